@@ -0,0 +1,148 @@
+// Локальная замена внешнего API с данными о песнях, чтобы сервис и e2e тесты работали без сети.
+// Настраивается переменными окружения:
+//
+//	MUSICINFO_PORT           - порт, по умолчанию 8082
+//	MUSICINFO_FIXTURES       - файл с песнями .json или .csv в формате каталога, по умолчанию test/fixtures/musicinfo.json
+//	MUSICINFO_LATENCY        - задержка ответа, например 200ms
+//	MUSICINFO_JITTER         - случайная добавка к задержке
+//	MUSICINFO_ERROR_RATE     - доля ответов 500 от 0 до 1
+//	MUSICINFO_NOT_FOUND_RATE - доля ответов 404 для известных песен от 0 до 1
+//	MUSICINFO_SEED           - начальное значение генератора случайных чисел для воспроизводимых ошибок
+//	MUSICINFO_RECORD         - файл, в который дописываются полученные запросы в формате JSON Lines
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"song/internal/musicinfo"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/realization"
+	"strconv"
+	"sync"
+	"time"
+)
+
+func main() {
+	err := logger.NewLogger()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	config, err := parseConfig()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	fixtures := getEnv("MUSICINFO_FIXTURES", "test/fixtures/musicinfo.json")
+	songs, err := realization.NewCatalogProvider(fixtures)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	record, closeRecord, err := recorder(os.Getenv("MUSICINFO_RECORD"))
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+	defer closeRecord()
+
+	port := getEnv("MUSICINFO_PORT", "8082")
+	logger.Logger.Info(fmt.Sprintf("Music info mock is listening on :%s with fixtures %s", port, fixtures))
+	err = http.ListenAndServe(":"+port, musicinfo.NewServer(*config, songs, record).Handler())
+	if err != nil {
+		logger.Logger.Error(fmt.Sprintf("Server working error - %v", err))
+	}
+}
+
+// parseConfig читает параметры поведения API из переменных окружения
+func parseConfig() (*musicinfo.Config, error) {
+	var config musicinfo.Config
+	var err error
+	if config.Latency, err = parseDuration("MUSICINFO_LATENCY"); err != nil {
+		return nil, err
+	}
+	if config.Jitter, err = parseDuration("MUSICINFO_JITTER"); err != nil {
+		return nil, err
+	}
+	if config.ErrorRate, err = parseRate("MUSICINFO_ERROR_RATE"); err != nil {
+		return nil, err
+	}
+	if config.NotFoundRate, err = parseRate("MUSICINFO_NOT_FOUND_RATE"); err != nil {
+		return nil, err
+	}
+	if value := os.Getenv("MUSICINFO_SEED"); value != "" {
+		if config.Seed, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid MUSICINFO_SEED - %v", err)
+		}
+	}
+
+	return &config, nil
+}
+
+// recorder возвращает функцию записи запросов в файл path, пустой путь - запросы не записываются
+func recorder(path string) (func(musicinfo.Request), func(), error) {
+	if path == "" {
+		return nil, func() {}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("record file opening error - %v", err)
+	}
+
+	var mu sync.Mutex
+	encoder := json.NewEncoder(file)
+	record := func(request musicinfo.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := encoder.Encode(request); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Request recording error - %v", err))
+		}
+	}
+
+	return record, func() { _ = file.Close() }, nil
+}
+
+// getEnv читает переменную окружения name, если она не задана - def
+func getEnv(name, def string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return def
+}
+
+// parseDuration читает длительность из переменной окружения name, если она не задана - 0
+func parseDuration(name string) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s - expected a non-negative duration, got %q", name, value)
+	}
+
+	return d, nil
+}
+
+// parseRate читает долю от 0 до 1 из переменной окружения name, если она не задана - 0
+func parseRate(name string) (float64, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("invalid %s - expected a number from 0 to 1, got %q", name, value)
+	}
+
+	return rate, nil
+}
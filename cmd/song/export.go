@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"song/internal/domain"
+	"song/internal/presentation/server"
+	"song/internal/services"
+	"strings"
+)
+
+// exportCommand - параметры подкоманды export:
+//
+//	song export [-format csv|json|ndjson] [-filter "group=Muse&sort=-releaseDate"] file
+type exportCommand struct {
+	path   string
+	filter *domain.LibFilter
+	format string
+}
+
+// parseExportCommand разбирает аргументы подкоманды export. Фильтр задается параметрами
+// запроса /lib/export, формат по умолчанию определяется по расширению файла
+func parseExportCommand(args []string) (*exportCommand, error) {
+	var format, filter string
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.StringVar(&format, "format", "", "file format: csv, json or ndjson, by default taken from the file extension")
+	flags.StringVar(&filter, "filter", "", "library filter in the query string format of GET /lib/export")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if flags.NArg() != 1 {
+		return nil, fmt.Errorf("usage: song export [-format csv|json|ndjson] [-filter query] file")
+	}
+	cmd := exportCommand{path: flags.Arg(0)}
+
+	query, err := url.ParseQuery(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -filter - %v", err)
+	}
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(cmd.path)), ".")
+		if format == "jsonl" {
+			format = domain.FormatNDJSON
+		}
+	}
+	query.Set("format", format)
+
+	cmd.filter, cmd.format, err = server.ParseExportFilter(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export parameters - %v", err)
+	}
+
+	return &cmd, nil
+}
+
+// run выгружает библиотеку во временный файл рядом с path и переименовывает его после успешной записи,
+// чтобы при ошибке не остался неполный файл
+func (c *exportCommand) run(service *services.SongService) (int, error) {
+	file, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("export file creating error - %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	count, err := service.ExportLib(context.Background(), *c.filter, c.format, file)
+	if err != nil {
+		return count, fmt.Errorf("export error - %v", err)
+	}
+	if err = file.Close(); err != nil {
+		return count, fmt.Errorf("export file writing error - %v", err)
+	}
+	if err = os.Rename(file.Name(), c.path); err != nil {
+		return count, fmt.Errorf("export file renaming error - %v", err)
+	}
+
+	return count, nil
+}
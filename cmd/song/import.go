@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"song/internal/domain"
+	"song/internal/services"
+	"strings"
+)
+
+// importCommand - параметры подкоманды import:
+//
+//	song import [-format csv|json|ndjson] [-dry-run] [-workers n] [-actor name] [-out report.json] file
+type importCommand struct {
+	path    string
+	format  string
+	dryRun  bool
+	workers int
+	actor   string
+	out     string
+}
+
+// parseImportCommand разбирает аргументы подкоманды import, формат по умолчанию определяется по расширению файла
+func parseImportCommand(args []string) (*importCommand, error) {
+	var cmd importCommand
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.StringVar(&cmd.format, "format", "", "file format: csv, json or ndjson, by default taken from the file extension")
+	flags.BoolVar(&cmd.dryRun, "dry-run", false, "only validate the file and report what would be created")
+	flags.IntVar(&cmd.workers, "workers", 0, "songs enriched concurrently, by default IMPORT_WORKERS")
+	flags.StringVar(&cmd.actor, "actor", "import", "author of the created songs in their history")
+	flags.StringVar(&cmd.out, "out", "", "report file, by default the report is printed to stdout")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if flags.NArg() != 1 {
+		return nil, fmt.Errorf("usage: song import [-format csv|json|ndjson] [-dry-run] [-workers n] [-actor name] [-out report.json] file")
+	}
+	cmd.path = flags.Arg(0)
+	if cmd.format == "" {
+		cmd.format = strings.TrimPrefix(strings.ToLower(filepath.Ext(cmd.path)), ".")
+		if cmd.format == "jsonl" {
+			cmd.format = domain.FormatNDJSON
+		}
+	}
+	if cmd.workers < 0 {
+		return nil, fmt.Errorf("invalid -workers - expected a non-negative number, got %d", cmd.workers)
+	}
+
+	return &cmd, nil
+}
+
+// run импортирует песни из файла и записывает отчет в формате JSON
+func (c *importCommand) run(service *services.ImportService) (*domain.ImportReport, error) {
+	file, err := os.Open(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("import file opening error - %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	rows, err := domain.DecodeSongs(file, c.format, domain.MaxImportRows)
+	if err != nil {
+		return nil, fmt.Errorf("import file %s reading error - %v", c.path, err)
+	}
+
+	report, err := service.Import(context.Background(), rows, c.dryRun, c.actor)
+	if err != nil {
+		return nil, fmt.Errorf("import error - %v", err)
+	}
+
+	out := os.Stdout
+	if c.out != "" {
+		out, err = os.Create(c.out)
+		if err != nil {
+			return nil, fmt.Errorf("report file creating error - %v", err)
+		}
+		defer func() {
+			_ = out.Close()
+		}()
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		return nil, fmt.Errorf("report writing error - %v", err)
+	}
+
+	return report, nil
+}
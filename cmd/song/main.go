@@ -3,21 +3,35 @@
 // @description This is a sample server for a song management application.
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token in the format "Bearer <token>"
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"fmt"
 	"net/url"
 	"os"
+	"song/internal/domain"
+	"song/internal/interfaces"
 	"song/internal/presentation/logger"
 	"song/internal/presentation/postgres"
 	"song/internal/presentation/realization"
 	"song/internal/presentation/server"
 	"song/internal/services"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// defaultRateLimitRules - ограничения частоты запросов, если RATE_LIMIT_RULES не задана
+const defaultRateLimitRules = "*=300/1m;ip:*=120/1m;GET /lib=60/1m;GET /lib/export=5/1m;POST /song=10/1m;POST /songs/import=5/1m;POST /auth/login=10/1m"
+
 func main() {
 	err := logger.NewLogger()
 	if err != nil {
@@ -25,6 +39,21 @@ func main() {
 		return
 	}
 
+	var importCmd *importCommand
+	var exportCmd *exportCommand
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			importCmd, err = parseImportCommand(os.Args[2:])
+		case "export":
+			exportCmd, err = parseExportCommand(os.Args[2:])
+		}
+		if err != nil {
+			logger.Logger.Error(err.Error())
+			os.Exit(2)
+		}
+	}
+
 	err = godotenv.Load("../../../.env")
 	if err != nil {
 		logger.Logger.Error(fmt.Sprintf("Failed to load env fail - %v", err))
@@ -47,6 +76,79 @@ func main() {
 
 	serverPort := os.Getenv("SERVER_PORT")
 
+	retention, err := parseDuration("TRASH_RETENTION", 30*24*time.Hour)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+	purgeInterval, err := parseDuration("TRASH_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	authConfig, err := parseAuthConfig()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	adminCredentials, err := parseAdminCredentials()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	rateLimitRules, err := parseRateLimitRules()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	trustedProxies := parseList("TRUSTED_PROXIES")
+
+	oidcConfig, err := parseOIDCConfig()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	enrichmentConfig, err := parseEnrichmentConfig(apiUrl, apiPort)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	jobConfig, err := parseEnrichmentJobConfig()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	importWorkers, err := parseInt("IMPORT_WORKERS", 4)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	cacheConfig, err := parseCacheConfig()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	localCacheConfig, err := parseLocalCacheConfig()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	cacheStoreConfig, err := parseCacheStoreConfig()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
 	_, err = postgres.CreateDB(host, port, user, password, name)
 	if err != nil {
 		logger.Logger.Error(fmt.Sprintf("Database creating error - %v", err))
@@ -60,17 +162,115 @@ func main() {
 	}
 
 	songRepo := realization.NewSongRepo()
-	cacheRepo := realization.NewConnectRedis(redisHost, redisPort, redisPass)
-	songService := services.NewSongService(cacheRepo, songRepo)
-
-	api, err := url.Parse(fmt.Sprintf("%s:%s", apiUrl, apiPort))
+	var redisRepo *realization.RedisRepo
+	var cacheRepo interfaces.CacheRepo
+	switch cacheStoreConfig.Backend {
+	case domain.CacheBackendMemory:
+		cacheRepo = realization.NewMemoryCache(cacheStoreConfig.MemoryEntries)
+	case domain.CacheBackendNone:
+		cacheRepo = realization.NewNoopCache()
+	default:
+		redisRepo = realization.NewConnectRedis(redisHost, redisPort, redisPass)
+		cacheRepo = redisRepo
+		if localCacheConfig.Entries > 0 {
+			cacheRepo = realization.NewTieredCache(redisRepo, *localCacheConfig)
+		}
+		cacheRepo = realization.NewResilientCache(cacheRepo, cacheStoreConfig.BreakerThreshold, cacheStoreConfig.BreakerCooldown)
+	}
+	songCache := services.NewSongCache(cacheRepo, *cacheConfig)
+	albumRepo := realization.NewAlbumRepo()
+	overrideRepo := realization.NewOverrideRepo()
+	metadataChain, err := parseMetadataChain(realization.NewEnrichmentClient(*enrichmentConfig), overrideRepo)
 	if err != nil {
-		logger.Logger.Error(fmt.Sprintf("Api url parsing error - %v", err))
+		logger.Logger.Error(err.Error())
 		return
 	}
+	importService := services.NewImportService(songCache, songRepo, albumRepo, realization.NewImportRepo(), metadataChain, importWorkers)
+	if importCmd != nil {
+		if importCmd.workers > 0 {
+			importService = services.NewImportService(songCache, songRepo, albumRepo, realization.NewImportRepo(), metadataChain, importCmd.workers)
+		}
+
+		report, err := importCmd.run(importService)
+		if err != nil {
+			logger.Logger.Error(err.Error())
+			os.Exit(1)
+		}
+		logger.Logger.Info(fmt.Sprintf("Import finished: %d created, %d skipped, %d failed of %d", report.Created, report.Skipped, report.Failed, report.Total))
+		if report.Failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	songService := services.NewSongService(songCache, songRepo, albumRepo, metadataChain)
+	if exportCmd != nil {
+		count, err := exportCmd.run(songService)
+		if err != nil {
+			logger.Logger.Error(err.Error())
+			os.Exit(1)
+		}
+		logger.Logger.Info(fmt.Sprintf("Exported %d songs to %s", count, exportCmd.path))
+		return
+	}
+
+	enrichmentService := services.NewEnrichmentService(songCache, realization.NewEnrichmentRepo(), albumRepo, metadataChain, *jobConfig)
+	artistService := services.NewArtistService(songCache, realization.NewArtistRepo())
+	albumService := services.NewAlbumService(songCache, albumRepo)
+	tagService := services.NewTagService(songCache, realization.NewTagRepo())
+	revisionService := services.NewRevisionService(songCache, realization.NewRevisionRepo())
+	trashService := services.NewTrashService(songCache, realization.NewTrashRepo(), retention)
+
+	var oidcVerifier interfaces.TokenVerifier
+	if oidcConfig != nil {
+		oidcVerifier = services.NewOIDCVerifier(*oidcConfig, nil)
+	}
+
+	authService := services.NewAuthService(realization.NewAuthRepo(), realization.NewAPIKeyRepo(), oidcVerifier, *authConfig)
+	if adminCredentials != nil {
+		admin, err := authService.BootstrapAdmin(*adminCredentials)
+		if err != nil {
+			logger.Logger.Error(fmt.Sprintf("Administrator creating error - %v", err))
+			return
+		}
+		logger.Logger.Info(fmt.Sprintf("User %s has the admin role", admin.Username))
+	}
 
-	srv := server.NewServer()
-	err = srv.Start(songService, api, serverPort)
+	var rateLimitService *services.RateLimitService
+	if len(rateLimitRules) > 0 {
+		// Без Redis счетчики запросов ведет каждый экземпляр сервиса
+		var rateLimiter interfaces.RateLimitRepo = realization.NewMemoryRateLimiter()
+		if redisRepo != nil {
+			rateLimiter = realization.NewRedisRateLimiter(redisRepo)
+		}
+		rateLimitService = services.NewRateLimitService(rateLimiter, realization.NewMemoryRateLimiter(), rateLimitRules)
+	}
+
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go trashService.RunPurge(backgroundCtx, purgeInterval)
+	go enrichmentService.Run(backgroundCtx)
+
+	srv, err := server.NewServer(trustedProxies)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+	err = srv.Start(server.Services{
+		Song:        songService,
+		Artist:      artistService,
+		Album:       albumService,
+		Tag:         tagService,
+		Revision:    revisionService,
+		Trash:       trashService,
+		Timing:      services.NewTimingService(realization.NewTimingRepo()),
+		Translation: services.NewTranslationService(songCache, songRepo, realization.NewTranslationRepo()),
+		Auth:        authService,
+		RateLimit:   rateLimitService,
+		Enrichment:  enrichmentService,
+		Override:    services.NewOverrideService(overrideRepo),
+		Import:      importService,
+	}, serverPort)
 	if err != nil {
 		logger.Logger.Error(fmt.Sprintf("Server working error - %v", err))
 	}
@@ -80,3 +280,332 @@ func main() {
 		logger.Logger.Error(fmt.Sprintf("Server stopping error - %v", err))
 	}
 }
+
+// parseEnrichmentConfig читает адрес и параметры устойчивости клиента API с данными о песнях
+func parseEnrichmentConfig(apiUrl, apiPort string) (*domain.EnrichmentConfig, error) {
+	api, err := url.Parse(fmt.Sprintf("%s:%s", apiUrl, apiPort))
+	if err != nil {
+		return nil, fmt.Errorf("api url parsing error - %v", err)
+	}
+
+	config := domain.EnrichmentConfig{BaseURL: api}
+	for _, d := range []struct {
+		name string
+		def  time.Duration
+		dst  *time.Duration
+	}{
+		{"API_TIMEOUT", 5 * time.Second, &config.Timeout},
+		{"API_RETRY_BACKOFF", 200 * time.Millisecond, &config.BackoffBase},
+		{"API_RETRY_BACKOFF_MAX", 2 * time.Second, &config.BackoffMax},
+		{"API_BREAKER_COOLDOWN", 30 * time.Second, &config.BreakerCooldown},
+	} {
+		if *d.dst, err = parseDuration(d.name, d.def); err != nil {
+			return nil, err
+		}
+	}
+	if config.Retries, err = parseInt("API_RETRIES", 2); err != nil {
+		return nil, err
+	}
+	if config.BreakerThreshold, err = parseInt("API_BREAKER_THRESHOLD", 5); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// parseMetadataChain собирает цепочку источников данных о песнях: ручные исправления, каталог
+// из файла METADATA_CATALOG, если он задан, и API. METADATA_PRECEDENCE задает порядок источников для полей
+func parseMetadataChain(api, overrides interfaces.MetadataProvider) (*services.MetadataChain, error) {
+	providers := []interfaces.MetadataProvider{overrides}
+	if path := os.Getenv("METADATA_CATALOG"); path != "" {
+		catalog, err := realization.NewCatalogProvider(path)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, catalog)
+	}
+	providers = append(providers, api)
+
+	names := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		names = append(names, provider.Name())
+	}
+	precedence, err := domain.ParseMetadataPrecedence(os.Getenv("METADATA_PRECEDENCE"), names)
+	if err != nil {
+		return nil, fmt.Errorf("invalid METADATA_PRECEDENCE - %v", err)
+	}
+
+	return services.NewMetadataChain(precedence, providers...), nil
+}
+
+// parseEnrichmentJobConfig читает параметры фонового получения данных песен
+func parseEnrichmentJobConfig() (*domain.EnrichmentJobConfig, error) {
+	var config domain.EnrichmentJobConfig
+	var err error
+	for _, d := range []struct {
+		name string
+		def  time.Duration
+		dst  *time.Duration
+	}{
+		{"ENRICHMENT_RETRY_BACKOFF", 10 * time.Second, &config.RetryBackoff},
+		{"ENRICHMENT_POLL_INTERVAL", 2 * time.Second, &config.PollInterval},
+		{"ENRICHMENT_LEASE", time.Minute, &config.Lease},
+	} {
+		if *d.dst, err = parseDuration(d.name, d.def); err != nil {
+			return nil, err
+		}
+	}
+	if config.Workers, err = parseInt("ENRICHMENT_WORKERS", 2); err != nil {
+		return nil, err
+	}
+	if config.MaxAttempts, err = parseInt("ENRICHMENT_MAX_ATTEMPTS", 5); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// parseCacheConfig читает время хранения значений в кэше, 0 отключает кэширование значений вида
+func parseCacheConfig() (*domain.CacheConfig, error) {
+	var config domain.CacheConfig
+	var err error
+	for _, d := range []struct {
+		name string
+		def  time.Duration
+		dst  *time.Duration
+	}{
+		{"CACHE_TEXT_TTL", 24 * time.Hour, &config.TextTTL},
+		{"CACHE_SONG_TTL", 10 * time.Minute, &config.SongTTL},
+		{"CACHE_LIB_TTL", time.Minute, &config.LibTTL},
+	} {
+		if *d.dst, err = parseDuration(d.name, d.def); err != nil {
+			return nil, err
+		}
+	}
+
+	return &config, nil
+}
+
+// parseLocalCacheConfig читает размер и время хранения кэша в памяти перед Redis
+func parseLocalCacheConfig() (*domain.LocalCacheConfig, error) {
+	var config domain.LocalCacheConfig
+	var err error
+	if config.Entries, err = parseInt("CACHE_LOCAL_ENTRIES", 1000); err != nil {
+		return nil, err
+	}
+	if config.TTL, err = parseDuration("CACHE_LOCAL_TTL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if config.Entries > 0 && config.TTL <= 0 {
+		return nil, fmt.Errorf("invalid CACHE_LOCAL_TTL - expected a positive duration when CACHE_LOCAL_ENTRIES is set")
+	}
+
+	return &config, nil
+}
+
+// parseCacheStoreConfig читает хранилище кэша: redis (по умолчанию), memory или none
+func parseCacheStoreConfig() (*domain.CacheStoreConfig, error) {
+	config := domain.CacheStoreConfig{Backend: os.Getenv("CACHE_BACKEND")}
+	switch config.Backend {
+	case "":
+		config.Backend = domain.CacheBackendRedis
+	case domain.CacheBackendRedis, domain.CacheBackendMemory, domain.CacheBackendNone:
+	default:
+		return nil, fmt.Errorf("invalid CACHE_BACKEND - expected %s, %s or %s, got %q",
+			domain.CacheBackendRedis, domain.CacheBackendMemory, domain.CacheBackendNone, config.Backend)
+	}
+
+	var err error
+	if config.MemoryEntries, err = parseInt("CACHE_MEMORY_ENTRIES", 10000); err != nil {
+		return nil, err
+	}
+	if config.Backend == domain.CacheBackendMemory && config.MemoryEntries == 0 {
+		return nil, fmt.Errorf("invalid CACHE_MEMORY_ENTRIES - expected a positive number for the memory cache")
+	}
+	if config.BreakerThreshold, err = parseInt("CACHE_BREAKER_THRESHOLD", 5); err != nil {
+		return nil, err
+	}
+	if config.BreakerCooldown, err = parseDuration("CACHE_BREAKER_COOLDOWN", 10*time.Second); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// parseList читает список через запятую из переменной окружения name, пустые элементы пропускаются
+func parseList(name string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// parseInt читает неотрицательное число из переменной окружения name, если она не задана - def
+func parseInt(name string, def int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s - expected a non-negative number, got %q", name, value)
+	}
+
+	return n, nil
+}
+
+// parseDuration читает длительность из переменной окружения name, если она не задана - def
+func parseDuration(name string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s - %v", name, err)
+	}
+
+	return d, nil
+}
+
+// parseAuthConfig читает настройки аутентификации из переменных окружения.
+// Если ключ подписи не задан, генерируется случайный и токены не переживают перезапуск
+func parseAuthConfig() (*domain.AuthConfig, error) {
+	accessTTL, err := parseDuration("JWT_ACCESS_TTL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	refreshTTL, err := parseDuration("JWT_REFRESH_TTL", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	protectReads := false
+	if value := os.Getenv("AUTH_PROTECT_READS"); value != "" {
+		protectReads, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTH_PROTECT_READS - %v", err)
+		}
+	}
+
+	defaultRole := os.Getenv("AUTH_DEFAULT_ROLE")
+	if defaultRole == "" {
+		defaultRole = domain.RoleViewer
+	}
+	if err := domain.ValidateRole(defaultRole); err != nil {
+		return nil, fmt.Errorf("invalid AUTH_DEFAULT_ROLE - %v", err)
+	}
+
+	registration := os.Getenv("AUTH_REGISTRATION")
+	switch registration {
+	case "":
+		registration = domain.RegistrationOpen
+	case domain.RegistrationOpen, domain.RegistrationAdmin, domain.RegistrationClosed:
+	default:
+		return nil, fmt.Errorf("invalid AUTH_REGISTRATION - expected %s, %s or %s, got %q",
+			domain.RegistrationOpen, domain.RegistrationAdmin, domain.RegistrationClosed, registration)
+	}
+
+	secret := []byte(os.Getenv("JWT_SECRET"))
+	if len(secret) == 0 {
+		logger.Logger.Warn("JWT_SECRET is not set, using a random secret, tokens will not survive a restart")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("secret generating error - %v", err)
+		}
+	}
+
+	return &domain.AuthConfig{
+		Secret:       secret,
+		AccessTTL:    accessTTL,
+		RefreshTTL:   refreshTTL,
+		ProtectReads: protectReads,
+		DefaultRole:  defaultRole,
+		Registration: registration,
+	}, nil
+}
+
+// parseAdminCredentials читает имя и пароль администратора, создаваемого при запуске,
+// если они не заданы - nil
+func parseAdminCredentials() (*domain.Credentials, error) {
+	username := os.Getenv("ADMIN_USERNAME")
+	password := os.Getenv("ADMIN_PASSWORD")
+	if username == "" && password == "" {
+		return nil, nil
+	}
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("ADMIN_USERNAME and ADMIN_PASSWORD must be set together")
+	}
+
+	return &domain.Credentials{Username: username, Password: password}, nil
+}
+
+// parseOIDCConfig читает настройки внешнего OIDC-провайдера, если издатель не задан - nil
+func parseOIDCConfig() (*domain.OIDCConfig, error) {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil, nil
+	}
+
+	jwksTTL, err := parseDuration("OIDC_JWKS_TTL", services.DefaultJWKSTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultRole := os.Getenv("OIDC_DEFAULT_ROLE")
+	if defaultRole == "" {
+		defaultRole = domain.RoleViewer
+	}
+	if err := domain.ValidateRole(defaultRole); err != nil {
+		return nil, fmt.Errorf("invalid OIDC_DEFAULT_ROLE - %v", err)
+	}
+
+	roleMap := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv("OIDC_ROLE_MAP"), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		claim, role, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid OIDC_ROLE_MAP - expected claim=role pairs, got %q", pair)
+		}
+		role = strings.TrimSpace(role)
+		if err := domain.ValidateRole(role); err != nil {
+			return nil, fmt.Errorf("invalid OIDC_ROLE_MAP - %v", err)
+		}
+		roleMap[strings.TrimSpace(claim)] = role
+	}
+
+	return &domain.OIDCConfig{
+		Issuer:      issuer,
+		Audience:    os.Getenv("OIDC_AUDIENCE"),
+		RoleClaim:   os.Getenv("OIDC_ROLE_CLAIM"),
+		RoleMap:     roleMap,
+		DefaultRole: defaultRole,
+		JWKSTTL:     jwksTTL,
+	}, nil
+}
+
+// parseRateLimitRules читает правила ограничения частоты запросов, off отключает ограничение
+func parseRateLimitRules() (domain.RateLimitRules, error) {
+	value := os.Getenv("RATE_LIMIT_RULES")
+	if value == "" {
+		value = defaultRateLimitRules
+	}
+	if value == "off" {
+		return nil, nil
+	}
+
+	rules, err := domain.ParseRateLimitRules(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_RULES - %v", err)
+	}
+
+	return rules, nil
+}
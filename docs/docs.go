@@ -5,7 +5,7 @@ package docs
 import "github.com/swaggo/swag/v2"
 
 const docTemplate = `{
-    "schemes": {{ marshal .Schemes }},"swagger":"2.0","info":{"description":"{{escape .Description}}","title":"{{.Title}}","contact":{},"version":"{{.Version}}"},"host":"{{.Host}}","basePath":"{{.BasePath}}","paths":{"/lib":{"get":{"description":"Get songs library","consumes":["application/json"],"produces":["application/json"],"tags":["library"],"summary":"Get library","parameters":[{"type":"string","description":"Song name","name":"song","in":"query"},{"type":"string","description":"Group name","name":"group","in":"query"},{"type":"string","description":"Release date in format dd.mm.yyyy","name":"releaseDate","in":"query"},{"type":"string","description":"Song text","name":"text","in":"query"},{"type":"string","description":"Link","name":"link","in":"query"},{"type":"integer","description":"Page number","name":"page","in":"query"}],"responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.Song"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/song":{"post":{"description":"Create a new song","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Create song","parameters":[{"description":"Song details","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.SongDataByUser"}}],"responses":{"200":{"description":"OK","schema":{"type":"object","additionalProperties":{"type":"integer"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"delete":{"description":"Delete a song by ID","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Delete song","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"query","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"patch":{"description":"Change song details by ID","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Change song","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"query","required":true},{"description":"Song details","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.Song"}}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/text":{"get":{"description":"Get the text of a song","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Get song text","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"query","required":true},{"type":"integer","description":"Page number","name":"page","in":"query"}],"responses":{"200":{"description":"OK","schema":{"type":"string"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}}},"definitions":{"domain.Song":{"type":"object","properties":{"group":{"description":"Группа или исполнитель","type":"string"},"id":{"description":"Идентификатор песни","type":"integer"},"link":{"description":"Ссылка на песню","type":"string"},"name":{"description":"Название песни","type":"string"},"releaseDate":{"description":"Дата выпуска песни","type":"string"},"text":{"description":"Текст песни","type":"string"}}},"domain.SongDataByUser":{"type":"object","properties":{"group":{"description":"Группа или исполнитель","type":"string"},"song":{"description":"Название песни","type":"string"}}}}}`
+    "schemes": {{ marshal .Schemes }},"swagger":"2.0","info":{"description":"{{escape .Description}}","title":"{{.Title}}","contact":{},"version":"{{.Version}}"},"host":"{{.Host}}","basePath":"{{.BasePath}}","paths":{"/albums":{"get":{"description":"Get albums list without tracks","consumes":["application/json"],"produces":["application/json"],"tags":["album"],"summary":"Get albums","parameters":[{"type":"integer","description":"Artist ID","name":"artistId","in":"query"},{"type":"integer","description":"Page number","name":"page","in":"query"}],"responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.Album"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"post":{"security":[{"BearerAuth":[]}],"description":"Create a new album, tracks are numbered in the order of the list","consumes":["application/json"],"produces":["application/json"],"tags":["album"],"summary":"Create album","parameters":[{"description":"Album details","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.Album"}}],"responses":{"200":{"description":"OK","schema":{"type":"object","additionalProperties":{"type":"integer"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"409":{"description":"Conflict","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/albums/{id}":{"get":{"description":"Get an album with its ordered track list","consumes":["application/json"],"produces":["application/json"],"tags":["album"],"summary":"Get album","parameters":[{"type":"integer","description":"Album ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.Album"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"delete":{"security":[{"BearerAuth":[]}],"description":"Delete an album, its songs stay in the library","consumes":["application/json"],"produces":["application/json"],"tags":["album"],"summary":"Delete album","parameters":[{"type":"integer","description":"Album ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"patch":{"security":[{"BearerAuth":[]}],"description":"Change album title, artist or release date","consumes":["application/json"],"produces":["application/json"],"tags":["album"],"summary":"Change album","parameters":[{"type":"integer","description":"Album ID","name":"id","in":"path","required":true},{"description":"Album details","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.Album"}}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"409":{"description":"Conflict","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/albums/{id}/tracks":{"put":{"security":[{"BearerAuth":[]}],"description":"Replace the album track list, tracks are numbered in the order of the list","consumes":["application/json"],"produces":["application/json"],"tags":["album"],"summary":"Reorder album tracks","parameters":[{"type":"integer","description":"Album ID","name":"id","in":"path","required":true},{"description":"Song IDs in track order","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.AlbumTracks"}}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/api-keys":{"get":{"security":[{"BearerAuth":[]}],"description":"Get active API keys of the current user, the keys themselves are not returned","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Get API keys","responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.APIKey"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"post":{"security":[{"BearerAuth":[]}],"description":"Create an API key with scopes songs:read, songs:write, songs:delete or admin, the scopes must be granted to the user.\nThe key is returned only once, pass it in the X-API-Key header or as a Bearer token","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Create API key","parameters":[{"description":"Key name and scopes","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.APIKeyByUser"}}],"responses":{"201":{"description":"Created","schema":{"$ref":"#/definitions/domain.CreatedAPIKey"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/api-keys/{id}":{"delete":{"security":[{"BearerAuth":[]}],"description":"Revoke an API key of the current user, administrators can revoke any key","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Revoke API key","parameters":[{"type":"integer","description":"Key ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/artists":{"get":{"description":"Get artists list ordered by name","consumes":["application/json"],"produces":["application/json"],"tags":["artist"],"summary":"Get artists","parameters":[{"type":"integer","description":"Page number","name":"page","in":"query"}],"responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.Artist"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"post":{"security":[{"BearerAuth":[]}],"description":"Create a new artist","consumes":["application/json"],"produces":["application/json"],"tags":["artist"],"summary":"Create artist","parameters":[{"description":"Artist details","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.Artist"}}],"responses":{"200":{"description":"OK","schema":{"type":"object","additionalProperties":{"type":"integer"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"409":{"description":"Conflict","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/artists/{id}":{"get":{"description":"Get an artist by ID","consumes":["application/json"],"produces":["application/json"],"tags":["artist"],"summary":"Get artist","parameters":[{"type":"integer","description":"Artist ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.Artist"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"delete":{"security":[{"BearerAuth":[]}],"description":"Delete an artist without songs","consumes":["application/json"],"produces":["application/json"],"tags":["artist"],"summary":"Delete artist","parameters":[{"type":"integer","description":"Artist ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"409":{"description":"Conflict","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"patch":{"security":[{"BearerAuth":[]}],"description":"Rename an artist, the new name is applied to all of its songs","consumes":["application/json"],"produces":["application/json"],"tags":["artist"],"summary":"Change artist","parameters":[{"type":"integer","description":"Artist ID","name":"id","in":"path","required":true},{"description":"Artist details","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.Artist"}}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"409":{"description":"Conflict","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/auth/login":{"post":{"description":"Check the password and issue an access token and a refresh token","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Login","parameters":[{"description":"Username and password","name":"credentials","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.Credentials"}}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.TokenPair"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/auth/logout":{"post":{"security":[{"BearerAuth":[]}],"description":"Revoke the current session","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Logout","responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/auth/logout-all":{"post":{"security":[{"BearerAuth":[]}],"description":"Revoke all sessions of the current user","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Logout everywhere","responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/auth/me":{"get":{"security":[{"BearerAuth":[]}],"description":"Get the user the access token belongs to, for OIDC tokens the user is taken from the token claims","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Current user","responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.User"}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/auth/refresh":{"post":{"description":"Exchange a refresh token for a new token pair. The refresh token can be used once, reusing it revokes the session","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Refresh tokens","parameters":[{"description":"Refresh token","name":"request","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.RefreshRequest"}}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.TokenPair"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/auth/register":{"post":{"security":[{"BearerAuth":[]}],"description":"Create a user account with the default role. Depending on the registration mode anyone can register,\nonly an administrator can register users or registration is disabled","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Register","parameters":[{"description":"Username and password","name":"credentials","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.Credentials"}}],"responses":{"201":{"description":"Created","schema":{"$ref":"#/definitions/domain.User"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"409":{"description":"Conflict","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/cache/stats":{"get":{"security":[{"BearerAuth":[]}],"description":"Get hit and miss counters of every cache tier since the service start: the in-process cache and Redis","produces":["application/json"],"tags":["cache"],"summary":"Get cache statistics","responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.CacheTierStats"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/genres":{"get":{"description":"Get genres with song counts","consumes":["application/json"],"produces":["application/json"],"tags":["tag"],"summary":"Get genres","responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.Tag"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/jobs/{id}":{"get":{"description":"Get the state of a background song enrichment job: pending, succeeded or failed","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Get enrichment job","parameters":[{"type":"integer","description":"Job ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.EnrichmentJob"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/lib":{"get":{"description":"Get songs library. Every song contains only the fields from the fields parameter,\nby default id, name, group, artistId, releaseDate, link and enrichment_status, plus rank and snippet with q.\nWithout page the response is an envelope with the songs in items and keyset cursors.\nWith page the response is a plain JSON array of the songs without the envelope, for old clients","consumes":["application/json"],"produces":["application/json"],"tags":["library"],"summary":"Get library","parameters":[{"type":"string","description":"Song name","name":"song","in":"query"},{"type":"string","description":"Group name","name":"group","in":"query"},{"type":"integer","description":"Artist ID","name":"artistId","in":"query"},{"type":"integer","description":"Album ID","name":"albumId","in":"query"},{"type":"string","description":"Comma-separated tag names","name":"tag","in":"query"},{"type":"string","description":"Tag match mode: any (default) or all","name":"tagMode","in":"query"},{"type":"string","description":"Comma-separated genre names","name":"genre","in":"query"},{"type":"string","description":"Genre match mode: any (default) or all","name":"genreMode","in":"query"},{"type":"string","description":"Release date in format dd.mm.yyyy","name":"releaseDate","in":"query"},{"type":"string","description":"Release date range start in format dd.mm.yyyy, inclusive","name":"releaseDateFrom","in":"query"},{"type":"string","description":"Release date range end in format dd.mm.yyyy, inclusive","name":"releaseDateTo","in":"query"},{"type":"string","description":"Song text","name":"text","in":"query"},{"type":"string","description":"Link","name":"link","in":"query"},{"type":"string","description":"Song name match mode: substring (default) or exact","name":"songMatch","in":"query"},{"type":"string","description":"Group name match mode: substring (default) or exact","name":"groupMatch","in":"query"},{"type":"string","description":"Song text match mode: substring (default) or exact","name":"textMatch","in":"query"},{"type":"string","description":"Link match mode: substring (default) or exact","name":"linkMatch","in":"query"},{"type":"string","description":"Comma-separated sort fields, prefix - for descending order: id, song_name, group_name, releaseDate","name":"sort","in":"query"},{"type":"string","description":"Full-text search query over titles, groups and lyrics, results are ordered by relevance","name":"q","in":"query"},{"type":"string","description":"Full-text search language: ru (default) or en","name":"lang","in":"query"},{"type":"string","description":"Cursor from next_cursor or prev_cursor","name":"cursor","in":"query"},{"type":"integer","description":"Page size, 20 by default, 100 at most","name":"limit","in":"query"},{"type":"boolean","description":"Count songs matching the filter","name":"total","in":"query"},{"type":"string","description":"Comma-separated song fields to return: id, name, group, artistId, releaseDate, text, link, enrichment_status, sources. Text and sources are omitted by default","name":"fields","in":"query"},{"type":"integer","description":"Page number (deprecated, use cursor)","name":"page","in":"query"}],"responses":{"200":{"description":"Envelope without page, a plain array of items with page","schema":{"$ref":"#/definitions/server.libResponse"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"429":{"description":"Too Many Requests","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/lib/export":{"get":{"description":"Stream the whole library or the songs matching the same filters as /lib as CSV, a JSON array or NDJSON.\nSongs are written while they are read from the database. If the export fails midway the response is cut short","produces":["text/csv","application/json","application/x-ndjson"],"tags":["library"],"summary":"Export library","parameters":[{"type":"string","description":"File format: csv (default), json or ndjson","name":"format","in":"query"},{"type":"string","description":"Song name","name":"song","in":"query"},{"type":"string","description":"Group name","name":"group","in":"query"},{"type":"integer","description":"Artist ID","name":"artistId","in":"query"},{"type":"integer","description":"Album ID","name":"albumId","in":"query"},{"type":"string","description":"Comma-separated tag names","name":"tag","in":"query"},{"type":"string","description":"Tag match mode: any (default) or all","name":"tagMode","in":"query"},{"type":"string","description":"Comma-separated genre names","name":"genre","in":"query"},{"type":"string","description":"Genre match mode: any (default) or all","name":"genreMode","in":"query"},{"type":"string","description":"Release date in format dd.mm.yyyy","name":"releaseDate","in":"query"},{"type":"string","description":"Release date range start in format dd.mm.yyyy, inclusive","name":"releaseDateFrom","in":"query"},{"type":"string","description":"Release date range end in format dd.mm.yyyy, inclusive","name":"releaseDateTo","in":"query"},{"type":"string","description":"Song text","name":"text","in":"query"},{"type":"string","description":"Link","name":"link","in":"query"},{"type":"string","description":"Comma-separated sort fields, prefix - for descending order: id, song_name, group_name, releaseDate","name":"sort","in":"query"},{"type":"string","description":"Full-text search query over titles, groups and lyrics","name":"q","in":"query"},{"type":"string","description":"Full-text search language: ru (default) or en","name":"lang","in":"query"},{"type":"string","description":"Comma-separated song fields to export. By default all fields except enrichment_status and sources","name":"fields","in":"query"}],"responses":{"200":{"description":"OK","schema":{"type":"string"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"429":{"description":"Too Many Requests","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/metadata/overrides":{"get":{"description":"Get manual corrections of song metadata. They take precedence over the catalogue and the song info API when new songs are created","consumes":["application/json"],"produces":["application/json"],"tags":["metadata"],"summary":"Get metadata overrides","parameters":[{"type":"integer","description":"Page number","name":"page","in":"query"}],"responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.MetadataOverride"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"put":{"security":[{"BearerAuth":[]}],"description":"Create or replace the manual correction of a song found by group and song name, case-insensitive. Empty fields are taken from other providers","consumes":["application/json"],"produces":["application/json"],"tags":["metadata"],"summary":"Set metadata override","parameters":[{"description":"Override details","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.MetadataOverride"}}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.MetadataOverride"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/metadata/overrides/{id}":{"delete":{"security":[{"BearerAuth":[]}],"description":"Delete a manual correction of song metadata. Songs that already got its data are not changed","consumes":["application/json"],"produces":["application/json"],"tags":["metadata"],"summary":"Delete metadata override","parameters":[{"type":"integer","description":"Override ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/song":{"post":{"security":[{"BearerAuth":[]}],"description":"Create a new song with release date, text and link from the song info API. 404 - the API does not know the song, 502/504 - the API failed or timed out, 503 - requests to the API are paused after repeated failures.\nWith async=true the song is stored at once with enrichment_status pending and the API is queried by a background job, the response is 202 with the job and its URL in Location","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Create song","parameters":[{"type":"boolean","description":"Query the song info API in the background","name":"async","in":"query"},{"description":"Song details","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.SongDataByUser"}}],"responses":{"200":{"description":"OK","schema":{"type":"object","additionalProperties":{"type":"integer"}}},"202":{"description":"Accepted","schema":{"$ref":"#/definitions/domain.EnrichmentJob"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"429":{"description":"Too Many Requests","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}},"502":{"description":"Bad Gateway","schema":{"type":"object","additionalProperties":{"type":"string"}}},"503":{"description":"Service Unavailable","schema":{"type":"object","additionalProperties":{"type":"string"}}},"504":{"description":"Gateway Timeout","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"delete":{"security":[{"BearerAuth":[]}],"description":"Move a song to the trash by ID. It can be restored until the retention period expires","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Delete song","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"query","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"patch":{"security":[{"BearerAuth":[]}],"description":"Change song details by ID","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Change song","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"query","required":true},{"description":"Song details","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.Song"}}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/import":{"post":{"security":[{"BearerAuth":[]}],"description":"Create songs from a CSV file with a header row (group, song or name and optional releaseDate, text, link, album), a JSON array or NDJSON.\nMissing song data is requested from the metadata providers concurrently, data from the file takes precedence.\nSongs repeated in the file or already in the library are skipped. The report lists the result of every row.\nAt most 1000 songs are accepted, larger files are imported with the import command.\nIf the request is canceled, no more songs are created and the remaining rows fail","consumes":["text/csv","application/json","application/x-ndjson"],"produces":["application/json"],"tags":["song"],"summary":"Import songs","parameters":[{"type":"string","description":"File format: csv, json or ndjson, by default taken from Content-Type","name":"format","in":"query"},{"type":"boolean","description":"Only validate the file and report what would be created","name":"dry_run","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.ImportReport"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"413":{"description":"Request Entity Too Large","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}":{"get":{"description":"Get a song with all its fields by ID","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Get song","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.Song"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}/diff":{"get":{"description":"Get field-level differences between two song revisions, the text is compared line by line","consumes":["application/json"],"produces":["application/json"],"tags":["revision"],"summary":"Diff song revisions","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true},{"type":"integer","description":"Source revision ID","name":"from","in":"query","required":true},{"type":"integer","description":"Target revision ID","name":"to","in":"query","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.RevisionDiff"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}/lrc":{"get":{"description":"Get the time-synced lyrics of a song in LRC format","consumes":["application/json"],"produces":["text/plain"],"tags":["timing"],"summary":"Export LRC","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"type":"string"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"put":{"security":[{"BearerAuth":[]}],"description":"Replace the time-synced lyrics of a song with lines from an LRC file. Enhanced word tags and the offset tag are supported","consumes":["text/plain"],"produces":["application/json"],"tags":["timing"],"summary":"Import LRC","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true},{"description":"LRC file","name":"body","in":"body","required":true,"schema":{"type":"string"}}],"responses":{"200":{"description":"OK","schema":{"type":"object","additionalProperties":{"type":"integer"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}/lrc/at":{"get":{"description":"Get the line active at a playback position and the lines that follow it","consumes":["application/json"],"produces":["application/json"],"tags":["timing"],"summary":"Get line at position","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true},{"type":"integer","description":"Playback position in milliseconds","name":"t","in":"query","required":true},{"type":"integer","description":"Number of following lines, 3 by default, 50 at most","name":"next","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.LinePosition"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}/lrc/stream":{"get":{"description":"Server-sent events stream pushing \"line\" events in real time from a start offset. The line active at the offset is sent at once, the stream ends with an \"end\" event","consumes":["application/json"],"produces":["text/event-stream"],"tags":["timing"],"summary":"Stream lines","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true},{"type":"integer","description":"Start offset in milliseconds","name":"from","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.TimedLine"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}/revisions":{"get":{"description":"Get the change history of a song, oldest first. Snapshots are omitted","consumes":["application/json"],"produces":["application/json"],"tags":["revision"],"summary":"Get song revisions","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.Revision"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}/revisions/{rev}":{"get":{"description":"Get a song revision with the full song snapshot","consumes":["application/json"],"produces":["application/json"],"tags":["revision"],"summary":"Get song revision","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true},{"type":"integer","description":"Revision ID","name":"rev","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.Revision"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}/revisions/{rev}/revert":{"post":{"security":[{"BearerAuth":[]}],"description":"Restore the song to the state of a revision. The revert is recorded as a new revision","consumes":["application/json"],"produces":["application/json"],"tags":["revision"],"summary":"Revert song","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true},{"type":"integer","description":"Revision ID","name":"rev","in":"path","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"409":{"description":"Conflict","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}/translations":{"get":{"description":"Get BCP-47 codes of the languages the song text is translated to","consumes":["application/json"],"produces":["application/json"],"tags":["translation"],"summary":"Get translation languages","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"type":"array","items":{"type":"string"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/songs/{id}/translations/{lang}":{"get":{"description":"Get the translation of the song text to a language with its sections","consumes":["application/json"],"produces":["application/json"],"tags":["translation"],"summary":"Get translation","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true},{"type":"string","description":"BCP-47 language code","name":"lang","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.Translation"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"put":{"security":[{"BearerAuth":[]}],"description":"Create or replace the translation of the song text. The translation must have as many sections as the original, section types are taken from the original","consumes":["application/json"],"produces":["application/json"],"tags":["translation"],"summary":"Set translation","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true},{"type":"string","description":"BCP-47 language code","name":"lang","in":"path","required":true},{"description":"Translation text","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.TranslationByUser"}}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.Translation"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}},"delete":{"security":[{"BearerAuth":[]}],"description":"Delete the translation of the song text to a language","consumes":["application/json"],"produces":["application/json"],"tags":["translation"],"summary":"Delete translation","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true},{"type":"string","description":"BCP-47 language code","name":"lang","in":"path","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/tags":{"get":{"description":"Get tags with song counts","consumes":["application/json"],"produces":["application/json"],"tags":["tag"],"summary":"Get tags","responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.Tag"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/tags/attach":{"post":{"security":[{"BearerAuth":[]}],"description":"Attach tags and genres to songs in bulk, missing tags and genres are created","consumes":["application/json"],"produces":["application/json"],"tags":["tag"],"summary":"Attach tags","parameters":[{"description":"Songs with tags and genres","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.TagBinding"}}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/tags/detach":{"post":{"security":[{"BearerAuth":[]}],"description":"Detach tags and genres from songs in bulk","consumes":["application/json"],"produces":["application/json"],"tags":["tag"],"summary":"Detach tags","parameters":[{"description":"Songs with tags and genres","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.TagBinding"}}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/text":{"get":{"description":"Get a section of the song text with its type, index and the total number of sections.\nWith lang the section of the translation is returned, falling back to the original if there is none.\nWith view=side-by-side the original and translated sections are returned together as domain.SideBySide","consumes":["application/json"],"produces":["application/json"],"tags":["song"],"summary":"Get song text","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"query","required":true},{"type":"integer","description":"Section number, starting from 1","name":"page","in":"query"},{"type":"string","description":"BCP-47 language code of the translation","name":"lang","in":"query"},{"type":"string","description":"single (default) or side-by-side, side-by-side requires lang","name":"view","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/domain.TextSection"}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/trash":{"get":{"description":"Get deleted songs waiting in the trash, most recently deleted first","consumes":["application/json"],"produces":["application/json"],"tags":["trash"],"summary":"Get trash","parameters":[{"type":"integer","description":"Page number","name":"page","in":"query"}],"responses":{"200":{"description":"OK","schema":{"type":"array","items":{"$ref":"#/definitions/domain.TrashedSong"}}},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/trash/{id}":{"delete":{"security":[{"BearerAuth":[]}],"description":"Permanently delete a song from the trash. The revision history is kept","consumes":["application/json"],"produces":["application/json"],"tags":["trash"],"summary":"Purge song","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/trash/{id}/restore":{"post":{"security":[{"BearerAuth":[]}],"description":"Move a song from the trash back to the library","consumes":["application/json"],"produces":["application/json"],"tags":["trash"],"summary":"Restore song","parameters":[{"type":"integer","description":"Song ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}},"/users/{id}/role":{"put":{"security":[{"BearerAuth":[]}],"description":"Set the role of a user: viewer (songs:read), editor (songs:read, songs:write, songs:delete) or admin (all scopes).\nRequires the admin scope","consumes":["application/json"],"produces":["application/json"],"tags":["auth"],"summary":"Set user role","parameters":[{"type":"integer","description":"User ID","name":"id","in":"path","required":true},{"description":"New role","name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/domain.RoleByUser"}}],"responses":{"200":{"description":"OK"},"400":{"description":"Bad Request","schema":{"type":"object","additionalProperties":{"type":"string"}}},"401":{"description":"Unauthorized","schema":{"type":"object","additionalProperties":{"type":"string"}}},"403":{"description":"Forbidden","schema":{"type":"object","additionalProperties":{"type":"string"}}},"404":{"description":"Not Found","schema":{"type":"object","additionalProperties":{"type":"string"}}},"500":{"description":"Internal Server Error","schema":{"type":"object","additionalProperties":{"type":"string"}}}}}}},"definitions":{"domain.APIKey":{"type":"object","properties":{"createdAt":{"description":"Время создания","type":"string"},"id":{"description":"Идентификатор ключа","type":"integer"},"lastUsedAt":{"description":"Время последнего использования","type":"string"},"name":{"description":"Название ключа","type":"string"},"prefix":{"description":"Начало ключа для его узнавания","type":"string"},"scopes":{"description":"Разрешения ключа","type":"array","items":{"type":"string"}},"userId":{"description":"Владелец ключа","type":"integer"}}},"domain.APIKeyByUser":{"type":"object","properties":{"name":{"description":"Название ключа","type":"string"},"scopes":{"description":"Разрешения ключа","type":"array","items":{"type":"string"}}}},"domain.Album":{"type":"object","properties":{"artist":{"description":"Название исполнителя, только для чтения","type":"string"},"artistId":{"description":"Идентификатор исполнителя","type":"integer"},"id":{"description":"Идентификатор альбома","type":"integer"},"releaseDate":{"description":"Дата выпуска альбома","type":"string"},"title":{"description":"Название альбома","type":"string"},"tracks":{"description":"Композиции альбома по порядку","type":"array","items":{"$ref":"#/definitions/domain.Track"}}}},"domain.AlbumTracks":{"type":"object","properties":{"songs":{"description":"Идентификаторы песен в порядке следования","type":"array","items":{"type":"integer"}}}},"domain.Artist":{"type":"object","properties":{"id":{"description":"Идентификатор исполнителя","type":"integer"},"name":{"description":"Название группы или исполнителя","type":"string"}}},"domain.CacheTierStats":{"type":"object","properties":{"entries":{"description":"Текущее число значений","type":"integer"},"evictions":{"description":"Количество значений, вытесненных из-за ограничения размера","type":"integer"},"hits":{"description":"Количество найденных значений","type":"integer"},"misses":{"description":"Количество отсутствующих значений","type":"integer"},"tier":{"description":"Уровень кэша","type":"string"}}},"domain.CreatedAPIKey":{"type":"object","properties":{"createdAt":{"description":"Время создания","type":"string"},"id":{"description":"Идентификатор ключа","type":"integer"},"key":{"description":"Ключ","type":"string"},"lastUsedAt":{"description":"Время последнего использования","type":"string"},"name":{"description":"Название ключа","type":"string"},"prefix":{"description":"Начало ключа для его узнавания","type":"string"},"scopes":{"description":"Разрешения ключа","type":"array","items":{"type":"string"}},"userId":{"description":"Владелец ключа","type":"integer"}}},"domain.Credentials":{"type":"object","properties":{"password":{"description":"Пароль","type":"string"},"username":{"description":"Имя пользователя","type":"string"}}},"domain.EnrichmentJob":{"type":"object","properties":{"attempts":{"description":"Число выполненных попыток","type":"integer"},"createdAt":{"description":"Время создания","type":"string"},"id":{"description":"Идентификатор задачи","type":"integer"},"lastError":{"description":"Ошибка последней попытки","type":"string"},"nextAttemptAt":{"description":"Время следующей попытки","type":"string"},"songId":{"description":"Идентификатор песни","type":"integer"},"status":{"description":"Состояние: pending, succeeded или failed","type":"string"},"updatedAt":{"description":"Время изменения","type":"string"}}},"domain.FieldDiff":{"type":"object","properties":{"field":{"description":"Имя поля","type":"string"},"lines":{"description":"Построчные различия для текста","type":"array","items":{"$ref":"#/definitions/domain.LineDiff"}},"new":{"description":"Значение в конечной ревизии"},"old":{"description":"Значение в исходной ревизии"}}},"domain.ImportReport":{"type":"object","properties":{"created":{"description":"Число созданных песен, при пробном импорте - готовых к созданию","type":"integer"},"dryRun":{"description":"Пробный импорт без изменения библиотеки","type":"boolean"},"failed":{"description":"Число ошибок","type":"integer"},"rows":{"description":"Результаты по строкам в порядке файла","type":"array","items":{"$ref":"#/definitions/domain.ImportResult"}},"skipped":{"description":"Число пропущенных повторов","type":"integer"},"total":{"description":"Число строк","type":"integer"}}},"domain.ImportResult":{"type":"object","properties":{"group":{"description":"Группа или исполнитель","type":"string"},"reason":{"description":"Причина пропуска или ошибки","type":"string"},"row":{"description":"Номер строки в файле","type":"integer"},"song":{"description":"Название песни","type":"string"},"songId":{"description":"Идентификатор созданной песни","type":"integer"},"status":{"description":"created, would_create, skipped или failed","type":"string"}}},"domain.LineDiff":{"type":"object","properties":{"op":{"description":"Операция: =, -, +","type":"string"},"text":{"description":"Строка","type":"string"}}},"domain.LinePosition":{"type":"object","properties":{"active":{"description":"Текущая строка","allOf":[{"$ref":"#/definitions/domain.TimedLine"}]},"index":{"description":"Номер текущей строки, -1 если строки еще не начались","type":"integer"},"next":{"description":"Следующие строки","type":"array","items":{"$ref":"#/definitions/domain.TimedLine"}},"timeMs":{"description":"Позиция воспроизведения в миллисекундах","type":"integer"}}},"domain.MetadataOverride":{"type":"object","properties":{"group":{"description":"Группа или исполнитель","type":"string"},"id":{"description":"Идентификатор исправления","type":"integer"},"link":{"description":"Ссылка на песню","type":"string"},"releaseDate":{"description":"Дата выпуска песни","type":"string"},"song":{"description":"Название песни","type":"string"},"text":{"description":"Текст песни","type":"string"}}},"domain.MetadataSources":{"type":"object","additionalProperties":{"type":"string"}},"domain.RefreshRequest":{"type":"object","properties":{"refresh_token":{"description":"Токен обновления","type":"string"}}},"domain.Revision":{"type":"object","properties":{"action":{"description":"Действие","type":"string"},"actor":{"description":"Автор изменения","type":"string"},"createdAt":{"description":"Время изменения","type":"string"},"id":{"description":"Идентификатор ревизии","type":"integer"},"snapshot":{"description":"Снимок песни после действия","allOf":[{"$ref":"#/definitions/domain.Song"}]},"songId":{"description":"Идентификатор песни","type":"integer"}}},"domain.RevisionDiff":{"type":"object","properties":{"fields":{"description":"Измененные поля","type":"array","items":{"$ref":"#/definitions/domain.FieldDiff"}},"from":{"description":"Исходная ревизия","type":"integer"},"songId":{"description":"Идентификатор песни","type":"integer"},"to":{"description":"Конечная ревизия","type":"integer"}}},"domain.RoleByUser":{"type":"object","properties":{"role":{"description":"Новая роль","type":"string"}}},"domain.Section":{"type":"object","properties":{"index":{"description":"Номер раздела, начиная с 1","type":"integer"},"label":{"description":"Метка раздела из текста, например Chorus","type":"string"},"lines":{"description":"Строки раздела","type":"array","items":{"type":"string"}},"type":{"description":"Тип раздела","type":"string"}}},"domain.Song":{"type":"object","properties":{"artistId":{"description":"Идентификатор исполнителя","type":"integer"},"enrichment_status":{"description":"Состояние получения данных из API","type":"string"},"group":{"description":"Группа или исполнитель","type":"string"},"id":{"description":"Идентификатор песни","type":"integer"},"link":{"description":"Ссылка на песню","type":"string"},"name":{"description":"Название песни","type":"string"},"rank":{"description":"Релевантность при полнотекстовом поиске","type":"number"},"releaseDate":{"description":"Дата выпуска песни","type":"string"},"snippet":{"description":"Фрагмент текста с подсветкой совпадений","type":"string"},"sources":{"description":"Источник каждого поля с данными из API","allOf":[{"$ref":"#/definitions/domain.MetadataSources"}]},"text":{"description":"Текст песни","type":"string"}}},"domain.SongDataByUser":{"type":"object","properties":{"group":{"description":"Группа или исполнитель","type":"string"},"song":{"description":"Название песни","type":"string"}}},"domain.Tag":{"type":"object","properties":{"id":{"description":"Идентификатор","type":"integer"},"name":{"description":"Название","type":"string"},"songs":{"description":"Количество песен, только для чтения","type":"integer"}}},"domain.TagBinding":{"type":"object","properties":{"genres":{"description":"Названия жанров","type":"array","items":{"type":"string"}},"songs":{"description":"Идентификаторы песен","type":"array","items":{"type":"integer"}},"tags":{"description":"Названия меток","type":"array","items":{"type":"string"}}}},"domain.TextSection":{"type":"object","properties":{"index":{"description":"Номер раздела","type":"integer"},"label":{"description":"Метка раздела","type":"string"},"lang":{"description":"Язык перевода, пусто для оригинала","type":"string"},"lines":{"description":"Строки раздела","type":"array","items":{"type":"string"}},"songId":{"description":"Идентификатор песни","type":"integer"},"text":{"description":"Текст раздела","type":"string"},"total":{"description":"Количество разделов в песне","type":"integer"},"type":{"description":"Тип раздела","type":"string"}}},"domain.TimedLine":{"type":"object","properties":{"text":{"description":"Текст строки","type":"string"},"timeMs":{"description":"Время начала строки в миллисекундах","type":"integer"},"words":{"description":"Слова строки, если заданы в расширенном LRC","type":"array","items":{"$ref":"#/definitions/domain.TimedWord"}}}},"domain.TimedWord":{"type":"object","properties":{"text":{"description":"Слово с пробелами после него","type":"string"},"timeMs":{"description":"Время начала слова в миллисекундах","type":"integer"}}},"domain.TokenPair":{"type":"object","properties":{"access_token":{"description":"Токен доступа","type":"string"},"expires_in":{"description":"Время жизни токена доступа в секундах","type":"integer"},"refresh_token":{"description":"Токен обновления","type":"string"},"token_type":{"description":"Тип токена для заголовка Authorization","type":"string"}}},"domain.Track":{"type":"object","properties":{"name":{"description":"Название песни, только для чтения","type":"string"},"position":{"description":"Номер композиции в альбоме","type":"integer"},"songId":{"description":"Идентификатор песни","type":"integer"}}},"domain.Translation":{"type":"object","properties":{"lang":{"description":"Код языка BCP-47","type":"string"},"sections":{"description":"Разделы перевода, выровненные по оригиналу","type":"array","items":{"$ref":"#/definitions/domain.Section"}},"songId":{"description":"Идентификатор песни","type":"integer"},"text":{"description":"Текст перевода","type":"string"}}},"domain.TranslationByUser":{"type":"object","properties":{"text":{"description":"Текст перевода","type":"string"}}},"domain.TrashedSong":{"type":"object","properties":{"artistId":{"description":"Идентификатор исполнителя","type":"integer"},"deletedAt":{"description":"Время перемещения в корзину","type":"string"},"enrichment_status":{"description":"Состояние получения данных из API","type":"string"},"group":{"description":"Группа или исполнитель","type":"string"},"id":{"description":"Идентификатор песни","type":"integer"},"link":{"description":"Ссылка на песню","type":"string"},"name":{"description":"Название песни","type":"string"},"purgeAt":{"description":"Время окончательного удаления по сроку хранения","type":"string"},"rank":{"description":"Релевантность при полнотекстовом поиске","type":"number"},"releaseDate":{"description":"Дата выпуска песни","type":"string"},"snippet":{"description":"Фрагмент текста с подсветкой совпадений","type":"string"},"sources":{"description":"Источник каждого поля с данными из API","allOf":[{"$ref":"#/definitions/domain.MetadataSources"}]},"text":{"description":"Текст песни","type":"string"}}},"domain.User":{"type":"object","properties":{"createdAt":{"description":"Время регистрации","type":"string"},"id":{"description":"Идентификатор пользователя","type":"integer"},"role":{"description":"Роль пользователя","type":"string"},"username":{"description":"Имя пользователя","type":"string"}}},"server.libResponse":{"type":"object","properties":{"items":{"description":"Песни только с полями из параметра fields","type":"array","items":{"type":"object","additionalProperties":true}},"next_cursor":{"description":"Курсор следующей страницы","type":"string"},"prev_cursor":{"description":"Курсор предыдущей страницы","type":"string"},"total":{"description":"Общее количество песен по фильтру","type":"integer"}}}},"securityDefinitions":{"BearerAuth":{"description":"Access token in the format \"Bearer \u003ctoken\u003e\"","type":"apiKey","name":"Authorization","in":"header"}}}`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Album - объект альбома
+type Album struct {
+	ID       uint64    `json:"id"`               // Идентификатор альбома
+	Title    string    `json:"title"`            // Название альбома
+	ArtistID uint64    `json:"artistId"`         // Идентификатор исполнителя
+	Artist   string    `json:"artist"`           // Название исполнителя, только для чтения
+	Date     time.Time `json:"releaseDate"`      // Дата выпуска альбома
+	Tracks   []Track   `json:"tracks,omitempty"` // Композиции альбома по порядку
+}
+
+// Track - композиция альбома
+type Track struct {
+	Position int    `json:"position"` // Номер композиции в альбоме
+	SongID   uint64 `json:"songId"`   // Идентификатор песни
+	Name     string `json:"name"`     // Название песни, только для чтения
+}
+
+// AlbumTracks - новый порядок композиций альбома
+type AlbumTracks struct {
+	Songs []Id `json:"songs"` // Идентификаторы песен в порядке следования
+}
+
+// AlbumByApi - данные об альбоме песни, полученные из поднятого API
+type AlbumByApi struct {
+	Title string    `json:"title"`       // Название альбома
+	Date  time.Time `json:"releaseDate"` // Дата выпуска альбома
+	Track int       `json:"track"`       // Номер песни в альбоме, 0 - в конец
+}
+
+// NewAlbum создает новый объект Album
+func NewAlbum(title string, artistID uint64, date time.Time) *Album {
+	return &Album{
+		Title:    title,
+		ArtistID: artistID,
+		Date:     date,
+	}
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Разрешения
+const (
+	ScopeSongsRead   = "songs:read"
+	ScopeSongsWrite  = "songs:write"
+	ScopeSongsDelete = "songs:delete"
+	ScopeAdmin       = "admin"
+)
+
+// Роли пользователей
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// APIKeyPrefix - префикс API-ключей, по нему ключ отличается от токена доступа
+const APIKeyPrefix = "sk_"
+
+// RoleScopes - разрешения ролей
+var RoleScopes = map[string][]string{
+	RoleViewer: {ScopeSongsRead},
+	RoleEditor: {ScopeSongsRead, ScopeSongsWrite, ScopeSongsDelete},
+	RoleAdmin:  {ScopeAdmin},
+}
+
+// APIKey - API-ключ для доступа без входа по паролю
+type APIKey struct {
+	ID         uint64     `json:"id"`                   // Идентификатор ключа
+	UserID     uint64     `json:"userId"`               // Владелец ключа
+	Name       string     `json:"name"`                 // Название ключа
+	Prefix     string     `json:"prefix"`               // Начало ключа для его узнавания
+	Scopes     []string   `json:"scopes"`               // Разрешения ключа
+	CreatedAt  time.Time  `json:"createdAt"`            // Время создания
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"` // Время последнего использования
+}
+
+// APIKeyByUser - данные для создания API-ключа
+type APIKeyByUser struct {
+	Name   string   `json:"name"`   // Название ключа
+	Scopes []string `json:"scopes"` // Разрешения ключа
+}
+
+// CreatedAPIKey - созданный API-ключ, сам ключ показывается только один раз
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"` // Ключ
+}
+
+// RoleByUser - данные для смены роли пользователя
+type RoleByUser struct {
+	Role string `json:"role"` // Новая роль
+}
+
+// ValidateRole проверяет, что роль существует
+func ValidateRole(role string) error {
+	if _, ok := RoleScopes[role]; !ok {
+		return &InputDataError{
+			Err:  fmt.Sprintf("unknown role %q, expected %s, %s or %s", role, RoleViewer, RoleEditor, RoleAdmin),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
+// ValidateScopes проверяет, что разрешения существуют и не повторяются
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return &InputDataError{
+			Err:  "at least one scope is required",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	seen := make(map[string]struct{}, len(scopes))
+	for _, scope := range scopes {
+		switch scope {
+		case ScopeSongsRead, ScopeSongsWrite, ScopeSongsDelete, ScopeAdmin:
+		default:
+			return &InputDataError{
+				Err:  fmt.Sprintf("unknown scope %q", scope),
+				Code: http.StatusBadRequest,
+			}
+		}
+		if _, ok := seen[scope]; ok {
+			return &InputDataError{
+				Err:  fmt.Sprintf("duplicate scope %q", scope),
+				Code: http.StatusBadRequest,
+			}
+		}
+		seen[scope] = struct{}{}
+	}
+
+	return nil
+}
+
+// EffectiveScopes возвращает разрешения ключа, которые есть и у роли его владельца,
+// чтобы понижение роли сразу ограничивало выпущенные ключи
+func EffectiveScopes(role string, scopes []string) []string {
+	owner := Principal{Scopes: RoleScopes[role]}
+	effective := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if owner.HasScope(scope) {
+			effective = append(effective, scope)
+		}
+	}
+
+	return effective
+}
@@ -0,0 +1,14 @@
+package domain
+
+// Artist - объект группы или исполнителя
+type Artist struct {
+	ID   uint64 `json:"id"`   // Идентификатор исполнителя
+	Name string `json:"name"` // Название группы или исполнителя
+}
+
+// NewArtist создает новый объект Artist
+func NewArtist(name string) *Artist {
+	return &Artist{
+		Name: name,
+	}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// CacheConfig - время хранения значений в кэше по видам, 0 - значения этого вида не кэшируются
+type CacheConfig struct {
+	TextTTL time.Duration // Разделы текста песни
+	SongTTL time.Duration // Песня со всеми полями
+	LibTTL  time.Duration // Страница библиотеки по фильтру
+}
+
+// LocalCacheConfig - параметры кэша в памяти процесса перед Redis
+type LocalCacheConfig struct {
+	Entries int           // Наибольшее число значений, 0 - кэш в памяти не используется
+	TTL     time.Duration // Наибольшее время хранения значения, ограничивает устаревание при потере сообщений об удалении
+}
+
+// Уровни кэша
+const (
+	CacheTierLocal  = "local"
+	CacheTierRedis  = "redis"
+	CacheTierMemory = "memory"
+)
+
+// CacheTierStats - статистика уровня кэша с момента запуска
+type CacheTierStats struct {
+	Tier      string `json:"tier"`                // Уровень кэша
+	Hits      uint64 `json:"hits"`                // Количество найденных значений
+	Misses    uint64 `json:"misses"`              // Количество отсутствующих значений
+	Evictions uint64 `json:"evictions,omitempty"` // Количество значений, вытесненных из-за ограничения размера
+	Entries   int    `json:"entries,omitempty"`   // Текущее число значений
+}
+
+// Хранилища кэша
+const (
+	CacheBackendRedis  = "redis"  // Redis, общий для всех экземпляров сервиса
+	CacheBackendMemory = "memory" // Память процесса, для разработки без Redis
+	CacheBackendNone   = "none"   // Кэширование отключено
+)
+
+// CacheStoreConfig - выбор и параметры хранилища кэша
+type CacheStoreConfig struct {
+	Backend          string        // Хранилище: redis, memory или none
+	MemoryEntries    int           // Наибольшее число значений в хранилище memory
+	BreakerThreshold int           // Число ошибок Redis подряд, после которого кэш перестает обращаться к нему
+	BreakerCooldown  time.Duration // Время, на которое кэш перестает обращаться к Redis
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -35,3 +37,353 @@ func TestNewSong(t *testing.T) {
 		t.Errorf("Expected Link to be %s, but got %s", link, song.Link)
 	}
 }
+
+// TestSongProject проверяет проекцию полей песни
+func TestSongProject(t *testing.T) {
+	song := Song{ID: 1, Name: "Test Song", Group: "Test Group", Text: "Test Text", Snippet: "<b>Test</b>"}
+
+	result := song.Project([]string{FieldID, FieldName})
+
+	if len(result) != 3 {
+		t.Errorf("Expected 3 fields, but got %d", len(result))
+	}
+
+	if result[FieldID] != song.ID || result[FieldName] != song.Name {
+		t.Errorf("Expected id and name to be projected, but got %v", result)
+	}
+
+	if _, ok := result[FieldText]; ok {
+		t.Errorf("Expected text to be omitted, but got %v", result)
+	}
+
+	if result["snippet"] != song.Snippet {
+		t.Errorf("Expected snippet to be %s, but got %v", song.Snippet, result["snippet"])
+	}
+}
+
+// TestSongProject_UnknownDate проверяет проекцию неизвестной даты выпуска как null
+func TestSongProject_UnknownDate(t *testing.T) {
+	result := Song{ID: 1}.Project([]string{FieldReleaseDate})
+
+	if value, ok := result[FieldReleaseDate]; !ok || value != nil {
+		t.Errorf("Expected releaseDate to be null, but got %v", value)
+	}
+}
+
+// TestDiffSongs проверяет сравнение снимков песни по полям и построчно
+func TestDiffSongs(t *testing.T) {
+	from := Song{ID: 1, Name: "Test Song", Group: "Test Group", Text: "Line 1\\nLine 2\\nLine 3"}
+	to := Song{ID: 1, Name: "New Song", Group: "Test Group", Text: "Line 1\nLine 2a\nLine 3"}
+
+	result := DiffSongs(from, to)
+
+	if len(result) != 2 || result[0].Field != FieldName || result[1].Field != FieldText {
+		t.Fatalf("Expected name and text to differ, but got %v", result)
+	}
+
+	expected := []LineDiff{
+		{Op: LineEqual, Text: "Line 1"},
+		{Op: LineDelete, Text: "Line 2"},
+		{Op: LineInsert, Text: "Line 2a"},
+		{Op: LineEqual, Text: "Line 3"},
+	}
+	if !reflect.DeepEqual(result[1].Lines, expected) {
+		t.Errorf("Expected lines to be %v, but got %v", expected, result[1].Lines)
+	}
+}
+
+// TestParseLyrics проверяет разбиение текста на разделы
+func TestParseLyrics(t *testing.T) {
+	text := "[Intro]\r\nOh\r\n\r\n\r\nFirst line  \r\nSecond line\\n\\n[Chorus 2]\nLa la\n[Bridge]\n\nNa na\n"
+
+	result := ParseLyrics(text)
+
+	expected := Lyrics{
+		{Index: 1, Type: SectionIntro, Label: "Intro", Lines: []string{"Oh"}},
+		{Index: 2, Type: SectionVerse, Lines: []string{"First line", "Second line"}},
+		{Index: 3, Type: SectionChorus, Label: "Chorus 2", Lines: []string{"La la"}},
+		{Index: 4, Type: SectionBridge, Label: "Bridge", Lines: []string{"Na na"}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected sections to be %v, but got %v", expected, result)
+	}
+}
+
+// TestNormalizeText проверяет нормализацию переводов строк
+func TestNormalizeText(t *testing.T) {
+	result := NormalizeText("\nLine 1 \r\nLine 2\\n\\n\\n\\nLine 3\n\n")
+
+	if result != "Line 1\nLine 2\n\nLine 3" {
+		t.Errorf("Expected normalized text, but got %q", result)
+	}
+}
+
+// TestParseLRC проверяет разбор LRC с повторами строк, сдвигом и метками слов
+func TestParseLRC(t *testing.T) {
+	data := "[ti:Hole]\r\n[offset:+500]\r\n[00:12.00][01:02.5]La la\r\n[00:20.120]<00:20.12>Hello <00:21.00>world<00:22.00>\r\nno time\r\n"
+
+	result, err := ParseLRC(data)
+
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := []TimedLine{
+		{TimeMs: 11500, Text: "La la"},
+		{TimeMs: 19620, Text: "Hello world", Words: []TimedWord{
+			{TimeMs: 19620, Text: "Hello "},
+			{TimeMs: 20500, Text: "world"},
+			{TimeMs: 21500, Text: ""},
+		}},
+		{TimeMs: 62000, Text: "La la"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected lines to be %v, but got %v", expected, result)
+	}
+
+	lrc := FormatLRC(TimedLyrics{Name: "Hole", Lines: result})
+	if lrc != "[ti:Hole]\n[00:11.50]La la\n[00:19.62]<00:19.62>Hello <00:20.50>world<00:21.50>\n[01:02.00]La la\n" {
+		t.Errorf("Unexpected LRC %q", lrc)
+	}
+}
+
+// TestLineAt проверяет поиск строки по позиции воспроизведения
+func TestLineAt(t *testing.T) {
+	lines := []TimedLine{{TimeMs: 1000, Text: "one"}, {TimeMs: 2000, Text: "two"}, {TimeMs: 3000, Text: "three"}}
+
+	before := LineAt(lines, 500, 2)
+	if before.Active != nil || before.Index != -1 || len(before.Next) != 2 {
+		t.Errorf("Expected no active line and two next lines, but got %v", before)
+	}
+
+	middle := LineAt(lines, 2000, 3)
+	if middle.Active == nil || middle.Active.Text != "two" || len(middle.Next) != 1 {
+		t.Errorf("Expected active line two and one next line, but got %v", middle)
+	}
+}
+
+// TestLangFallbacks проверяет канонический вид кода языка и его сокращения
+func TestLangFallbacks(t *testing.T) {
+	lang, err := CanonicalLang("zh-hant-tw")
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	result := LangFallbacks(lang)
+
+	expected := []string{"zh-Hant-TW", "zh-Hant", "zh"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected fallbacks to be %v, but got %v", expected, result)
+	}
+
+	if _, err := CanonicalLang("not a language"); err == nil {
+		t.Errorf("Expected error for invalid language tag")
+	}
+}
+
+// TestAlignLyrics проверяет выравнивание разделов перевода по оригиналу
+func TestAlignLyrics(t *testing.T) {
+	original := ParseLyrics("Line\n\n[Chorus]\nLa la")
+
+	result, err := AlignLyrics(original, ParseLyrics("Строка\n\n[Припев]\nЛа ла"))
+
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if result[1].Type != SectionChorus || result[1].Label != "Припев" || result[0].Type != SectionVerse {
+		t.Errorf("Expected types from the original, but got %v", result)
+	}
+
+	if _, err := AlignLyrics(original, ParseLyrics("Строка")); err == nil {
+		t.Errorf("Expected error for misaligned translation")
+	}
+}
+
+// TestRateLimitRules проверяет разбор правил ограничения запросов и выбор правила
+func TestRateLimitRules(t *testing.T) {
+	rules, err := ParseRateLimitRules("*=300/1m; ip:*=60/1m; get /lib=30/1m; ip:GET /songs/:id/lrc=5/10s")
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	tests := []struct {
+		kind, method, path string
+		rule               string
+		limit              RateLimit
+	}{
+		{RateLimitIP, "GET", "/songs/:id/lrc", "ip:GET /songs/:id/lrc", RateLimit{5, 10 * time.Second}},
+		{RateLimitUser, "GET", "/lib", "GET /lib", RateLimit{30, time.Minute}},
+		{RateLimitIP, "POST", "/song", "ip:*", RateLimit{60, time.Minute}},
+		{RateLimitKey, "POST", "/song", "*", RateLimit{300, time.Minute}},
+	}
+	for _, tt := range tests {
+		rule, limit, ok := rules.Match(tt.kind, tt.method, tt.path)
+		if !ok || rule != tt.rule || limit != tt.limit {
+			t.Errorf("Expected rule %s %v for %s %s %s, but got %s %v", tt.rule, tt.limit, tt.kind, tt.method, tt.path, rule, limit)
+		}
+	}
+
+	for _, invalid := range []string{"GET /lib", "GET /lib=0/1m", "GET /lib=10/1ms", "bot:*=1/1s", "lib=1/1m"} {
+		if _, err := ParseRateLimitRules(invalid); err == nil {
+			t.Errorf("Expected error for rule %q", invalid)
+		}
+	}
+}
+
+// TestSlidingWindow проверяет оценку скользящего окна
+func TestSlidingWindow(t *testing.T) {
+	limit := RateLimit{Limit: 10, Window: time.Minute}
+
+	// Прошла четверть окна: из предыдущего учитываются три четверти - 6 из 8 запросов
+	result := limit.SlidingWindow(true, 8, 3, 15*time.Second)
+	if result.Remaining != 1 || result.Reset != 45*time.Second {
+		t.Errorf("Expected 1 remaining request and reset in 45s, but got %+v", result)
+	}
+
+	// Для еще одного запроса доля предыдущего окна должна уменьшиться на 1 запрос - восьмую часть окна
+	result = limit.SlidingWindow(false, 8, 4, 15*time.Second)
+	if result.Remaining != 0 || result.RetryAfter != 7500*time.Millisecond {
+		t.Errorf("Expected retry in 7.5s, but got %+v", result)
+	}
+
+	// Текущее окно заполнено - ждем следующего
+	result = limit.SlidingWindow(false, 0, 10, 50*time.Second)
+	if result.RetryAfter != 10*time.Second {
+		t.Errorf("Expected retry in 10s, but got %+v", result)
+	}
+}
+
+// TestMetadataPrecedence проверяет разбор правил порядка источников данных
+func TestMetadataPrecedence(t *testing.T) {
+	providers := []string{MetadataProviderOverride, MetadataProviderCatalog, MetadataProviderAPI}
+	precedence, err := ParseMetadataPrecedence("text=catalog, api; *=override,api", providers)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if order := precedence.Order(FieldText); !reflect.DeepEqual(order, []string{"catalog", "api"}) {
+		t.Errorf("Expected text order [catalog api], but got %v", order)
+	}
+	if order := precedence.Order(FieldLink); !reflect.DeepEqual(order, []string{"override", "api"}) {
+		t.Errorf("Expected link order [override api], but got %v", order)
+	}
+
+	for _, invalid := range []string{"text", "lyrics=api", "text=api,web"} {
+		if _, err := ParseMetadataPrecedence(invalid, providers); err == nil {
+			t.Errorf("Expected error for rule %q", invalid)
+		}
+	}
+}
+
+// TestDecodeSongs проверяет чтение песен для импорта из NDJSON и CSV
+func TestDecodeSongs(t *testing.T) {
+	rows, err := DecodeSongs(strings.NewReader(`{"group":"Muse","song":"Hysteria","text":"text","sources":{"text":"api"}}
+{"group":"Muse","song":"Uprising"}
+`), FormatNDJSON, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(rows) != 2 || rows[1].Row != 2 || rows[0].Text != "text" || rows[0].Sources != nil {
+		t.Errorf("Unexpected NDJSON rows %+v", rows)
+	}
+
+	rows, err = DecodeSongs(strings.NewReader("\ufeffgroup,song,album\nMuse,Hysteria,Absolution\n"), FormatCSV, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(rows) != 1 || rows[0].Group != "Muse" || rows[0].Album == nil || rows[0].Album.Title != "Absolution" {
+		t.Errorf("Unexpected CSV rows %+v", rows)
+	}
+
+	if _, err = DecodeSongs(strings.NewReader("group,song\na,b\nc,d\n"), FormatCSV, 1); err == nil {
+		t.Errorf("Expected error for too many songs")
+	}
+	if _, err = DecodeSongs(strings.NewReader("group,song\na,b,2003-13-01\n"), "xml", 0); err == nil {
+		t.Errorf("Expected error for unsupported format")
+	}
+}
+
+// TestSongEncoder проверяет запись песен для экспорта в CSV и JSON
+func TestSongEncoder(t *testing.T) {
+	songs := []Song{
+		{ID: 1, Name: "Hysteria", Group: "Muse", Date: time.Date(2003, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, Name: "Uprising, live", Group: "Muse"},
+	}
+	fields := []string{FieldID, FieldName, FieldReleaseDate}
+
+	var out strings.Builder
+	encoder, err := NewSongEncoder(&out, FormatCSV, fields)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	for _, song := range songs {
+		if err = encoder.Encode(song); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+	}
+	if err = encoder.Close(); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	expected := "id,name,releaseDate\n1,Hysteria,2003-12-01\n2,\"Uprising, live\",\n"
+	if out.String() != expected {
+		t.Errorf("Expected CSV %q, but got %q", expected, out.String())
+	}
+
+	// Пустой экспорт - корректный JSON массив
+	out.Reset()
+	encoder, _ = NewSongEncoder(&out, FormatJSON, fields)
+	if err = encoder.Close(); err != nil || strings.TrimSpace(strings.ReplaceAll(out.String(), "\n", "")) != "[]" {
+		t.Errorf("Expected empty JSON array, but got %q, %v", out.String(), err)
+	}
+
+	out.Reset()
+	encoder, _ = NewSongEncoder(&out, FormatJSON, []string{FieldID})
+	_ = encoder.Encode(songs[0])
+	_ = encoder.Encode(songs[1])
+	_ = encoder.Close()
+	if out.String() != "[\n{\"id\":1},\n{\"id\":2}\n]\n" {
+		t.Errorf("Unexpected JSON %q", out.String())
+	}
+
+	if _, err = NewSongEncoder(&out, "xml", nil); err == nil {
+		t.Errorf("Expected error for unsupported format")
+	}
+}
+
+// TestSongFileRoundTrip проверяет, что файл экспорта библиотеки читается импортом в каждом формате
+func TestSongFileRoundTrip(t *testing.T) {
+	songs := []Song{
+		{ID: 1, Name: "Hysteria", Group: "Muse", Date: time.Date(2003, 12, 1, 0, 0, 0, 0, time.UTC), Text: "It's bugging me", Link: "link"},
+		{ID: 2, Name: "Uprising", Group: "Muse"},
+	}
+
+	for _, format := range []string{FormatCSV, FormatJSON, FormatNDJSON} {
+		var out strings.Builder
+		encoder, err := NewSongEncoder(&out, format, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		for _, song := range songs {
+			if err = encoder.Encode(song); err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+		}
+		if err = encoder.Close(); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+
+		rows, err := DecodeSongs(strings.NewReader(out.String()), format, 10)
+		if err != nil {
+			t.Fatalf("Expected no error for %s, but got %v", format, err)
+		}
+		if len(rows) != len(songs) {
+			t.Fatalf("Expected %d %s rows, but got %d", len(songs), format, len(rows))
+		}
+		for i, row := range rows {
+			song := songs[i]
+			if row.Name != song.Name || row.Group != song.Group || row.Text != song.Text || row.Link != song.Link || !row.Date.Equal(song.Date) {
+				t.Errorf("Unexpected %s row %+v for song %+v", format, row, song)
+			}
+		}
+	}
+}
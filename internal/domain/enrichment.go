@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"net/url"
+	"time"
+)
+
+// EnrichmentConfig - параметры клиента API с дополнительными данными о песнях
+type EnrichmentConfig struct {
+	BaseURL          *url.URL      // Адрес API
+	Timeout          time.Duration // Время ожидания одного запроса
+	Retries          int           // Число повторов при ошибках сети и 5xx
+	BackoffBase      time.Duration // Начальная пауза перед повтором, удваивается с каждым повтором
+	BackoffMax       time.Duration // Наибольшая пауза перед повтором
+	BreakerThreshold int           // Число ошибок подряд, после которого запросы перестают отправляться
+	BreakerCooldown  time.Duration // Время, на которое запросы перестают отправляться
+}
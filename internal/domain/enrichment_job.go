@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Состояния получения дополнительных данных песни
+const (
+	EnrichmentPending   = "pending"
+	EnrichmentSucceeded = "succeeded"
+	EnrichmentFailed    = "failed"
+)
+
+// EnrichmentActor - автор изменений, внесенных фоновым получением данных
+const EnrichmentActor = "enrichment"
+
+// EnrichmentJob - задача фонового получения данных песни из API
+type EnrichmentJob struct {
+	ID            uint64     `json:"id"`                      // Идентификатор задачи
+	SongID        uint64     `json:"songId"`                  // Идентификатор песни
+	Status        string     `json:"status"`                  // Состояние: pending, succeeded или failed
+	Attempts      int        `json:"attempts"`                // Число выполненных попыток
+	LastError     string     `json:"lastError,omitempty"`     // Ошибка последней попытки
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"` // Время следующей попытки
+	CreatedAt     time.Time  `json:"createdAt"`               // Время создания
+	UpdatedAt     time.Time  `json:"updatedAt"`               // Время изменения
+
+	Data SongDataByUser `json:"-"` // Группа и название песни для запроса к API
+}
+
+// EnrichmentJobConfig - параметры фонового получения данных
+type EnrichmentJobConfig struct {
+	Workers      int           // Число обработчиков
+	MaxAttempts  int           // Число попыток, после которого задача завершается ошибкой
+	RetryBackoff time.Duration // Пауза перед второй попыткой, удваивается с каждой попыткой
+	PollInterval time.Duration // Пауза между проверками очереди, когда она пуста
+	Lease        time.Duration // Время, на которое задача закрепляется за обработчиком
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Результаты импорта строки
+const (
+	ImportCreated     = "created"      // Песня создана
+	ImportWouldCreate = "would_create" // Песня была бы создана, пробный импорт
+	ImportSkipped     = "skipped"      // Песня уже есть в библиотеке или выше в файле
+	ImportFailed      = "failed"       // Песню не удалось создать
+)
+
+// MaxImportRows - наибольшее число песен в одном импорте
+const MaxImportRows = 10000
+
+// ImportRow - песня из файла импорта: группа и название, данные песни необязательны
+type ImportRow struct {
+	Row int `json:"-"` // Номер строки с песней в файле, начиная с 1
+
+	SongDataByUser
+	SongDataByApi
+}
+
+// UnmarshalJSON читает песню из JSON. Название принимается из поля song или из поля name,
+// в котором его записывает экспорт библиотеки
+func (r *ImportRow) UnmarshalJSON(data []byte) error {
+	type importRow ImportRow
+	var row struct {
+		importRow
+		Title string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &row); err != nil {
+		return err
+	}
+
+	*r = ImportRow(row.importRow)
+	if r.Name == "" {
+		r.Name = row.Title
+	}
+
+	return nil
+}
+
+// ImportResult - результат импорта строки
+type ImportResult struct {
+	Row    int    `json:"row"`              // Номер строки в файле
+	Group  string `json:"group"`            // Группа или исполнитель
+	Name   string `json:"song"`             // Название песни
+	Status string `json:"status"`           // created, would_create, skipped или failed
+	SongID uint64 `json:"songId,omitempty"` // Идентификатор созданной песни
+	Reason string `json:"reason,omitempty"` // Причина пропуска или ошибки
+}
+
+// ImportReport - отчет об импорте песен
+type ImportReport struct {
+	DryRun  bool           `json:"dryRun"`  // Пробный импорт без изменения библиотеки
+	Total   int            `json:"total"`   // Число строк
+	Created int            `json:"created"` // Число созданных песен, при пробном импорте - готовых к созданию
+	Skipped int            `json:"skipped"` // Число пропущенных повторов
+	Failed  int            `json:"failed"`  // Число ошибок
+	Rows    []ImportResult `json:"rows"`    // Результаты по строкам в порядке файла
+}
+
+// SongKey возвращает ключ песни для сравнения группы и названия без учета регистра
+func SongKey(data SongDataByUser) string {
+	return strings.ToLower(strings.TrimSpace(data.Group)) + "\x00" + strings.ToLower(strings.TrimSpace(data.Name))
+}
@@ -0,0 +1,102 @@
+package domain
+
+import "time"
+
+// Языки полнотекстового поиска
+const (
+	SearchLangRu = "ru" // Русская конфигурация, латиница разбирается английским стеммером
+	SearchLangEn = "en" // Английская конфигурация
+)
+
+// Режимы сравнения строковых полей фильтра
+const (
+	MatchSubstring = "substring" // Поиск подстроки, режим по умолчанию
+	MatchExact     = "exact"     // Точное совпадение
+)
+
+// Поля сортировки библиотеки
+const (
+	SortID          = "id"
+	SortName        = "song_name"
+	SortGroup       = "group_name"
+	SortReleaseDate = "releaseDate"
+)
+
+// sortAliases - допустимые имена полей сортировки
+var sortAliases = map[string]string{
+	"id":           SortID,
+	"song":         SortName,
+	"name":         SortName,
+	"song_name":    SortName,
+	"group":        SortGroup,
+	"group_name":   SortGroup,
+	"releaseDate":  SortReleaseDate,
+	"release_date": SortReleaseDate,
+}
+
+// MatchMode - режимы сравнения для строковых полей фильтра
+type MatchMode struct {
+	Name  string // Название песни
+	Group string // Группа или исполнитель
+	Text  string // Текст песни
+	Link  string // Ссылка на песню
+}
+
+// SortField - поле сортировки библиотеки
+type SortField struct {
+	Field string // Одно из значений Sort*
+	Desc  bool   // Сортировка по убыванию
+}
+
+// LibFilter - параметры выборки библиотеки песен
+type LibFilter struct {
+	Song       Song        // Фильтр по полям песни
+	AlbumID    Id          // Фильтр по альбому
+	Tags       TagFilter   // Фильтр по меткам
+	Genres     TagFilter   // Фильтр по жанрам
+	Match      MatchMode   // Режимы сравнения строковых полей
+	DateFrom   time.Time   // Начало диапазона дат выпуска включительно
+	DateTo     time.Time   // Конец диапазона дат выпуска включительно
+	Query      string      // Полнотекстовый поисковый запрос
+	SearchLang string      // Язык полнотекстового поиска
+	Sort       []SortField // Сортировка, по умолчанию по релевантности при поиске и по идентификатору
+	Fields     []string    // Возвращаемые поля песни, по умолчанию DefaultLibFields
+}
+
+// SortFieldByName возвращает поле сортировки по имени из запроса
+func SortFieldByName(name string) (string, bool) {
+	field, ok := sortAliases[name]
+	return field, ok
+}
+
+// IsMatchMode проверяет, поддерживается ли режим сравнения
+func IsMatchMode(mode string) bool {
+	return mode == MatchSubstring || mode == MatchExact
+}
+
+// IsSearchLang проверяет, поддерживается ли язык полнотекстового поиска
+func IsSearchLang(lang string) bool {
+	return lang == SearchLangRu || lang == SearchLangEn
+}
+
+// Ограничения размера страницы библиотеки
+const (
+	DefaultLibLimit = 20  // Размер страницы по умолчанию
+	MaxLibLimit     = 100 // Максимальный размер страницы
+)
+
+// Pagination - параметры пагинации библиотеки песен
+type Pagination struct {
+	Page   Page   // Номер страницы для постраничного режима, 0 - пагинация курсором
+	Cursor string // Курсор, полученный из next_cursor или prev_cursor
+	Limit  int    // Размер страницы
+	Total  bool   // Подсчитывать ли общее количество песен по фильтру
+}
+
+// LibPage - страница библиотеки песен
+type LibPage struct {
+	Items      []Song  `json:"items"`                 // Песни на странице
+	NextCursor string  `json:"next_cursor,omitempty"` // Курсор следующей страницы
+	PrevCursor string  `json:"prev_cursor,omitempty"` // Курсор предыдущей страницы
+	Total      *uint64 `json:"total,omitempty"`       // Общее количество песен по фильтру
+}
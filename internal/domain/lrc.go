@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultNextLines - количество следующих строк в ответе по умолчанию
+const DefaultNextLines = 3
+
+var (
+	lrcTimeTag = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	lrcMetaTag = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+	lrcWordTag = regexp.MustCompile(`<(\d+):(\d{1,2})(?:[.:](\d{1,3}))?>`)
+)
+
+// TimedWord - слово строки с временем начала для караоке
+type TimedWord struct {
+	TimeMs int64  `json:"timeMs"` // Время начала слова в миллисекундах
+	Text   string `json:"text"`   // Слово с пробелами после него
+}
+
+// TimedLine - строка текста с временем начала
+type TimedLine struct {
+	TimeMs int64       `json:"timeMs"`          // Время начала строки в миллисекундах
+	Text   string      `json:"text"`            // Текст строки
+	Words  []TimedWord `json:"words,omitempty"` // Слова строки, если заданы в расширенном LRC
+}
+
+// TimedLyrics - синхронизированный текст песни
+type TimedLyrics struct {
+	SongID uint64      `json:"songId"` // Идентификатор песни
+	Name   string      `json:"name"`   // Название песни
+	Group  string      `json:"group"`  // Группа или исполнитель
+	Lines  []TimedLine `json:"lines"`  // Строки в порядке времени
+}
+
+// LinePosition - строка, звучащая в момент воспроизведения, и следующие за ней
+type LinePosition struct {
+	TimeMs int64       `json:"timeMs"`           // Позиция воспроизведения в миллисекундах
+	Index  int         `json:"index"`            // Номер текущей строки, -1 если строки еще не начались
+	Active *TimedLine  `json:"active,omitempty"` // Текущая строка
+	Next   []TimedLine `json:"next"`             // Следующие строки
+}
+
+// ParseLRC разбирает текст в формате LRC, включая расширенные метки слов <mm:ss.xx>.
+// Строка с несколькими метками времени повторяется для каждой из них,
+// метка [offset:] сдвигает все строки
+func ParseLRC(data string) ([]TimedLine, error) {
+	var lines []TimedLine
+	var offset int64
+
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	for _, raw := range strings.Split(data, "\n") {
+		raw = strings.TrimSpace(raw)
+
+		var times []int64
+		for {
+			tag := lrcTimeTag.FindStringSubmatch(raw)
+			if tag == nil {
+				break
+			}
+			times = append(times, lrcTime(tag[1], tag[2], tag[3]))
+			raw = raw[len(tag[0]):]
+		}
+
+		if len(times) == 0 {
+			if meta := lrcMetaTag.FindStringSubmatch(raw); meta != nil && strings.EqualFold(meta[1], "offset") {
+				value, err := strconv.ParseInt(strings.TrimSpace(meta[2]), 10, 64)
+				if err != nil {
+					return nil, &InputDataError{
+						Err:  fmt.Sprintf("Invalid LRC offset %q", meta[2]),
+						Code: http.StatusBadRequest,
+					}
+				}
+				offset = value
+			}
+			continue
+		}
+
+		text, words := parseLrcWords(raw)
+		for _, t := range times {
+			lines = append(lines, TimedLine{TimeMs: t, Text: text, Words: words})
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, &InputDataError{
+			Err:  "LRC has no timed lines",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	// Положительный offset показывает строки раньше
+	for i := range lines {
+		lines[i].TimeMs = max(lines[i].TimeMs-offset, 0)
+		if lines[i].Words != nil {
+			words := make([]TimedWord, len(lines[i].Words))
+			for j, word := range lines[i].Words {
+				words[j] = TimedWord{TimeMs: max(word.TimeMs-offset, 0), Text: word.Text}
+			}
+			lines[i].Words = words
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].TimeMs < lines[j].TimeMs
+	})
+
+	return lines, nil
+}
+
+// parseLrcWords выделяет слова с метками времени из текста строки
+func parseLrcWords(raw string) (string, []TimedWord) {
+	tags := lrcWordTag.FindAllStringSubmatchIndex(raw, -1)
+	if len(tags) == 0 {
+		return raw, nil
+	}
+
+	words := make([]TimedWord, 0, len(tags))
+	var text strings.Builder
+	text.WriteString(raw[:tags[0][0]])
+	for i, tag := range tags {
+		end := len(raw)
+		if i+1 < len(tags) {
+			end = tags[i+1][0]
+		}
+		word := raw[tag[1]:end]
+		text.WriteString(word)
+		words = append(words, TimedWord{
+			TimeMs: lrcTime(raw[tag[2]:tag[3]], raw[tag[4]:tag[5]], submatch(raw, tag[6], tag[7])),
+			Text:   word,
+		})
+	}
+
+	return strings.TrimSpace(text.String()), words
+}
+
+// submatch возвращает необязательную группу совпадения или пустую строку
+func submatch(s string, start, end int) string {
+	if start < 0 {
+		return ""
+	}
+	return s[start:end]
+}
+
+// lrcTime переводит минуты, секунды и дробную часть метки LRC в миллисекунды
+func lrcTime(minutes, seconds, frac string) int64 {
+	m, _ := strconv.ParseInt(minutes, 10, 64)
+	s, _ := strconv.ParseInt(seconds, 10, 64)
+	ms := (m*60 + s) * 1000
+	if frac != "" {
+		f, _ := strconv.ParseInt(frac, 10, 64)
+		for i := len(frac); i < 3; i++ {
+			f *= 10
+		}
+		ms += f
+	}
+
+	return ms
+}
+
+// FormatLRC формирует текст в формате LRC, слова с метками выводятся в расширенном формате
+func FormatLRC(lyrics TimedLyrics) string {
+	var b strings.Builder
+	if lyrics.Name != "" {
+		fmt.Fprintf(&b, "[ti:%s]\n", lyrics.Name)
+	}
+	if lyrics.Group != "" {
+		fmt.Fprintf(&b, "[ar:%s]\n", lyrics.Group)
+	}
+
+	for _, line := range lyrics.Lines {
+		b.WriteString("[" + formatLrcTime(line.TimeMs) + "]")
+		if len(line.Words) == 0 {
+			b.WriteString(line.Text)
+		}
+		for _, word := range line.Words {
+			b.WriteString("<" + formatLrcTime(word.TimeMs) + ">" + word.Text)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// formatLrcTime форматирует время в миллисекундах как mm:ss.xx
+func formatLrcTime(ms int64) string {
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60000, ms/1000%60, ms%1000/10)
+}
+
+// LineAt возвращает строку, звучащую в момент timeMs, и next следующих строк
+func LineAt(lines []TimedLine, timeMs int64, next int) LinePosition {
+	index := sort.Search(len(lines), func(i int) bool {
+		return lines[i].TimeMs > timeMs
+	}) - 1
+
+	position := LinePosition{TimeMs: timeMs, Index: index, Next: []TimedLine{}}
+	if index >= 0 {
+		position.Active = &lines[index]
+	}
+
+	end := min(index+1+next, len(lines))
+	position.Next = append(position.Next, lines[index+1:end]...)
+
+	return position
+}
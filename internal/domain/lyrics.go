@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Типы разделов текста песни
+const (
+	SectionVerse     = "verse"
+	SectionChorus    = "chorus"
+	SectionPreChorus = "pre-chorus"
+	SectionBridge    = "bridge"
+	SectionIntro     = "intro"
+	SectionOutro     = "outro"
+)
+
+// sectionTypes - типы разделов по началу метки, проверяются по порядку.
+// Правила повторяются в функции lyrics_section_type миграции 000008
+var sectionTypes = []struct {
+	kind  string
+	label *regexp.Regexp
+}{
+	{kind: SectionIntro, label: regexp.MustCompile(`^(intro|вступление)`)},
+	{kind: SectionPreChorus, label: regexp.MustCompile(`^(pre-?chorus|предприпев)`)},
+	{kind: SectionChorus, label: regexp.MustCompile(`^(chorus|refrain|hook|припев)`)},
+	{kind: SectionBridge, label: regexp.MustCompile(`^(bridge|бридж)`)},
+	{kind: SectionOutro, label: regexp.MustCompile(`^(outro|аутро|концовка)`)},
+}
+
+var (
+	markerLine    = regexp.MustCompile(`^\[([^\]]+)\]$`)
+	trailingSpace = regexp.MustCompile(`[ \t]+(\n|$)`)
+	extraBlank    = regexp.MustCompile(`\n{3,}`)
+)
+
+// Section - раздел текста песни
+type Section struct {
+	Index int      `json:"index"`           // Номер раздела, начиная с 1
+	Type  string   `json:"type"`            // Тип раздела
+	Label string   `json:"label,omitempty"` // Метка раздела из текста, например Chorus
+	Lines []string `json:"lines"`           // Строки раздела
+}
+
+// Lyrics - текст песни, разбитый на разделы
+type Lyrics = []Section
+
+// TextSection - раздел текста песни с его положением в тексте
+type TextSection struct {
+	SongID uint64   `json:"songId"`          // Идентификатор песни
+	Index  int      `json:"index"`           // Номер раздела
+	Total  int      `json:"total"`           // Количество разделов в песне
+	Type   string   `json:"type"`            // Тип раздела
+	Label  string   `json:"label,omitempty"` // Метка раздела
+	Text   string   `json:"text"`            // Текст раздела
+	Lines  []string `json:"lines"`           // Строки раздела
+	Lang   string   `json:"lang,omitempty"`  // Язык перевода, пусто для оригинала
+}
+
+// NormalizeText приводит переводы строк к \n, понимая CRLF и экранированные
+// последовательности \n, убирает пробелы в конце строк и лишние пустые строки.
+// Правила повторяются в миграции 000008
+func NormalizeText(text string) string {
+	text = strings.ReplaceAll(text, `\n`, "\n")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = trailingSpace.ReplaceAllString(text, "$1")
+	text = extraBlank.ReplaceAllString(text, "\n\n")
+	return strings.Trim(text, "\n")
+}
+
+// ParseLyrics разбивает текст на разделы. Разделы отделяются пустыми строками
+// или метками вида [Chorus], раздел без метки считается куплетом
+func ParseLyrics(text string) Lyrics {
+	sections := Lyrics{}
+	current := Section{Type: SectionVerse}
+	flush := func() {
+		if len(current.Lines) > 0 {
+			current.Index = len(sections) + 1
+			sections = append(sections, current)
+			current = Section{Type: SectionVerse}
+		}
+	}
+
+	for _, line := range strings.Split(NormalizeText(text), "\n") {
+		marker := markerLine.FindStringSubmatch(line)
+		switch {
+		case line == "":
+			flush()
+		case marker != nil:
+			flush()
+			label := strings.TrimSpace(marker[1])
+			current = Section{Type: SectionType(label), Label: label}
+		default:
+			current.Lines = append(current.Lines, line)
+		}
+	}
+	flush()
+
+	return sections
+}
+
+// SectionType определяет тип раздела по метке, неизвестные метки считаются куплетом
+func SectionType(label string) string {
+	label = strings.ToLower(label)
+	for _, t := range sectionTypes {
+		if t.label.MatchString(label) {
+			return t.kind
+		}
+	}
+
+	return SectionVerse
+}
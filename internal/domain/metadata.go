@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Источники дополнительных данных песни
+const (
+	MetadataProviderOverride = "override" // Ручные исправления из таблицы metadata_override
+	MetadataProviderCatalog  = "catalog"  // Локальный каталог из JSON или CSV файла
+	MetadataProviderAPI      = "api"      // Внешний API с данными о песнях
+	MetadataSourceUser       = "user"     // Поле изменено пользователем после создания песни
+)
+
+// FieldAlbum - альбом песни, получаемый вместе с остальными данными
+const FieldAlbum = "album"
+
+// MetadataFields - поля песни, которые заполняют источники данных
+var MetadataFields = []string{FieldReleaseDate, FieldText, FieldLink, FieldAlbum}
+
+// MetadataSources - источник каждого заполненного поля песни
+type MetadataSources map[string]string
+
+// MetadataPrecedence - порядок источников для каждого поля, "*" - порядок по умолчанию
+type MetadataPrecedence map[string][]string
+
+// MetadataOverride - ручное исправление данных песни, пустые поля не переопределяются
+type MetadataOverride struct {
+	ID    uint64     `json:"id"`                    // Идентификатор исправления
+	Group string     `json:"group"`                 // Группа или исполнитель
+	Name  string     `json:"song"`                  // Название песни
+	Date  *time.Time `json:"releaseDate,omitempty"` // Дата выпуска песни
+	Text  string     `json:"text,omitempty"`        // Текст песни
+	Link  string     `json:"link,omitempty"`        // Ссылка на песню
+}
+
+// ParseMetadataPrecedence разбирает правила вида "*=override,catalog,api;text=catalog,api".
+// Поле без правила берет порядок "*", а если его нет - порядок providers
+// providers - имена подключенных источников
+func ParseMetadataPrecedence(value string, providers []string) (MetadataPrecedence, error) {
+	known := make(map[string]bool, len(providers))
+	for _, name := range providers {
+		known[name] = true
+	}
+
+	precedence := MetadataPrecedence{MetadataAnyField: providers}
+	for _, rule := range strings.Split(value, ";") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+
+		field, order, found := strings.Cut(rule, "=")
+		if !found {
+			return nil, metadataError(fmt.Sprintf("expected field=provider,... got %q", rule))
+		}
+		field = strings.TrimSpace(field)
+		if field != MetadataAnyField && !isMetadataField(field) {
+			return nil, metadataError(fmt.Sprintf("unknown field %q", field))
+		}
+
+		var names []string
+		for _, name := range strings.Split(order, ",") {
+			name = strings.TrimSpace(name)
+			if !known[name] {
+				return nil, metadataError(fmt.Sprintf("unknown provider %q for field %s", name, field))
+			}
+			names = append(names, name)
+		}
+		precedence[field] = names
+	}
+
+	return precedence, nil
+}
+
+// MetadataAnyField - правило порядка источников для полей без собственного правила
+const MetadataAnyField = "*"
+
+// Order возвращает порядок источников для поля
+func (p MetadataPrecedence) Order(field string) []string {
+	if order, ok := p[field]; ok {
+		return order
+	}
+
+	return p[MetadataAnyField]
+}
+
+// MetadataValue проверяет, заполнено ли поле field в данных песни
+func MetadataValue(data SongDataByApi, field string) bool {
+	switch field {
+	case FieldReleaseDate:
+		return !data.Date.IsZero()
+	case FieldText:
+		return data.Text != ""
+	case FieldLink:
+		return data.Link != ""
+	case FieldAlbum:
+		return data.Album != nil && data.Album.Title != ""
+	}
+
+	return false
+}
+
+// CopyMetadataField переносит поле field из src в dst
+func CopyMetadataField(dst *SongDataByApi, src SongDataByApi, field string) {
+	switch field {
+	case FieldReleaseDate:
+		dst.Date = src.Date
+	case FieldText:
+		dst.Text = src.Text
+	case FieldLink:
+		dst.Link = src.Link
+	case FieldAlbum:
+		dst.Album = src.Album
+	}
+}
+
+// SongData возвращает исправление в виде данных песни
+func (o MetadataOverride) SongData() SongDataByApi {
+	data := SongDataByApi{Text: o.Text, Link: o.Link}
+	if o.Date != nil {
+		data.Date = *o.Date
+	}
+
+	return data
+}
+
+// isMetadataField проверяет, заполняют ли поле источники данных
+func isMetadataField(name string) bool {
+	for _, field := range MetadataFields {
+		if field == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// metadataError возвращает ошибку разбора правил источников данных
+func metadataError(msg string) error {
+	return &InputDataError{
+		Err:  msg,
+		Code: http.StatusBadRequest,
+	}
+}
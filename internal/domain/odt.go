@@ -19,6 +19,10 @@ type SongDataByApi struct {
 	Date time.Time `json:"releaseDate"` // Дата выпуска песни
 	Text string    `json:"text"`        // Текст песни
 	Link string    `json:"link"`        // Ссылка на песню
+
+	Album *AlbumByApi `json:"album,omitempty"` // Альбом, если API его предоставляет
+
+	Sources MetadataSources `json:"sources,omitempty"` // Источник каждого поля, заполняет цепочка источников
 }
 
 // BaseError - шаблон ошибки
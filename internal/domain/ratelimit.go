@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Виды клиентов для правил ограничения запросов
+const (
+	RateLimitIP   = "ip"   // Анонимный клиент по IP-адресу, сюда же учитываются неудачные аутентификации
+	RateLimitKey  = "key"  // Клиент с API-ключом
+	RateLimitUser = "user" // Пользователь с токеном доступа
+)
+
+// RateLimitAnyRoute - правило для всех маршрутов, их запросы учитываются вместе
+const RateLimitAnyRoute = "*"
+
+// RateLimit - не более Limit запросов за скользящее окно Window
+type RateLimit struct {
+	Limit  int64
+	Window time.Duration
+}
+
+// RateLimitResult - результат проверки запроса
+type RateLimitResult struct {
+	Allowed    bool          // Запрос разрешен
+	Limit      int64         // Ограничение окна
+	Remaining  int64         // Сколько запросов еще можно выполнить
+	Reset      time.Duration // Через сколько начнется новое окно
+	RetryAfter time.Duration // Через сколько можно повторить отклоненный запрос
+}
+
+// RateLimitRules - правила ограничения запросов по ключу вида "[kind:]METHOD /path" или "[kind:]*"
+type RateLimitRules map[string]RateLimit
+
+// ParseRateLimit разбирает ограничение вида "100/1m"
+func ParseRateLimit(value string) (RateLimit, error) {
+	limit, window, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return RateLimit{}, rateLimitError(fmt.Sprintf("expected limit/window, got %q", value))
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(limit), 10, 64)
+	if err != nil || n <= 0 {
+		return RateLimit{}, rateLimitError(fmt.Sprintf("invalid limit %q", limit))
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || d < time.Second {
+		return RateLimit{}, rateLimitError(fmt.Sprintf("invalid window %q, at least 1s is expected", window))
+	}
+
+	return RateLimit{Limit: n, Window: d}, nil
+}
+
+// ParseRateLimitRules разбирает правила вида "*=300/1m;ip:*=60/1m;POST /song=10/1m"
+func ParseRateLimitRules(value string) (RateLimitRules, error) {
+	rules := make(RateLimitRules)
+	for _, rule := range strings.Split(value, ";") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+
+		route, limit, found := strings.Cut(rule, "=")
+		if !found {
+			return nil, rateLimitError(fmt.Sprintf("expected route=limit/window, got %q", rule))
+		}
+		key, err := rateLimitRuleKey(route)
+		if err != nil {
+			return nil, err
+		}
+		rules[key], err = ParseRateLimit(limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
+// Match находит правило для клиента вида kind и маршрута: сначала правило вида клиента
+// для маршрута, затем правило маршрута, затем общие правила вида клиента и всех клиентов
+func (r RateLimitRules) Match(kind, method, path string) (string, RateLimit, bool) {
+	route := method + " " + path
+	for _, key := range []string{kind + ":" + route, route, kind + ":" + RateLimitAnyRoute, RateLimitAnyRoute} {
+		if limit, ok := r[key]; ok {
+			return key, limit, true
+		}
+	}
+
+	return "", RateLimit{}, false
+}
+
+// SlidingWindow оценивает число запросов в скользящем окне по счетчикам текущего
+// и предыдущего фиксированных окон, предыдущее окно учитывается пропорционально
+// своей доле в скользящем окне
+// allowed - запрос учтен в текущем окне
+// prev, cur - счетчики предыдущего и текущего окон
+// elapsed - сколько прошло от начала текущего окна
+func (l RateLimit) SlidingWindow(allowed bool, prev, cur int64, elapsed time.Duration) RateLimitResult {
+	weight := 1 - float64(elapsed)/float64(l.Window)
+	estimate := float64(prev)*weight + float64(cur)
+	reset := l.Window - elapsed
+
+	result := RateLimitResult{
+		Allowed:   allowed,
+		Limit:     l.Limit,
+		Remaining: int64(math.Max(0, math.Floor(float64(l.Limit)-estimate))),
+		Reset:     reset,
+	}
+	if allowed {
+		return result
+	}
+
+	// Ждем, пока доля предыдущего окна уменьшится настолько, что поместится еще один запрос
+	retry := reset
+	if cur+1 <= l.Limit && prev > 0 {
+		excess := estimate + 1 - float64(l.Limit)
+		retry = time.Duration(math.Ceil(excess / float64(prev) * float64(l.Window)))
+		if retry > reset {
+			retry = reset
+		}
+	}
+	result.RetryAfter = retry
+
+	return result
+}
+
+// rateLimitRuleKey проверяет маршрут правила и приводит его к ключу правила
+func rateLimitRuleKey(route string) (string, error) {
+	route = strings.TrimSpace(route)
+	prefix := ""
+	if kind, rest, found := strings.Cut(route, ":"); found && !strings.Contains(kind, " ") && !strings.Contains(kind, "/") {
+		switch kind {
+		case RateLimitIP, RateLimitKey, RateLimitUser:
+		default:
+			return "", rateLimitError(fmt.Sprintf("unknown client kind %q, expected %s, %s or %s", kind, RateLimitIP, RateLimitKey, RateLimitUser))
+		}
+		prefix, route = kind+":", strings.TrimSpace(rest)
+	}
+	if route == RateLimitAnyRoute {
+		return prefix + route, nil
+	}
+
+	method, path, found := strings.Cut(route, " ")
+	path = strings.TrimSpace(path)
+	if !found || !strings.HasPrefix(path, "/") {
+		return "", rateLimitError(fmt.Sprintf("expected METHOD /path or *, got %q", route))
+	}
+
+	return prefix + strings.ToUpper(method) + " " + path, nil
+}
+
+// rateLimitError возвращает ошибку разбора правил ограничения запросов
+func rateLimitError(msg string) error {
+	return &InputDataError{
+		Err:  msg,
+		Code: http.StatusBadRequest,
+	}
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Действия, записываемые в историю изменений песни
+const (
+	RevisionCreate  = "create"
+	RevisionChange  = "change"
+	RevisionDelete  = "delete"
+	RevisionRevert  = "revert"
+	RevisionRestore = "restore"
+	RevisionPurge   = "purge"
+	RevisionEnrich  = "enrich"
+)
+
+// Операции построчного сравнения текста
+const (
+	LineEqual  = "="
+	LineDelete = "-"
+	LineInsert = "+"
+)
+
+// Revision - неизменяемая ревизия песни
+type Revision struct {
+	ID        uint64    `json:"id"`                 // Идентификатор ревизии
+	SongID    uint64    `json:"songId"`             // Идентификатор песни
+	Action    string    `json:"action"`             // Действие
+	Actor     string    `json:"actor"`              // Автор изменения
+	CreatedAt time.Time `json:"createdAt"`          // Время изменения
+	Snapshot  *Song     `json:"snapshot,omitempty"` // Снимок песни после действия
+}
+
+// RevisionDiff - различия между двумя ревизиями песни
+type RevisionDiff struct {
+	SongID uint64      `json:"songId"` // Идентификатор песни
+	From   uint64      `json:"from"`   // Исходная ревизия
+	To     uint64      `json:"to"`     // Конечная ревизия
+	Fields []FieldDiff `json:"fields"` // Измененные поля
+}
+
+// FieldDiff - изменение поля песни
+type FieldDiff struct {
+	Field string      `json:"field"`           // Имя поля
+	Old   interface{} `json:"old"`             // Значение в исходной ревизии
+	New   interface{} `json:"new"`             // Значение в конечной ревизии
+	Lines []LineDiff  `json:"lines,omitempty"` // Построчные различия для текста
+}
+
+// LineDiff - строка построчного сравнения текста
+type LineDiff struct {
+	Op   string `json:"op"`   // Операция: =, -, +
+	Text string `json:"text"` // Строка
+}
+
+// DiffSongs сравнивает два снимка песни по полям, текст сравнивается построчно
+func DiffSongs(from, to Song) []FieldDiff {
+	fields := []FieldDiff{}
+	add := func(field string, old, new interface{}, changed bool) {
+		if changed {
+			fields = append(fields, FieldDiff{Field: field, Old: old, New: new})
+		}
+	}
+
+	add(FieldName, from.Name, to.Name, from.Name != to.Name)
+	add(FieldGroup, from.Group, to.Group, from.Group != to.Group)
+	add(FieldArtistID, from.ArtistID, to.ArtistID, from.ArtistID != to.ArtistID)
+	add(FieldReleaseDate, from.Date, to.Date, !from.Date.Equal(to.Date))
+	add(FieldLink, from.Link, to.Link, from.Link != to.Link)
+	if from.Text != to.Text {
+		fields = append(fields, FieldDiff{
+			Field: FieldText,
+			Old:   from.Text,
+			New:   to.Text,
+			Lines: DiffLines(from.Text, to.Text),
+		})
+	}
+
+	return fields
+}
+
+// DiffLines сравнивает тексты построчно по наибольшей общей подпоследовательности
+func DiffLines(from, to string) []LineDiff {
+	a, b := splitLines(from), splitLines(to)
+
+	// lcs[i][j] - длина общей подпоследовательности a[i:] и b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var result []LineDiff
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, LineDiff{Op: LineEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, LineDiff{Op: LineDelete, Text: a[i]})
+			i++
+		default:
+			result = append(result, LineDiff{Op: LineInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, LineDiff{Op: LineDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, LineDiff{Op: LineInsert, Text: b[j]})
+	}
+
+	return result
+}
+
+// splitLines разбивает нормализованный текст на строки
+func splitLines(text string) []string {
+	text = NormalizeText(text)
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
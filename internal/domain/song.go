@@ -2,14 +2,40 @@ package domain
 
 import "time"
 
+// Поля песни, доступные для проекции
+const (
+	FieldID          = "id"
+	FieldName        = "name"
+	FieldGroup       = "group"
+	FieldArtistID    = "artistId"
+	FieldReleaseDate = "releaseDate"
+	FieldText        = "text"
+	FieldLink        = "link"
+	FieldEnrichment  = "enrichment_status"
+	FieldSources     = "sources"
+)
+
+// SongFields - все поля песни в порядке вывода
+var SongFields = []string{FieldID, FieldName, FieldGroup, FieldArtistID, FieldReleaseDate, FieldText, FieldLink, FieldEnrichment, FieldSources}
+
+// DefaultLibFields - поля песни в библиотеке по умолчанию, текст запрашивается явно
+var DefaultLibFields = []string{FieldID, FieldName, FieldGroup, FieldArtistID, FieldReleaseDate, FieldLink, FieldEnrichment}
+
 // Song - объект песни
 type Song struct {
-	ID    uint64    `json:"id"`          // Идентификатор песни
-	Name  string    `json:"name"`        // Название песни
-	Group string    `json:"group"`       // Группа или исполнитель
-	Date  time.Time `json:"releaseDate"` // Дата выпуска песни
-	Text  string    `json:"text"`        // Текст песни
-	Link  string    `json:"link"`        // Ссылка на песню
+	ID       uint64    `json:"id"`          // Идентификатор песни
+	Name     string    `json:"name"`        // Название песни
+	Group    string    `json:"group"`       // Группа или исполнитель
+	ArtistID uint64    `json:"artistId"`    // Идентификатор исполнителя
+	Date     time.Time `json:"releaseDate"` // Дата выпуска песни
+	Text     string    `json:"text"`        // Текст песни
+	Link     string    `json:"link"`        // Ссылка на песню
+
+	EnrichmentStatus string          `json:"enrichment_status,omitempty"` // Состояние получения данных из API
+	Sources          MetadataSources `json:"sources,omitempty"`           // Источник каждого поля с данными из API
+
+	Rank    float64 `json:"rank,omitempty"`    // Релевантность при полнотекстовом поиске
+	Snippet string  `json:"snippet,omitempty"` // Фрагмент текста с подсветкой совпадений
 }
 
 // NewSong создает новый объект Song
@@ -22,3 +48,55 @@ func NewSong(name, group, text, link string, date time.Time) *Song {
 		Link:  link,
 	}
 }
+
+// IsSongField проверяет, является ли имя полем песни
+func IsSongField(name string) bool {
+	for _, field := range SongFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Project возвращает представление песни только с указанными полями,
+// релевантность и фрагмент текста добавляются, если заполнены
+func (s Song) Project(fields []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields)+2)
+	for _, field := range fields {
+		switch field {
+		case FieldID:
+			result[field] = s.ID
+		case FieldName:
+			result[field] = s.Name
+		case FieldGroup:
+			result[field] = s.Group
+		case FieldArtistID:
+			result[field] = s.ArtistID
+		case FieldReleaseDate:
+			// Дата выпуска песни, ожидающей данных из API, неизвестна
+			if s.Date.IsZero() {
+				result[field] = nil
+			} else {
+				result[field] = s.Date
+			}
+		case FieldText:
+			result[field] = s.Text
+		case FieldLink:
+			result[field] = s.Link
+		case FieldEnrichment:
+			result[field] = s.EnrichmentStatus
+		case FieldSources:
+			result[field] = s.Sources
+		}
+	}
+
+	if s.Rank != 0 {
+		result["rank"] = s.Rank
+	}
+	if s.Snippet != "" {
+		result["snippet"] = s.Snippet
+	}
+
+	return result
+}
@@ -0,0 +1,310 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Форматы файлов импорта и экспорта песен
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// songFileDateLayouts - форматы даты выпуска в CSV файле
+var songFileDateLayouts = []string{time.DateOnly, time.RFC3339}
+
+// DecodeSongs читает песни из файла формата csv, json (массив) или ndjson (объект на строке).
+// CSV файл начинается со строки заголовков: group, song или name и любые из releaseDate, text, link, album.
+// Название в поле name позволяет импортировать файл экспорта библиотеки
+// limit - наибольшее число песен, 0 - без ограничения
+func DecodeSongs(r io.Reader, format string, limit int) ([]ImportRow, error) {
+	var rows []ImportRow
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, err = decodeSongsCSV(r, limit)
+	case FormatJSON:
+		err = json.NewDecoder(r).Decode(&rows)
+		if err != nil {
+			err = songFileError(fmt.Sprintf("invalid JSON array - %v", err))
+		}
+	case FormatNDJSON:
+		rows, err = decodeSongsNDJSON(r, limit)
+	default:
+		err = songFileError(fmt.Sprintf("unsupported format %q, csv, json or ndjson is expected", format))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(rows) > limit {
+		return nil, songFileError(fmt.Sprintf("too many songs, at most %d are allowed", limit))
+	}
+	for i := range rows {
+		rows[i].Row = i + 1
+		rows[i].Sources = nil
+	}
+
+	return rows, nil
+}
+
+// decodeSongsNDJSON читает песни из JSON объектов, разделенных переводами строк
+func decodeSongsNDJSON(r io.Reader, limit int) ([]ImportRow, error) {
+	decoder := json.NewDecoder(r)
+	var rows []ImportRow
+	for decoder.More() {
+		var row ImportRow
+		if err := decoder.Decode(&row); err != nil {
+			return nil, songFileError(fmt.Sprintf("invalid JSON in row %d - %v", len(rows)+1, err))
+		}
+		rows = append(rows, row)
+		if limit > 0 && len(rows) > limit {
+			break
+		}
+	}
+
+	return rows, nil
+}
+
+// decodeSongsCSV читает песни из CSV по строке заголовков
+func decodeSongsCSV(r io.Reader, limit int) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, songFileError(fmt.Sprintf("invalid CSV header - %v", err))
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	if i, ok := columns[FieldName]; ok {
+		if _, found := columns["song"]; !found {
+			columns["song"] = i
+		}
+	}
+	for _, name := range []string{"group", "song"} {
+		if _, ok := columns[name]; !ok {
+			return nil, songFileError(fmt.Sprintf("CSV column %s is missing", name))
+		}
+	}
+
+	var rows []ImportRow
+	for limit <= 0 || len(rows) <= limit {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, songFileError(fmt.Sprintf("invalid CSV in row %d - %v", len(rows)+1, err))
+		}
+
+		value := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := ImportRow{
+			SongDataByUser: SongDataByUser{Group: value("group"), Name: value("song")},
+			SongDataByApi:  SongDataByApi{Text: value("text"), Link: value("link")},
+		}
+		if date := value("releaseDate"); date != "" {
+			row.Date, err = parseSongFileDate(date)
+			if err != nil {
+				return nil, songFileError(fmt.Sprintf("invalid releaseDate %q in row %d, YYYY-MM-DD is expected", date, len(rows)+1))
+			}
+		}
+		if album := value("album"); album != "" {
+			row.Album = &AlbumByApi{Title: album}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseSongFileDate разбирает дату выпуска в одном из форматов файла
+func parseSongFileDate(value string) (time.Time, error) {
+	var err error
+	for _, layout := range songFileDateLayouts {
+		var date time.Time
+		if date, err = time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// SongFileFormat определяет формат файла с песнями по типу содержимого, если не удалось - пустая строка
+func SongFileFormat(contentType string) string {
+	contentType = strings.ToLower(contentType)
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return FormatCSV
+	case strings.Contains(contentType, "ndjson"), strings.Contains(contentType, "jsonl"):
+		return FormatNDJSON
+	case strings.Contains(contentType, "json"):
+		return FormatJSON
+	}
+
+	return ""
+}
+
+// DefaultExportFields - поля песни при экспорте по умолчанию
+var DefaultExportFields = []string{FieldID, FieldName, FieldGroup, FieldArtistID, FieldReleaseDate, FieldText, FieldLink}
+
+// songFileContentTypes - типы содержимого файлов с песнями
+var songFileContentTypes = map[string]string{
+	FormatCSV:    "text/csv; charset=utf-8",
+	FormatJSON:   "application/json; charset=utf-8",
+	FormatNDJSON: "application/x-ndjson; charset=utf-8",
+}
+
+// SongFileContentType возвращает тип содержимого файла с песнями формата format
+func SongFileContentType(format string) string {
+	return songFileContentTypes[format]
+}
+
+// SongEncoder записывает песни в файл по одной, не накапливая их в памяти.
+// Заголовок CSV и начало JSON массива записываются вместе с первой песней или при закрытии
+type SongEncoder struct {
+	w       io.Writer
+	csv     *csv.Writer
+	format  string
+	fields  []string
+	started bool // Заголовок или начало массива уже записаны
+	count   int  // Число записанных песен
+}
+
+// NewSongEncoder создает запись песен в w в формате csv, json или ndjson
+// fields - записываемые поля песни, по умолчанию DefaultExportFields
+func NewSongEncoder(w io.Writer, format string, fields []string) (*SongEncoder, error) {
+	if _, ok := songFileContentTypes[format]; !ok {
+		return nil, songFileError(fmt.Sprintf("unsupported format %q, csv, json or ndjson is expected", format))
+	}
+	if len(fields) == 0 {
+		fields = DefaultExportFields
+	}
+
+	encoder := &SongEncoder{w: w, format: format, fields: fields}
+	if format == FormatCSV {
+		encoder.csv = csv.NewWriter(w)
+	}
+
+	return encoder, nil
+}
+
+// Encode записывает песню
+func (e *SongEncoder) Encode(song Song) error {
+	if err := e.begin(); err != nil {
+		return err
+	}
+	e.count++
+
+	if e.csv != nil {
+		record := make([]string, 0, len(e.fields))
+		for _, field := range e.fields {
+			value, err := csvValue(song, field)
+			if err != nil {
+				return err
+			}
+			record = append(record, value)
+		}
+		return e.csv.Write(record)
+	}
+
+	data, err := json.Marshal(song.Project(e.fields))
+	if err != nil {
+		return err
+	}
+	if e.format == FormatJSON && e.count > 1 {
+		data = append([]byte(",\n"), data...)
+	}
+	if e.format == FormatNDJSON {
+		data = append(data, '\n')
+	}
+	_, err = e.w.Write(data)
+
+	return err
+}
+
+// flush передает записанные песни в w
+func (e *SongEncoder) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+
+	return nil
+}
+
+// Close завершает файл, пустой файл получает заголовок CSV или пустой JSON массив
+func (e *SongEncoder) Close() error {
+	if err := e.begin(); err != nil {
+		return err
+	}
+	if e.format == FormatJSON {
+		if _, err := io.WriteString(e.w, "\n]\n"); err != nil {
+			return err
+		}
+	}
+
+	return e.flush()
+}
+
+// begin записывает заголовок CSV или начало JSON массива перед первой песней
+func (e *SongEncoder) begin() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	switch e.format {
+	case FormatCSV:
+		return e.csv.Write(e.fields)
+	case FormatJSON:
+		_, err := io.WriteString(e.w, "[\n")
+		return err
+	}
+
+	return nil
+}
+
+// csvValue возвращает значение поля песни для CSV: дата в формате YYYY-MM-DD, источники в JSON
+func csvValue(song Song, field string) (string, error) {
+	switch field {
+	case FieldReleaseDate:
+		if song.Date.IsZero() {
+			return "", nil
+		}
+		return song.Date.Format(time.DateOnly), nil
+	case FieldSources:
+		if len(song.Sources) == 0 {
+			return "", nil
+		}
+		data, err := json.Marshal(song.Sources)
+		return string(data), err
+	}
+
+	return fmt.Sprint(song.Project([]string{field})[field]), nil
+}
+
+// songFileError возвращает ошибку разбора файла с песнями
+func songFileError(msg string) error {
+	return &InputDataError{
+		Err:  msg,
+		Code: http.StatusBadRequest,
+	}
+}
@@ -0,0 +1,27 @@
+package domain
+
+// Виды классификаторов песен
+const (
+	TagKindTag   = "tag"   // Произвольная метка
+	TagKindGenre = "genre" // Жанр
+)
+
+// Tag - жанр или произвольная метка песни
+type Tag struct {
+	ID    uint64 `json:"id"`    // Идентификатор
+	Name  string `json:"name"`  // Название
+	Songs uint64 `json:"songs"` // Количество песен, только для чтения
+}
+
+// TagBinding - привязка или отвязка жанров и меток у набора песен
+type TagBinding struct {
+	Songs  []Id     `json:"songs"`  // Идентификаторы песен
+	Tags   []string `json:"tags"`   // Названия меток
+	Genres []string `json:"genres"` // Названия жанров
+}
+
+// TagFilter - фильтр библиотеки по жанрам или меткам
+type TagFilter struct {
+	Names []string // Названия
+	All   bool     // Песня должна иметь все названия, иначе хотя бы одно
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Виды вывода текста песни
+const (
+	TextViewSingle     = "single"
+	TextViewSideBySide = "side-by-side"
+)
+
+// Translation - перевод текста песни на язык
+type Translation struct {
+	SongID   uint64 `json:"songId"`   // Идентификатор песни
+	Lang     string `json:"lang"`     // Код языка BCP-47
+	Text     string `json:"text"`     // Текст перевода
+	Sections Lyrics `json:"sections"` // Разделы перевода, выровненные по оригиналу
+}
+
+// TranslationByUser - текст перевода от пользователя
+type TranslationByUser struct {
+	Text string `json:"text"` // Текст перевода
+}
+
+// SideBySide - раздел оригинала вместе с разделом перевода
+type SideBySide struct {
+	Original    TextSection  `json:"original"`    // Раздел оригинала
+	Translation *TextSection `json:"translation"` // Раздел перевода, null если перевода нет
+}
+
+// CanonicalLang проверяет код языка BCP-47 и приводит его к каноническому виду, например pt-BR
+func CanonicalLang(tag string) (string, error) {
+	parsed, err := language.Parse(tag)
+	if err != nil || parsed == language.Und {
+		return "", &InputDataError{
+			Err:  fmt.Sprintf("Invalid language tag %q", tag),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return parsed.String(), nil
+}
+
+// LangFallbacks возвращает коды языков для поиска перевода: сам код
+// и его сокращения по подтегам, например zh-Hant-TW, zh-Hant, zh
+func LangFallbacks(tag string) []string {
+	parts := strings.Split(tag, "-")
+	result := make([]string, 0, len(parts))
+	for i := len(parts); i > 0; i-- {
+		// Одиночный подтег открывает расширение и без продолжения не имеет смысла
+		if i < len(parts) && len(parts[i-1]) == 1 {
+			continue
+		}
+		result = append(result, strings.Join(parts[:i], "-"))
+	}
+
+	return result
+}
+
+// AlignLyrics выравнивает разделы перевода по разделам оригинала: количество
+// разделов должно совпадать, тип раздела берется из оригинала, метка - из перевода,
+// если переводчик ее указал
+func AlignLyrics(original, translation Lyrics) (Lyrics, error) {
+	if len(original) != len(translation) {
+		return nil, &InputDataError{
+			Err:  fmt.Sprintf("Translation has %d sections, the original has %d", len(translation), len(original)),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	aligned := make(Lyrics, len(translation))
+	for i, section := range translation {
+		section.Type = original[i].Type
+		if section.Label == "" {
+			section.Label = original[i].Label
+		}
+		aligned[i] = section
+	}
+
+	return aligned, nil
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// TrashedSong - песня в корзине
+type TrashedSong struct {
+	Song
+	DeletedAt time.Time  `json:"deletedAt"`         // Время перемещения в корзину
+	PurgeAt   *time.Time `json:"purgeAt,omitempty"` // Время окончательного удаления по сроку хранения
+}
@@ -0,0 +1,104 @@
+package domain
+
+import "time"
+
+// Ограничения учетных данных пользователя
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 64
+	MinPasswordLength = 8
+	MaxPasswordLength = 72 // Ограничение bcrypt в байтах
+)
+
+// Типы токенов
+const (
+	TokenAccess  = "access"
+	TokenRefresh = "refresh"
+	TokenBearer  = "Bearer"
+)
+
+// User - пользователь
+type User struct {
+	ID           uint64    `json:"id"`        // Идентификатор пользователя
+	Username     string    `json:"username"`  // Имя пользователя
+	Role         string    `json:"role"`      // Роль пользователя
+	PasswordHash string    `json:"-"`         // Хэш пароля
+	CreatedAt    time.Time `json:"createdAt"` // Время регистрации
+}
+
+// Credentials - учетные данные для регистрации и входа
+type Credentials struct {
+	Username string `json:"username"` // Имя пользователя
+	Password string `json:"password"` // Пароль
+}
+
+// TokenPair - токены доступа и обновления
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`  // Токен доступа
+	RefreshToken string `json:"refresh_token"` // Токен обновления
+	TokenType    string `json:"token_type"`    // Тип токена для заголовка Authorization
+	ExpiresIn    int64  `json:"expires_in"`    // Время жизни токена доступа в секундах
+}
+
+// RefreshRequest - запрос на обновление токенов
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"` // Токен обновления
+}
+
+// Session - сессия пользователя, с которой связаны его токены
+type Session struct {
+	ID         string    // Идентификатор сессии
+	UserID     uint64    // Идентификатор пользователя
+	Role       string    // Текущая роль пользователя
+	RefreshJTI string    // Идентификатор действующего токена обновления
+	ExpiresAt  time.Time // Время окончания сессии
+	Revoked    bool      // Сессия отозвана
+}
+
+// Principal - пользователь, выполняющий запрос
+type Principal struct {
+	UserID    uint64   // Идентификатор пользователя
+	Username  string   // Имя пользователя
+	Role      string   // Роль пользователя
+	Scopes    []string // Разрешения запроса
+	SessionID string   // Идентификатор сессии, если вход выполнен по паролю
+	APIKeyID  uint64   // Идентификатор API-ключа, если запрос выполнен по ключу
+}
+
+// HasScope проверяет, что у пользователя есть разрешение scope, admin включает все разрешения
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuthConfig - параметры аутентификации
+type AuthConfig struct {
+	Secret       []byte        // Ключ подписи токенов
+	AccessTTL    time.Duration // Время жизни токена доступа
+	RefreshTTL   time.Duration // Время жизни токена обновления и сессии
+	ProtectReads bool          // Требовать аутентификацию и для чтения
+	DefaultRole  string        // Роль новых пользователей
+	Registration string        // Режим регистрации: open, admin или closed
+}
+
+// Режимы регистрации пользователей
+const (
+	RegistrationOpen   = "open"   // Зарегистрироваться может любой клиент
+	RegistrationAdmin  = "admin"  // Пользователей регистрирует администратор
+	RegistrationClosed = "closed" // Регистрация отключена
+)
+
+// OIDCConfig - параметры проверки токенов внешнего OIDC-провайдера
+type OIDCConfig struct {
+	Issuer      string            // Издатель токенов, по нему находится документ discovery
+	Audience    string            // Ожидаемый получатель токена, пустой - не проверяется
+	RoleClaim   string            // Путь к утверждению с ролями через точку, например realm_access.roles
+	RoleMap     map[string]string // Соответствие значений утверждения ролям, пустое - значения и есть роли
+	DefaultRole string            // Роль пользователя, для которого не нашлось роли в токене
+	JWKSTTL     time.Duration     // Время жизни кэша ключей подписи
+}
@@ -0,0 +1,27 @@
+package interfaces
+
+import "song/internal/domain"
+
+// AlbumRepo представляет интерфейс для работы с альбомами
+type AlbumRepo interface {
+	// GetAlbums получает список альбомов без композиций с пагинацией
+	GetAlbums(artistID domain.Id, page domain.Page) (*[]domain.Album, error)
+
+	// GetAlbum получает альбом с композициями по идентификатору
+	GetAlbum(id domain.Id) (*domain.Album, error)
+
+	// CreateAlbum создает новый альбом с композициями
+	CreateAlbum(album domain.Album) (*domain.Id, error)
+
+	// ChangeAlbum изменяет данные альбома
+	ChangeAlbum(album domain.Album) error
+
+	// DelAlbum удаляет альбом, песни остаются в библиотеке
+	DelAlbum(id domain.Id) error
+
+	// SetTracks заменяет список композиций альбома
+	SetTracks(id domain.Id, songs []domain.Id) error
+
+	// AttachSong добавляет песню в альбом ее исполнителя, создавая альбом при необходимости
+	AttachSong(songID domain.Id, album domain.AlbumByApi) error
+}
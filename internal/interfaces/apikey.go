@@ -0,0 +1,19 @@
+package interfaces
+
+import "song/internal/domain"
+
+// APIKeyRepo представляет интерфейс для работы с API-ключами
+type APIKeyRepo interface {
+	// CreateAPIKey сохраняет ключ по его хэшу
+	CreateAPIKey(key domain.APIKey, hash string) (*domain.APIKey, error)
+
+	// GetAPIKeys получает действующие ключи пользователя
+	GetAPIKeys(userID domain.Id) (*[]domain.APIKey, error)
+
+	// UseAPIKey находит действующий ключ по хэшу, отмечает его использование
+	// и возвращает владельца с разрешениями ключа
+	UseAPIKey(hash string) (*domain.Principal, error)
+
+	// RevokeAPIKey отзывает ключ пользователя userID, при anyUser - ключ любого пользователя
+	RevokeAPIKey(id, userID domain.Id, anyUser bool) error
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import "song/internal/domain"
+
+// ArtistRepo представляет интерфейс для работы с исполнителями
+type ArtistRepo interface {
+	// GetArtists получает список исполнителей с пагинацией
+	GetArtists(page domain.Page) (*[]domain.Artist, error)
+
+	// GetArtist получает исполнителя по идентификатору
+	GetArtist(id domain.Id) (*domain.Artist, error)
+
+	// CreateArtist создает нового исполнителя
+	CreateArtist(artist domain.Artist) (*domain.Id, error)
+
+	// ChangeArtist переименовывает исполнителя во всех его песнях, actor - автор изменения.
+	// Возвращает идентификаторы измененных песен
+	ChangeArtist(artist domain.Artist, actor string) ([]domain.Id, error)
+
+	// DelArtist удаляет исполнителя без песен
+	DelArtist(id domain.Id) error
+}
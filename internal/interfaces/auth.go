@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"song/internal/domain"
+	"time"
+)
+
+// AuthRepo представляет интерфейс для работы с пользователями и их сессиями
+type AuthRepo interface {
+	// CreateUser создает нового пользователя
+	CreateUser(user domain.User) (*domain.User, error)
+
+	// GetUser получает пользователя по идентификатору
+	GetUser(id domain.Id) (*domain.User, error)
+
+	// GetUserByName получает пользователя по имени без учета регистра
+	GetUserByName(username string) (*domain.User, error)
+
+	// SetUserRole меняет роль пользователя
+	SetUserRole(id domain.Id, role string) error
+
+	// CreateSession создает сессию пользователя
+	CreateSession(session domain.Session) error
+
+	// GetSession получает сессию по идентификатору вместе с текущей ролью пользователя
+	GetSession(id string) (*domain.Session, error)
+
+	// RotateSession заменяет токен обновления сессии, если действующий токен - oldJTI
+	RotateSession(id, oldJTI, newJTI string, expiresAt time.Time) (bool, error)
+
+	// RevokeSession отзывает сессию
+	RevokeSession(id string) error
+
+	// RevokeUserSessions отзывает все сессии пользователя
+	RevokeUserSessions(userID domain.Id) error
+}
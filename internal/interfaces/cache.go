@@ -1,17 +1,26 @@
 package interfaces
 
-import "song/internal/domain"
+import (
+	"song/internal/domain"
+	"time"
+)
 
 // CacheRepo представляет интерфейс для работы с кэшом
 type CacheRepo interface {
-	// CreateKey создает ключ в кэше
-	CreateKey(domain.Id, domain.SongText) error
+	// Get получает значение ключа, если ключа нет - nil
+	Get(key string) ([]byte, error)
 
-	// GetText получает текст песни из кэша по идентификатору
-	GetText(domain.Id) (*domain.SongText, error)
+	// Set записывает значение ключа на время ttl, 0 - без срока хранения
+	Set(key string, value []byte, ttl time.Duration) error
 
-	// DelKey удаляет ключ из кэша
-	DelKey(domain.Id) error
+	// Del удаляет ключи
+	Del(keys ...string) error
+
+	// Incr увеличивает число в ключе на 1 и возвращает новое значение, отсутствующий ключ считается нулем
+	Incr(key string) (int64, error)
+
+	// Stats возвращает статистику попаданий по уровням кэша
+	Stats() []domain.CacheTierStats
 
 	// Close закрывает подключение
 	Close() error
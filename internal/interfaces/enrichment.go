@@ -0,0 +1,9 @@
+package interfaces
+
+import "song/internal/domain"
+
+// EnrichmentClient представляет интерфейс для получения дополнительных данных о песне из внешнего API
+type EnrichmentClient interface {
+	// SongInfo получает дату выпуска, текст, ссылку и альбом песни
+	SongInfo(data domain.SongDataByUser) (*domain.SongDataByApi, error)
+}
@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"song/internal/domain"
+	"time"
+)
+
+// EnrichmentRepo представляет интерфейс для работы с задачами фонового получения данных песен
+type EnrichmentRepo interface {
+	// CreateSongJob создает песню без данных из API и задачу на их получение, actor - автор изменения
+	CreateSongJob(song domain.Song, actor string) (*domain.EnrichmentJob, error)
+
+	// GetJob получает задачу по идентификатору
+	GetJob(id uint64) (*domain.EnrichmentJob, error)
+
+	// ClaimJob закрепляет за обработчиком готовую к выполнению задачу на время lease,
+	// если таких задач нет - nil
+	ClaimJob(lease time.Duration) (*domain.EnrichmentJob, error)
+
+	// CompleteJob сохраняет полученные данные песни и завершает задачу успешно
+	CompleteJob(job domain.EnrichmentJob, info domain.SongDataByApi) error
+
+	// RetryJob откладывает задачу до next после неудачной попытки
+	RetryJob(id uint64, errMsg string, next time.Time) error
+
+	// FailJob завершает задачу и получение данных песни ошибкой
+	FailJob(job domain.EnrichmentJob, errMsg string) error
+}
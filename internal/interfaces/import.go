@@ -0,0 +1,9 @@
+package interfaces
+
+import "song/internal/domain"
+
+// ImportRepo представляет интерфейс для проверки песен перед импортом
+type ImportRepo interface {
+	// ExistingSongs возвращает песни из songs, которые уже есть в библиотеке
+	ExistingSongs(songs []domain.SongDataByUser) ([]domain.SongDataByUser, error)
+}
@@ -0,0 +1,27 @@
+package interfaces
+
+import "song/internal/domain"
+
+// MetadataProvider представляет интерфейс источника дополнительных данных о песне
+type MetadataProvider interface {
+	// Name возвращает имя источника для правил порядка и учета источников полей
+	Name() string
+
+	// SongInfo получает известные источнику данные песни, если песня неизвестна - ошибка с кодом 404
+	SongInfo(data domain.SongDataByUser) (*domain.SongDataByApi, error)
+}
+
+// MetadataOverrideRepo представляет интерфейс для работы с ручными исправлениями данных песен,
+// исправления служат источником данных с наивысшим приоритетом
+type MetadataOverrideRepo interface {
+	MetadataProvider
+
+	// GetOverrides получает исправления по номеру страницы
+	GetOverrides(page domain.Page) (*[]domain.MetadataOverride, error)
+
+	// SetOverride создает или заменяет исправление песни
+	SetOverride(override domain.MetadataOverride) (*domain.MetadataOverride, error)
+
+	// DelOverride удаляет исправление по идентификатору
+	DelOverride(id uint64) error
+}
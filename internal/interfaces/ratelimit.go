@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"song/internal/domain"
+	"time"
+)
+
+// RateLimitRepo представляет интерфейс для хранения счетчиков запросов
+type RateLimitRepo interface {
+	// Allow учитывает запрос клиента key, если он укладывается в ограничение limit
+	Allow(key string, limit domain.RateLimit, now time.Time) (*domain.RateLimitResult, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import "song/internal/domain"
+
+// RevisionRepo представляет интерфейс для работы с историей изменений песен
+type RevisionRepo interface {
+	// GetRevisions получает ревизии песни без снимков
+	GetRevisions(songID domain.Id) (*[]domain.Revision, error)
+
+	// GetRevision получает ревизию песни со снимком
+	GetRevision(songID, id domain.Id) (*domain.Revision, error)
+
+	// RevertSong возвращает песню к состоянию ревизии
+	RevertSong(songID, id domain.Id, actor string) error
+}
@@ -1,21 +1,30 @@
 package interfaces
 
-import "song/internal/domain"
+import (
+	"context"
+	"song/internal/domain"
+)
 
 // SongRepo представляет интерфейс для работы с песнями
 type SongRepo interface {
-	// GetLib получает библиотеку песен с пагинацией
-	GetLib(song domain.Song, page domain.Page) (*[]domain.Song, error)
+	// GetLib получает страницу библиотеки песен
+	GetLib(filter domain.LibFilter, pagination domain.Pagination) (*domain.LibPage, error)
 
-	// GetText получает текст песни по идентификатору
-	GetText(id domain.Id) (*domain.SongText, error)
+	// ExportLib передает fn все песни библиотеки по фильтру, ошибка fn прерывает выгрузку
+	ExportLib(ctx context.Context, filter domain.LibFilter, fn func(domain.Song) error) error
 
-	// DelSong удаляет песню по идентификатору
-	DelSong(id domain.Id) error
+	// GetSong получает песню со всеми полями по идентификатору
+	GetSong(id domain.Id) (*domain.Song, error)
 
-	// ChangeSong изменяет данные песни
-	ChangeSong(song domain.Song) error
+	// GetLyrics получает разделы текста песни по идентификатору
+	GetLyrics(id domain.Id) (*domain.Lyrics, error)
 
-	// CreateSong создает новую песню
-	CreateSong(song domain.Song) (*domain.Id, error)
+	// DelSong удаляет песню по идентификатору, actor - автор изменения
+	DelSong(id domain.Id, actor string) error
+
+	// ChangeSong изменяет данные песни, actor - автор изменения
+	ChangeSong(song domain.Song, actor string) error
+
+	// CreateSong создает новую песню, actor - автор изменения
+	CreateSong(song domain.Song, actor string) (*domain.Id, error)
 }
@@ -0,0 +1,15 @@
+package interfaces
+
+import "song/internal/domain"
+
+// TagRepo представляет интерфейс для работы с жанрами и метками
+type TagRepo interface {
+	// GetTags получает жанры или метки с количеством песен
+	GetTags(kind string) (*[]domain.Tag, error)
+
+	// AttachTags привязывает жанры и метки к песням, создавая отсутствующие
+	AttachTags(binding domain.TagBinding) error
+
+	// DetachTags отвязывает жанры и метки от песен
+	DetachTags(binding domain.TagBinding) error
+}
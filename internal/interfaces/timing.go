@@ -0,0 +1,12 @@
+package interfaces
+
+import "song/internal/domain"
+
+// TimingRepo представляет интерфейс для работы с синхронизированным текстом песен
+type TimingRepo interface {
+	// GetTiming получает синхронизированный текст песни
+	GetTiming(songID domain.Id) (*domain.TimedLyrics, error)
+
+	// SetTiming заменяет синхронизированный текст песни
+	SetTiming(songID domain.Id, lines []domain.TimedLine) error
+}
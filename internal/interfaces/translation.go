@@ -0,0 +1,18 @@
+package interfaces
+
+import "song/internal/domain"
+
+// TranslationRepo представляет интерфейс для работы с переводами текстов песен
+type TranslationRepo interface {
+	// GetLangs получает языки, на которые переведен текст песни
+	GetLangs(songID domain.Id) (*[]string, error)
+
+	// GetTranslation получает первый найденный перевод из списка языков по порядку
+	GetTranslation(songID domain.Id, langs []string) (*domain.Translation, error)
+
+	// SetTranslation создает или заменяет перевод
+	SetTranslation(translation domain.Translation) error
+
+	// DelTranslation удаляет перевод
+	DelTranslation(songID domain.Id, lang string) error
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"song/internal/domain"
+	"time"
+)
+
+// TrashRepo представляет интерфейс для работы с корзиной песен
+type TrashRepo interface {
+	// GetTrash получает песни из корзины по номеру страницы
+	GetTrash(page domain.Page) (*[]domain.TrashedSong, error)
+
+	// RestoreSong возвращает песню из корзины в библиотеку
+	RestoreSong(id domain.Id, actor string) error
+
+	// PurgeSong окончательно удаляет песню из корзины
+	PurgeSong(id domain.Id, actor string) error
+
+	// PurgeExpired окончательно удаляет песни, попавшие в корзину раньше before
+	PurgeExpired(before time.Time, actor string) (int64, error)
+}
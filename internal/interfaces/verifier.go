@@ -0,0 +1,12 @@
+package interfaces
+
+import "song/internal/domain"
+
+// TokenVerifier представляет интерфейс для проверки токенов внешнего издателя
+type TokenVerifier interface {
+	// Issuer возвращает издателя, токены которого проверяются
+	Issuer() string
+
+	// Verify проверяет токен и возвращает пользователя с разрешениями его роли
+	Verify(token string) (*domain.Principal, error)
+}
@@ -0,0 +1,151 @@
+// Package musicinfo - локальная замена внешнего API с данными о песнях для разработки и e2e тестов.
+// Данные берутся из файла с песнями, задержка и ошибки добавляются по настройкам,
+// полученные запросы записываются для проверки в тестах
+package musicinfo
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Config - параметры поведения API
+type Config struct {
+	Latency      time.Duration // Задержка каждого ответа
+	Jitter       time.Duration // Случайная добавка к задержке от 0 до Jitter
+	ErrorRate    float64       // Доля ответов 500 от 0 до 1
+	NotFoundRate float64       // Доля ответов 404 для известных песен от 0 до 1
+	Seed         int64         // Начальное значение генератора случайных чисел, 0 - текущее время
+}
+
+// Request - запрос, полученный API
+type Request struct {
+	Time   time.Time `json:"time"`   // Время получения
+	Group  string    `json:"group"`  // Группа из запроса
+	Song   string    `json:"song"`   // Название песни из запроса
+	Status int       `json:"status"` // Код ответа
+}
+
+// Server - API с данными о песнях
+type Server struct {
+	config Config
+	songs  interfaces.MetadataProvider
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	requests []Request
+	record   func(Request)
+}
+
+// NewServer создает API
+// config - параметры поведения
+// songs - источник данных о песнях, например каталог из файла
+// record - вызывается для каждого запроса, nil - запросы только хранятся в памяти
+func NewServer(config Config, songs interfaces.MetadataProvider, record func(Request)) *Server {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &Server{
+		config: config,
+		songs:  songs,
+		rand:   rand.New(rand.NewSource(seed)),
+		record: record,
+	}
+}
+
+// Handler возвращает обработчик маршрутов API:
+// GET /info - данные песни, GET /requests - полученные запросы, DELETE /requests - очистка запросов
+func (s *Server) Handler() http.Handler {
+	gin.SetMode(gin.ReleaseMode)
+	srv := gin.New()
+
+	srv.GET("/info", s.info)
+	srv.GET("/requests", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, s.Requests())
+	})
+	srv.DELETE("/requests", func(ctx *gin.Context) {
+		s.Reset()
+		ctx.Status(http.StatusNoContent)
+	})
+
+	return srv
+}
+
+// Requests возвращает полученные запросы к /info в порядке получения
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request{}, s.requests...)
+}
+
+// Reset очищает полученные запросы
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+}
+
+// info отвечает данными песни после задержки, часть ответов заменяется ошибками
+func (s *Server) info(ctx *gin.Context) {
+	data := domain.SongDataByUser{Group: ctx.Query("group"), Name: ctx.Query("song")}
+	delay, failed, notFound := s.faults()
+	time.Sleep(delay)
+
+	status, body := s.answer(data, failed, notFound)
+	s.save(Request{Time: time.Now(), Group: data.Group, Song: data.Name, Status: status})
+
+	ctx.JSON(status, body)
+}
+
+// answer возвращает код и тело ответа для песни data
+func (s *Server) answer(data domain.SongDataByUser, failed, notFound bool) (int, interface{}) {
+	switch {
+	case data.Group == "" || data.Name == "":
+		return http.StatusBadRequest, map[string]string{"error": "group and song are required"}
+	case failed:
+		return http.StatusInternalServerError, map[string]string{"error": "injected failure"}
+	case notFound:
+		return http.StatusNotFound, map[string]string{"error": "injected not found"}
+	}
+
+	info, err := s.songs.SongInfo(data)
+	if err != nil {
+		return http.StatusNotFound, map[string]string{"error": fmt.Sprintf("song %q by %q is not found", data.Name, data.Group)}
+	}
+
+	return http.StatusOK, info
+}
+
+// faults выбирает задержку и внедряемые ошибки для очередного запроса
+func (s *Server) faults() (time.Duration, bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delay := s.config.Latency
+	if s.config.Jitter > 0 {
+		delay += time.Duration(s.rand.Int63n(int64(s.config.Jitter) + 1))
+	}
+
+	return delay, s.rand.Float64() < s.config.ErrorRate, s.rand.Float64() < s.config.NotFoundRate
+}
+
+// save сохраняет запрос и передает его record
+func (s *Server) save(request Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, request)
+	s.mu.Unlock()
+
+	if s.record != nil {
+		s.record(request)
+	}
+}
@@ -0,0 +1,67 @@
+package musicinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/realization"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer создает API с песнями из файла test/fixtures/musicinfo.json
+func newTestServer(t *testing.T, config Config) (*Server, *httptest.Server) {
+	_ = logger.NewLogger()
+	songs, err := realization.NewCatalogProvider("../../test/fixtures/musicinfo.json")
+	assert.NoError(t, err)
+
+	server := NewServer(config, songs, nil)
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+
+	return server, ts
+}
+
+// getInfo запрашивает данные песни
+func getInfo(t *testing.T, ts *httptest.Server, group, song string) *http.Response {
+	resp, err := http.Get(ts.URL + "/info?" + url.Values{"group": {group}, "song": {song}}.Encode())
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+// Тест для /info с песней из файла и с неизвестной песней, запросы записываются
+func TestServer_Info(t *testing.T) {
+	server, ts := newTestServer(t, Config{})
+
+	resp := getInfo(t, ts, "muse", "Hysteria")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var info domain.SongDataByApi
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	assert.Equal(t, "https://www.youtube.com/watch?v=3dm_5qWWDV8", info.Link)
+	assert.Equal(t, "Absolution", info.Album.Title)
+
+	assert.Equal(t, http.StatusNotFound, getInfo(t, ts, "Muse", "Unknown").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, getInfo(t, ts, "Muse", "").StatusCode)
+
+	requests := server.Requests()
+	assert.Len(t, requests, 3)
+	assert.Equal(t, Request{Time: requests[0].Time, Group: "muse", Song: "Hysteria", Status: http.StatusOK}, requests[0])
+
+	server.Reset()
+	assert.Empty(t, server.Requests())
+}
+
+// Тест для /info с внедренными ошибками
+func TestServer_Info_Faults(t *testing.T) {
+	_, ts := newTestServer(t, Config{ErrorRate: 1})
+	assert.Equal(t, http.StatusInternalServerError, getInfo(t, ts, "Muse", "Hysteria").StatusCode)
+
+	_, ts = newTestServer(t, Config{NotFoundRate: 1})
+	assert.Equal(t, http.StatusNotFound, getInfo(t, ts, "Muse", "Hysteria").StatusCode)
+}
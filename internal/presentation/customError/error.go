@@ -16,3 +16,7 @@ type LoggerBuildError = domain.BaseError
 type RedisQueryError = domain.BaseError
 
 type InvalidInputData = domain.BaseError
+
+type ConflictError = domain.BaseError
+
+type UpstreamError = domain.BaseError
@@ -0,0 +1,6 @@
+-- Удаление индексов и поисковых векторов
+DROP INDEX IF EXISTS song_search_en_idx;
+DROP INDEX IF EXISTS song_search_ru_idx;
+ALTER TABLE song
+    DROP COLUMN IF EXISTS search_en,
+    DROP COLUMN IF EXISTS search_ru;
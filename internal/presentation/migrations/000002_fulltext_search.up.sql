@@ -0,0 +1,18 @@
+-- Поисковые векторы по названию, исполнителю и тексту песни.
+-- Конфигурация russian разбирает латиницу английским стеммером, поэтому
+-- используется по умолчанию, english - для поиска по англоязычным песням
+ALTER TABLE song
+    ADD COLUMN search_ru TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(song_name, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(group_name, '')), 'B') ||
+        setweight(to_tsvector('russian', coalesce(text, '')), 'C')
+    ) STORED,
+    ADD COLUMN search_en TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(song_name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(group_name, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(text, '')), 'C')
+    ) STORED;
+
+-- Индексы для полнотекстового поиска
+CREATE INDEX song_search_ru_idx ON song USING GIN (search_ru);
+CREATE INDEX song_search_en_idx ON song USING GIN (search_en);
@@ -0,0 +1,4 @@
+-- Отвязка песен от исполнителей и удаление таблицы artist
+DROP INDEX IF EXISTS song_artist_id_idx;
+ALTER TABLE song DROP COLUMN IF EXISTS artist_id;
+DROP TABLE IF EXISTS artist;
@@ -0,0 +1,23 @@
+-- Создание таблицы artist
+CREATE TABLE artist (
+    id      SERIAL PRIMARY KEY,     -- Идентификатор исполнителя
+    name    VARCHAR(255) NOT NULL   -- Название группы или исполнителя
+);
+
+-- Названия исполнителей уникальны без учета регистра
+CREATE UNIQUE INDEX artist_name_idx ON artist (lower(name));
+
+-- Заполнение исполнителей из существующих песен
+INSERT INTO artist (name)
+SELECT DISTINCT ON (lower(group_name)) group_name
+FROM song
+ORDER BY lower(group_name), group_name;
+
+-- Привязка песен к исполнителям, group_name остается копией названия исполнителя
+ALTER TABLE song ADD COLUMN artist_id INTEGER REFERENCES artist (id);
+UPDATE song SET artist_id = artist.id, group_name = artist.name
+FROM artist
+WHERE lower(artist.name) = lower(song.group_name);
+ALTER TABLE song ALTER COLUMN artist_id SET NOT NULL;
+
+CREATE INDEX song_artist_id_idx ON song (artist_id);
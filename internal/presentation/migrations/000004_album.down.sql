@@ -0,0 +1,3 @@
+-- Удаление таблиц album_track и album
+DROP TABLE IF EXISTS album_track;
+DROP TABLE IF EXISTS album;
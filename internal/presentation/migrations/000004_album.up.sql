@@ -0,0 +1,21 @@
+-- Создание таблицы album
+CREATE TABLE album (
+    id              SERIAL PRIMARY KEY,                         -- Идентификатор альбома
+    title           VARCHAR(255) NOT NULL,                      -- Название альбома
+    artist_id       INTEGER NOT NULL REFERENCES artist (id),    -- Исполнитель альбома
+    release_date    DATE                                        -- Дата выпуска альбома
+);
+
+-- Название альбома уникально у исполнителя без учета регистра
+CREATE UNIQUE INDEX album_artist_title_idx ON album (artist_id, lower(title));
+
+-- Создание таблицы album_track со списком композиций альбома
+CREATE TABLE album_track (
+    album_id    INTEGER NOT NULL REFERENCES album (id) ON DELETE CASCADE,   -- Альбом
+    song_id     INTEGER NOT NULL REFERENCES song (id) ON DELETE CASCADE,    -- Песня
+    position    INTEGER NOT NULL CHECK (position > 0),                      -- Номер композиции в альбоме
+    PRIMARY KEY (album_id, position),
+    UNIQUE (album_id, song_id)
+);
+
+CREATE INDEX album_track_song_id_idx ON album_track (song_id);
@@ -0,0 +1,5 @@
+-- Удаление жанров, меток и их связей с песнями
+DROP TABLE IF EXISTS song_tag;
+DROP TABLE IF EXISTS song_genre;
+DROP TABLE IF EXISTS tag;
+DROP TABLE IF EXISTS genre;
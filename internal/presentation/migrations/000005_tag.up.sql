@@ -0,0 +1,33 @@
+-- Создание таблицы genre
+CREATE TABLE genre (
+    id      SERIAL PRIMARY KEY,     -- Идентификатор жанра
+    name    VARCHAR(255) NOT NULL   -- Название жанра
+);
+
+CREATE UNIQUE INDEX genre_name_idx ON genre (lower(name));
+
+-- Создание таблицы tag с произвольными метками
+CREATE TABLE tag (
+    id      SERIAL PRIMARY KEY,     -- Идентификатор метки
+    name    VARCHAR(255) NOT NULL   -- Название метки
+);
+
+CREATE UNIQUE INDEX tag_name_idx ON tag (lower(name));
+
+-- Связи песен с жанрами
+CREATE TABLE song_genre (
+    song_id     INTEGER NOT NULL REFERENCES song (id) ON DELETE CASCADE,
+    genre_id    INTEGER NOT NULL REFERENCES genre (id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, genre_id)
+);
+
+CREATE INDEX song_genre_genre_id_idx ON song_genre (genre_id);
+
+-- Связи песен с метками
+CREATE TABLE song_tag (
+    song_id     INTEGER NOT NULL REFERENCES song (id) ON DELETE CASCADE,
+    tag_id      INTEGER NOT NULL REFERENCES tag (id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, tag_id)
+);
+
+CREATE INDEX song_tag_tag_id_idx ON song_tag (tag_id);
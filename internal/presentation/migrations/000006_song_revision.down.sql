@@ -0,0 +1,3 @@
+-- Удаление истории изменений песен
+DROP TABLE IF EXISTS song_revision;
+DROP FUNCTION IF EXISTS song_revision_immutable();
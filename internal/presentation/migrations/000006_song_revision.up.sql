@@ -0,0 +1,36 @@
+-- Создание таблицы song_revision с историей изменений песен.
+-- Ссылки на song нет, чтобы история переживала удаление песни
+CREATE TABLE song_revision (
+    id          SERIAL PRIMARY KEY,                     -- Идентификатор ревизии
+    song_id     INTEGER NOT NULL,                       -- Идентификатор песни
+    action      VARCHAR(16) NOT NULL,                   -- Действие: create, change, delete, revert
+    snapshot    JSONB NOT NULL,                         -- Полный снимок песни после действия
+    actor       VARCHAR(255) NOT NULL,                  -- Автор изменения
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()      -- Время изменения
+);
+
+CREATE INDEX song_revision_song_id_idx ON song_revision (song_id, id);
+
+-- Ревизии неизменяемы
+CREATE FUNCTION song_revision_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'song_revision is immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER song_revision_immutable
+    BEFORE UPDATE OR DELETE ON song_revision
+    FOR EACH ROW EXECUTE FUNCTION song_revision_immutable();
+
+-- Начальные ревизии для существующих песен
+INSERT INTO song_revision (song_id, action, snapshot, actor)
+SELECT id, 'create', jsonb_build_object(
+    'id', id,
+    'name', song_name,
+    'group', group_name,
+    'artistId', artist_id,
+    'releaseDate', to_char(release_date, 'YYYY-MM-DD') || 'T00:00:00Z',
+    'text', text,
+    'link', link
+), 'migration'
+FROM song;
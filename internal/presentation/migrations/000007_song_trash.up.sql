@@ -0,0 +1,4 @@
+-- Мягкое удаление песен: удаленные песни попадают в корзину
+ALTER TABLE song ADD COLUMN deleted_at TIMESTAMPTZ NULL; -- Время перемещения в корзину
+
+CREATE INDEX song_deleted_at_idx ON song (deleted_at) WHERE deleted_at IS NOT NULL;
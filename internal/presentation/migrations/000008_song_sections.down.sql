@@ -0,0 +1,2 @@
+-- Удаление разделов текста песни
+ALTER TABLE song DROP COLUMN IF EXISTS sections;
@@ -0,0 +1,71 @@
+-- Структурированный текст песни: разделы с типом, меткой и строками
+ALTER TABLE song ADD COLUMN sections JSONB NOT NULL DEFAULT '[]'; -- Разделы текста песни
+
+-- Тип раздела по метке, повторяет domain.SectionType
+CREATE FUNCTION lyrics_section_type(label TEXT) RETURNS TEXT AS $$
+    SELECT CASE
+        WHEN l ~ '^(intro|вступление)' THEN 'intro'
+        WHEN l ~ '^(pre-?chorus|предприпев)' THEN 'pre-chorus'
+        WHEN l ~ '^(chorus|refrain|hook|припев)' THEN 'chorus'
+        WHEN l ~ '^(bridge|бридж)' THEN 'bridge'
+        WHEN l ~ '^(outro|аутро|концовка)' THEN 'outro'
+        ELSE 'verse'
+    END
+    FROM lower(label) AS l;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Нормализация текста, повторяет domain.NormalizeText
+CREATE FUNCTION lyrics_normalize(body TEXT) RETURNS TEXT AS $$
+    SELECT btrim(
+        regexp_replace(
+            regexp_replace(
+                replace(replace(replace(coalesce(body, ''), '\n', E'\n'), E'\r\n', E'\n'), E'\r', E'\n'),
+                '[ \t]+(\n|$)', '\1', 'g'),
+            '\n{3,}', E'\n\n', 'g'),
+        E'\n');
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Разбиение нормализованного текста на разделы, повторяет domain.ParseLyrics
+CREATE FUNCTION lyrics_sections(body TEXT) RETURNS JSONB AS $$
+DECLARE
+    line     TEXT;
+    marker   TEXT[];
+    sections JSONB := '[]';
+    kind     TEXT := 'verse';
+    label    TEXT := NULL;
+    lines    JSONB := '[]';
+BEGIN
+    FOREACH line IN ARRAY string_to_array(body, E'\n') || ''::TEXT LOOP
+        marker := regexp_match(line, '^\[([^\]]+)\]$');
+        IF line = '' OR marker IS NOT NULL THEN
+            IF jsonb_array_length(lines) > 0 THEN
+                sections := sections || jsonb_build_array(jsonb_strip_nulls(jsonb_build_object(
+                    'index', jsonb_array_length(sections) + 1,
+                    'type', kind,
+                    'label', label,
+                    'lines', lines
+                )));
+                kind := 'verse';
+                label := NULL;
+                lines := '[]';
+            END IF;
+            IF marker IS NOT NULL THEN
+                label := btrim(marker[1]);
+                kind := lyrics_section_type(label);
+            END IF;
+        ELSE
+            lines := lines || to_jsonb(line);
+        END IF;
+    END LOOP;
+
+    RETURN sections;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Сохраненный текст не меняется, разделы строятся по его нормализованной копии
+UPDATE song SET sections = lyrics_sections(lyrics_normalize(text)) WHERE text IS NOT NULL AND text <> '';
+
+-- Дальше разделы формирует приложение
+DROP FUNCTION lyrics_sections(TEXT);
+DROP FUNCTION lyrics_normalize(TEXT);
+DROP FUNCTION lyrics_section_type(TEXT);
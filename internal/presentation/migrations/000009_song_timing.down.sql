@@ -0,0 +1,2 @@
+-- Удаление синхронизированного текста песен
+DROP TABLE IF EXISTS song_timing;
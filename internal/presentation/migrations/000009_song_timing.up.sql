@@ -0,0 +1,9 @@
+-- Создание таблицы song_timing со строками текста, синхронизированными по времени
+CREATE TABLE song_timing (
+    song_id     INTEGER NOT NULL REFERENCES song (id) ON DELETE CASCADE, -- Идентификатор песни
+    position    INTEGER NOT NULL,                                        -- Номер строки по порядку времени
+    time_ms     BIGINT NOT NULL,                                         -- Время начала строки в миллисекундах
+    text        TEXT NOT NULL,                                           -- Текст строки
+    words       JSONB NULL,                                              -- Слова с временем начала из расширенного LRC
+    PRIMARY KEY (song_id, position)
+);
@@ -0,0 +1,2 @@
+-- Удаление переводов текстов песен
+DROP TABLE IF EXISTS song_translation;
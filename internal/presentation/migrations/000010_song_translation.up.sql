@@ -0,0 +1,8 @@
+-- Создание таблицы song_translation с переводами текстов песен
+CREATE TABLE song_translation (
+    song_id     INTEGER NOT NULL REFERENCES song (id) ON DELETE CASCADE, -- Идентификатор песни
+    lang        VARCHAR(35) NOT NULL,                                    -- Код языка BCP-47 в каноническом виде
+    text        TEXT NOT NULL,                                           -- Текст перевода
+    sections    JSONB NOT NULL,                                          -- Разделы перевода, выровненные по оригиналу
+    PRIMARY KEY (song_id, lang)
+);
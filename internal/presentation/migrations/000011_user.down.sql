@@ -0,0 +1,3 @@
+-- Удаление пользователей и сессий
+DROP TABLE IF EXISTS auth_session;
+DROP TABLE IF EXISTS app_user;
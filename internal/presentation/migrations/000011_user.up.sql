@@ -0,0 +1,21 @@
+-- Создание таблицы app_user с пользователями
+CREATE TABLE app_user (
+    id              SERIAL PRIMARY KEY,                     -- Идентификатор пользователя
+    username        VARCHAR(64) NOT NULL,                   -- Имя пользователя
+    password_hash   TEXT NOT NULL,                          -- Хэш пароля bcrypt
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()      -- Время регистрации
+);
+
+CREATE UNIQUE INDEX app_user_username_idx ON app_user (lower(username));
+
+-- Создание таблицы auth_session с сессиями, отзыв сессии отзывает все ее токены
+CREATE TABLE auth_session (
+    id              VARCHAR(32) PRIMARY KEY,                                    -- Идентификатор сессии
+    user_id         INTEGER NOT NULL REFERENCES app_user (id) ON DELETE CASCADE, -- Идентификатор пользователя
+    refresh_jti     VARCHAR(32) NOT NULL,                                       -- Действующий токен обновления
+    expires_at      TIMESTAMPTZ NOT NULL,                                       -- Время окончания сессии
+    revoked_at      TIMESTAMPTZ NULL,                                           -- Время отзыва
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()                          -- Время входа
+);
+
+CREATE INDEX auth_session_user_id_idx ON auth_session (user_id);
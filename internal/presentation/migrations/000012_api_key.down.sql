@@ -0,0 +1,4 @@
+-- Удаление API-ключей и ролей
+DROP TABLE IF EXISTS api_key;
+
+ALTER TABLE app_user DROP COLUMN IF EXISTS role;
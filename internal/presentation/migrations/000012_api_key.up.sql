@@ -0,0 +1,21 @@
+-- Роли пользователей, все зарегистрированные ранее пользователи могли изменять библиотеку
+ALTER TABLE app_user ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'editor'
+    CHECK (role IN ('viewer', 'editor', 'admin'));
+
+-- Первый пользователь становится администратором
+UPDATE app_user SET role = 'admin' WHERE id = (SELECT min(id) FROM app_user);
+
+-- Создание таблицы api_key с API-ключами, хранится только хэш ключа
+CREATE TABLE api_key (
+    id              SERIAL PRIMARY KEY,                                          -- Идентификатор ключа
+    user_id         INTEGER NOT NULL REFERENCES app_user (id) ON DELETE CASCADE, -- Владелец ключа
+    name            VARCHAR(64) NOT NULL,                                        -- Название ключа
+    prefix          VARCHAR(16) NOT NULL,                                        -- Начало ключа для его узнавания
+    key_hash        CHAR(64) NOT NULL UNIQUE,                                    -- SHA-256 ключа в hex
+    scopes          TEXT[] NOT NULL,                                             -- Разрешения ключа
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),                          -- Время создания
+    last_used_at    TIMESTAMPTZ NULL,                                            -- Время последнего использования
+    revoked_at      TIMESTAMPTZ NULL                                             -- Время отзыва
+);
+
+CREATE INDEX api_key_user_id_idx ON api_key (user_id);
@@ -0,0 +1,4 @@
+-- Удаление задач фонового получения данных
+DROP TABLE IF EXISTS enrichment_job;
+
+ALTER TABLE song DROP COLUMN IF EXISTS enrichment_status;
@@ -0,0 +1,20 @@
+-- Состояние получения дополнительных данных песни, существующие песни уже получили данные
+ALTER TABLE song ADD COLUMN enrichment_status VARCHAR(16) NOT NULL DEFAULT 'succeeded'
+    CHECK (enrichment_status IN ('pending', 'succeeded', 'failed'));
+
+-- Создание таблицы enrichment_job с задачами фонового получения данных песен
+CREATE TABLE enrichment_job (
+    id              SERIAL PRIMARY KEY,                                       -- Идентификатор задачи
+    song_id         INTEGER NOT NULL REFERENCES song (id) ON DELETE CASCADE, -- Идентификатор песни
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'succeeded', 'failed')),                 -- Состояние задачи
+    attempts        INTEGER NOT NULL DEFAULT 0,                               -- Число выполненных попыток
+    last_error      TEXT NULL,                                                -- Ошибка последней попытки
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),                       -- Время следующей попытки
+    locked_until    TIMESTAMPTZ NULL,                                         -- Задача закреплена за обработчиком до этого времени
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),                       -- Время создания
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()                        -- Время изменения
+);
+
+CREATE INDEX enrichment_job_pending_idx ON enrichment_job (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX enrichment_job_song_id_idx ON enrichment_job (song_id);
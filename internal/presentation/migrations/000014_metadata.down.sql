@@ -0,0 +1,4 @@
+-- Удаление ручных исправлений данных песен
+DROP TABLE IF EXISTS metadata_override;
+
+ALTER TABLE song DROP COLUMN IF EXISTS metadata_sources;
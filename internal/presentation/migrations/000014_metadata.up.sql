@@ -0,0 +1,17 @@
+-- Источник каждого поля с дополнительными данными песни, существующие песни получили данные из API
+ALTER TABLE song ADD COLUMN metadata_sources JSONB NOT NULL DEFAULT '{}';
+UPDATE song SET metadata_sources = '{"releaseDate": "api", "text": "api", "link": "api"}'
+WHERE enrichment_status = 'succeeded';
+
+-- Создание таблицы metadata_override с ручными исправлениями данных песен
+CREATE TABLE metadata_override (
+    id              SERIAL PRIMARY KEY,         -- Идентификатор исправления
+    group_name      VARCHAR(255) NOT NULL,      -- Название группы или исполнителя
+    song_name       VARCHAR(255) NOT NULL,      -- Название песни
+    release_date    DATE NULL,                  -- Дата выпуска песни
+    text            TEXT NULL,                  -- Текст песни
+    link            VARCHAR(255) NULL           -- Ссылка на песню
+);
+
+-- Для песни хранится одно исправление, названия сравниваются без учета регистра
+CREATE UNIQUE INDEX metadata_override_song_idx ON metadata_override (lower(group_name), lower(song_name));
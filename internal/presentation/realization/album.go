@@ -0,0 +1,327 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AlbumRepo - реализация репозитория для работы с альбомами в базе данных
+type AlbumRepo struct{}
+
+func NewAlbumRepo() *AlbumRepo {
+	return &AlbumRepo{}
+}
+
+// GetAlbums получает список альбомов по номеру страницы
+// artistID - идентификатор исполнителя, 0 - все альбомы
+// page - номер страницы для пагинации
+func (r *AlbumRepo) GetAlbums(artistID domain.Id, page domain.Page) (*[]domain.Album, error) {
+	query := psql.Select("album.id", "album.title", "album.artist_id", "artist.name", "album.release_date").
+		From("album").
+		Join("artist ON artist.id = album.artist_id").
+		OrderBy("album.id").
+		Offset(uint64(20 * (page - 1))).
+		Limit(20)
+	if artistID != 0 {
+		query = query.Where("album.artist_id = ?", artistID)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка генерации SQL-запроса: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, sqlQuery, args...)
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	result := []domain.Album{}
+	for rows.Next() {
+		var album domain.Album
+		var date sql.NullTime
+		if err := rows.Scan(&album.ID, &album.Title, &album.ArtistID, &album.Artist, &date); err != nil {
+			return nil, &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		album.Date = date.Time
+		result = append(result, album)
+	}
+
+	return &result, nil
+}
+
+// GetAlbum получает альбом с композициями по идентификатору
+// id - идентификатор альбома
+func (r *AlbumRepo) GetAlbum(id domain.Id) (*domain.Album, error) {
+	var album domain.Album
+	var date sql.NullTime
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT album.id, album.title, album.artist_id, artist.name, album.release_date
+		FROM album JOIN artist ON artist.id = album.artist_id WHERE album.id = $1`, id).
+		Scan(&album.ID, &album.Title, &album.ArtistID, &album.Artist, &date)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &e.RowsNotFoundError{
+				Err:  "Альбом с таким идентификатором не существует",
+				Code: http.StatusNotFound,
+			}
+		}
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	album.Date = date.Time
+
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, `SELECT album_track.position, album_track.song_id, song.song_name
+		FROM album_track JOIN song ON song.id = album_track.song_id
+		WHERE album_track.album_id = $1 AND song.deleted_at IS NULL ORDER BY album_track.position`, id)
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	album.Tracks = []domain.Track{}
+	for rows.Next() {
+		var track domain.Track
+		if err := rows.Scan(&track.Position, &track.SongID, &track.Name); err != nil {
+			return nil, &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		album.Tracks = append(album.Tracks, track)
+	}
+
+	return &album, nil
+}
+
+// CreateAlbum создает новый альбом, композиции нумеруются в порядке списка
+// album - объект нового альбома
+func (r *AlbumRepo) CreateAlbum(album domain.Album) (*domain.Id, error) {
+	var id uint64
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := withTx(timeoutCtx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(timeoutCtx, `INSERT INTO album (title, artist_id, release_date) VALUES ($1, $2, $3) RETURNING id`,
+			album.Title, album.ArtistID, nullDate(album.Date)).Scan(&id)
+		if err != nil {
+			return albumQueryError(err)
+		}
+
+		songs := make([]domain.Id, 0, len(album.Tracks))
+		for _, track := range album.Tracks {
+			songs = append(songs, track.SongID)
+		}
+
+		return insertTracks(timeoutCtx, tx, id, songs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// ChangeAlbum изменяет данные альбома
+// album - объект альбома с новыми данными
+func (r *AlbumRepo) ChangeAlbum(album domain.Album) error {
+	query := psql.Update("album").Where("id = ?", album.ID)
+
+	if album.Title != "" {
+		query = query.Set("title", album.Title)
+	}
+	if album.ArtistID != 0 {
+		query = query.Set("artist_id", album.ArtistID)
+	}
+	if !album.Date.IsZero() {
+		query = query.Set("release_date", album.Date)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return &e.DbQueryError{
+			Err:  fmt.Sprintf("Sql query generation error: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := postgres.DbService.Db.ExecContext(timeoutCtx, sqlQuery, args...)
+	if err != nil {
+		return albumQueryError(err)
+	}
+
+	return checkAlbumAffected(res)
+}
+
+// DelAlbum удаляет альбом вместе со списком композиций
+// id - идентификатор альбома
+func (r *AlbumRepo) DelAlbum(id domain.Id) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := postgres.DbService.Db.ExecContext(timeoutCtx, "DELETE FROM album WHERE id = $1", id)
+	if err != nil {
+		return albumQueryError(err)
+	}
+
+	return checkAlbumAffected(res)
+}
+
+// SetTracks заменяет список композиций альбома, номера присваиваются в порядке списка
+// id - идентификатор альбома
+// songs - идентификаторы песен
+func (r *AlbumRepo) SetTracks(id domain.Id, songs []domain.Id) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		var albumID uint64
+		err := tx.QueryRowContext(timeoutCtx, `SELECT id FROM album WHERE id = $1 FOR UPDATE`, id).Scan(&albumID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &e.RowsNotFoundError{
+					Err:  "Альбом с таким идентификатором не существует",
+					Code: http.StatusNotFound,
+				}
+			}
+			return albumQueryError(err)
+		}
+
+		_, err = tx.ExecContext(timeoutCtx, `DELETE FROM album_track WHERE album_id = $1`, id)
+		if err != nil {
+			return albumQueryError(err)
+		}
+
+		return insertTracks(timeoutCtx, tx, id, songs)
+	})
+}
+
+// AttachSong добавляет песню в альбом ее исполнителя, альбом создается, если его нет.
+// Если номер композиции занят или не указан, песня добавляется в конец альбома
+// songID - идентификатор песни
+// album - данные об альбоме
+func (r *AlbumRepo) AttachSong(songID domain.Id, album domain.AlbumByApi) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		var albumID uint64
+		err := tx.QueryRowContext(timeoutCtx, `INSERT INTO album (title, artist_id, release_date)
+			SELECT $1, artist_id, $3 FROM song WHERE id = $2
+			ON CONFLICT (artist_id, (lower(title))) DO UPDATE SET title = album.title
+			RETURNING id`, album.Title, songID, nullDate(album.Date)).Scan(&albumID)
+		if err != nil {
+			return albumQueryError(err)
+		}
+
+		_, err = tx.ExecContext(timeoutCtx, `INSERT INTO album_track (album_id, song_id, position)
+			SELECT $1, $2, CASE
+				WHEN $3 > 0 AND bool_or(position = $3) IS NOT TRUE THEN $3
+				ELSE COALESCE(MAX(position), 0) + 1
+			END
+			FROM album_track WHERE album_id = $1
+			ON CONFLICT (album_id, song_id) DO NOTHING`, albumID, songID, album.Track)
+		if err != nil {
+			return albumQueryError(err)
+		}
+
+		return nil
+	})
+}
+
+// insertTracks добавляет композиции альбома с номерами по порядку
+func insertTracks(ctx context.Context, tx *sql.Tx, albumID domain.Id, songs []domain.Id) error {
+	for i, songID := range songs {
+		_, err := tx.ExecContext(ctx, `INSERT INTO album_track (album_id, song_id, position) VALUES ($1, $2, $3)`, albumID, songID, i+1)
+		if err != nil {
+			return albumQueryError(err)
+		}
+	}
+
+	return nil
+}
+
+// nullDate преобразует нулевую дату в NULL
+func nullDate(date time.Time) sql.NullTime {
+	return sql.NullTime{Time: date, Valid: !date.IsZero()}
+}
+
+// checkAlbumAffected возвращает ошибку, если запрос не затронул ни одного альбома
+func checkAlbumAffected(res sql.Result) error {
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return &e.RowsNotFoundError{
+			Err:  "Альбом с таким идентификатором не существует",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return nil
+}
+
+// albumQueryError преобразует ошибку базы данных при изменении альбомов
+func albumQueryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return &e.ConflictError{
+				Err:  "Album with this title already exists for the artist or the song is already in the album",
+				Code: http.StatusConflict,
+			}
+		case pqForeignKeyViolation:
+			return &e.InvalidInputData{
+				Err:  "Artist or song with this id not exist",
+				Code: http.StatusBadRequest,
+			}
+		}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return &e.InvalidInputData{
+			Err:  "Song with this id not exist",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return &e.DbQueryError{
+		Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
@@ -0,0 +1,116 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/postgres"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// APIKeyRepo - реализация репозитория для работы с API-ключами в базе данных
+type APIKeyRepo struct{}
+
+func NewAPIKeyRepo() *APIKeyRepo {
+	return &APIKeyRepo{}
+}
+
+// CreateAPIKey сохраняет ключ по его хэшу
+// key - данные ключа
+// hash - SHA-256 ключа в hex
+func (r *APIKeyRepo) CreateAPIKey(key domain.APIKey, hash string) (*domain.APIKey, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `INSERT INTO api_key (user_id, name, prefix, key_hash, scopes)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		key.UserID, key.Name, key.Prefix, hash, pq.Array(key.Scopes)).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return nil, authQueryError(err)
+	}
+
+	return &key, nil
+}
+
+// GetAPIKeys получает действующие ключи пользователя
+// userID - идентификатор пользователя
+func (r *APIKeyRepo) GetAPIKeys(userID domain.Id) (*[]domain.APIKey, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, `SELECT id, user_id, name, prefix, scopes, created_at, last_used_at
+		FROM api_key WHERE user_id = $1 AND revoked_at IS NULL ORDER BY id`, userID)
+	if err != nil {
+		return nil, authQueryError(err)
+	}
+	defer rows.Close()
+
+	keys := make([]domain.APIKey, 0)
+	for rows.Next() {
+		var key domain.APIKey
+		err = rows.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, pq.Array(&key.Scopes), &key.CreatedAt, &key.LastUsedAt)
+		if err != nil {
+			return nil, authQueryError(err)
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, authQueryError(err)
+	}
+
+	return &keys, nil
+}
+
+// UseAPIKey находит действующий ключ по хэшу, отмечает его использование
+// и возвращает владельца с разрешениями ключа
+// hash - SHA-256 ключа в hex
+func (r *APIKeyRepo) UseAPIKey(hash string) (*domain.Principal, error) {
+	var principal domain.Principal
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `UPDATE api_key k SET last_used_at = now()
+		FROM app_user u WHERE u.id = k.user_id AND k.key_hash = $1 AND k.revoked_at IS NULL
+		RETURNING k.id, k.user_id, u.username, u.role, k.scopes`, hash).
+		Scan(&principal.APIKeyID, &principal.UserID, &principal.Username, &principal.Role, pq.Array(&principal.Scopes))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &e.RowsNotFoundError{
+				Err:  "Ключ не существует",
+				Code: http.StatusNotFound,
+			}
+		}
+		return nil, authQueryError(err)
+	}
+
+	return &principal, nil
+}
+
+// RevokeAPIKey отзывает ключ
+// id - идентификатор ключа
+// userID - владелец ключа
+// anyUser - отозвать ключ любого пользователя, userID не проверяется
+func (r *APIKeyRepo) RevokeAPIKey(id, userID domain.Id, anyUser bool) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := postgres.DbService.Db.ExecContext(timeoutCtx, `UPDATE api_key SET revoked_at = now()
+		WHERE id = $1 AND ($2 OR user_id = $3) AND revoked_at IS NULL`, id, anyUser, userID)
+	if err != nil {
+		return authQueryError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return authQueryError(err)
+	}
+	if n == 0 {
+		return &e.RowsNotFoundError{
+			Err:  "Ключ не существует",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Коды ошибок PostgreSQL
+const (
+	pqNotNullViolation    = "23502"
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+)
+
+// queryRower - подключение или транзакция, в которых можно выполнить запрос
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// ArtistRepo - реализация репозитория для работы с исполнителями в базе данных
+type ArtistRepo struct{}
+
+func NewArtistRepo() *ArtistRepo {
+	return &ArtistRepo{}
+}
+
+// GetArtists получает список исполнителей по номеру страницы
+// page - номер страницы для пагинации
+func (r *ArtistRepo) GetArtists(page domain.Page) (*[]domain.Artist, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, `SELECT id, name FROM artist ORDER BY name, id LIMIT 20 OFFSET $1`, 20*(page-1))
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	result := []domain.Artist{}
+	for rows.Next() {
+		var artist domain.Artist
+		if err := rows.Scan(&artist.ID, &artist.Name); err != nil {
+			return nil, &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		result = append(result, artist)
+	}
+
+	return &result, nil
+}
+
+// GetArtist получает исполнителя по идентификатору
+// id - идентификатор исполнителя
+func (r *ArtistRepo) GetArtist(id domain.Id) (*domain.Artist, error) {
+	var artist domain.Artist
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT id, name FROM artist WHERE id = $1`, id).Scan(&artist.ID, &artist.Name)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &e.RowsNotFoundError{
+				Err:  "Исполнитель с таким идентификатором не существует",
+				Code: http.StatusNotFound,
+			}
+		}
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return &artist, nil
+}
+
+// CreateArtist создает нового исполнителя
+// artist - объект нового исполнителя
+func (r *ArtistRepo) CreateArtist(artist domain.Artist) (*domain.Id, error) {
+	var id uint64
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `INSERT INTO artist (name) VALUES ($1) RETURNING id`, artist.Name).Scan(&id)
+
+	if err != nil {
+		return nil, artistQueryError(err)
+	}
+
+	return &id, nil
+}
+
+// ChangeArtist переименовывает исполнителя и обновляет название во всех его песнях,
+// для каждой песни записывается ревизия. Возвращает идентификаторы измененных песен
+// artist - объект исполнителя с новыми данными
+// actor - автор изменения
+func (r *ArtistRepo) ChangeArtist(artist domain.Artist, actor string) ([]domain.Id, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var ids []domain.Id
+	err := withTx(timeoutCtx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(timeoutCtx, `UPDATE artist SET name = $1 WHERE id = $2`, artist.Name, artist.ID)
+		if err != nil {
+			return artistQueryError(err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return &e.RowsNotFoundError{
+				Err:  "Исполнитель с таким идентификатором не существует",
+				Code: http.StatusNotFound,
+			}
+		}
+
+		rows, err := tx.QueryContext(timeoutCtx, `UPDATE song SET group_name = $1 WHERE artist_id = $2 RETURNING id`, artist.Name, artist.ID)
+		if err != nil {
+			return artistQueryError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id domain.Id
+			err = rows.Scan(&id)
+			if err != nil {
+				return artistQueryError(err)
+			}
+			ids = append(ids, id)
+		}
+		if err = rows.Err(); err != nil {
+			return artistQueryError(err)
+		}
+
+		return recordRevisions(timeoutCtx, tx, domain.RevisionChange, actor, "artist_id = $3", artist.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// DelArtist удаляет исполнителя, у которого нет песен
+// id - идентификатор исполнителя
+func (r *ArtistRepo) DelArtist(id domain.Id) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := postgres.DbService.Db.ExecContext(timeoutCtx, "DELETE FROM artist WHERE id = $1", id)
+
+	if err != nil {
+		return artistQueryError(err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return &e.RowsNotFoundError{
+			Err:  "Исполнитель с таким идентификатором не существует",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return nil
+}
+
+// upsertArtist находит исполнителя по названию без учета регистра или создает нового,
+// возвращает идентификатор и каноническое название исполнителя
+func upsertArtist(ctx context.Context, db queryRower, name string) (uint64, string, error) {
+	var id uint64
+	var canonical string
+	err := db.QueryRowContext(ctx, `INSERT INTO artist (name) VALUES ($1)
+		ON CONFLICT ((lower(name))) DO UPDATE SET name = artist.name
+		RETURNING id, name`, name).Scan(&id, &canonical)
+
+	if err != nil {
+		return 0, "", &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return id, canonical, nil
+}
+
+// artistQueryError преобразует ошибку базы данных при изменении исполнителей
+func artistQueryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return &e.ConflictError{
+				Err:  "Artist with this name already exists",
+				Code: http.StatusConflict,
+			}
+		case pqForeignKeyViolation:
+			return &e.ConflictError{
+				Err:  "Artist has songs, delete or move them first",
+				Code: http.StatusConflict,
+			}
+		}
+	}
+
+	return &e.DbQueryError{
+		Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
@@ -0,0 +1,34 @@
+package realization
+
+import (
+	"regexp"
+	"song/internal/domain"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для метода ChangeArtist - для каждой песни исполнителя записывается ревизия
+func TestArtistRepo_ChangeArtist(t *testing.T) {
+	sqlMock := newMockDB(t)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE artist SET name = $1 WHERE id = $2")).
+		WithArgs("Muse", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("UPDATE song SET group_name = $1 WHERE artist_id = $2 RETURNING id")).
+		WithArgs("Muse", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(9))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO song_revision (song_id, action, snapshot, actor)")).
+		WithArgs(domain.RevisionChange, "editor", 3).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	sqlMock.ExpectCommit()
+
+	repo := NewArtistRepo()
+	ids, err := repo.ChangeArtist(domain.Artist{ID: 3, Name: "Muse"}, "editor")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Id{4, 9}, ids)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,193 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/postgres"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AuthRepo - реализация репозитория для работы с пользователями и сессиями в базе данных
+type AuthRepo struct{}
+
+func NewAuthRepo() *AuthRepo {
+	return &AuthRepo{}
+}
+
+// CreateUser создает нового пользователя
+// user - пользователь с хэшем пароля и ролью
+func (r *AuthRepo) CreateUser(user domain.User) (*domain.User, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `INSERT INTO app_user (username, password_hash, role)
+		VALUES ($1, $2, $3) RETURNING id, created_at`,
+		user.Username, user.PasswordHash, user.Role).Scan(&user.ID, &user.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+			return nil, &e.ConflictError{
+				Err:  "User with this username already exists",
+				Code: http.StatusConflict,
+			}
+		}
+		return nil, authQueryError(err)
+	}
+
+	return &user, nil
+}
+
+// GetUser получает пользователя по идентификатору
+// id - идентификатор пользователя
+func (r *AuthRepo) GetUser(id domain.Id) (*domain.User, error) {
+	return r.getUser(`id = $1`, id)
+}
+
+// GetUserByName получает пользователя по имени без учета регистра
+// username - имя пользователя
+func (r *AuthRepo) GetUserByName(username string) (*domain.User, error) {
+	return r.getUser(`lower(username) = lower($1)`, username)
+}
+
+// getUser получает пользователя по условию where
+func (r *AuthRepo) getUser(where string, arg interface{}) (*domain.User, error) {
+	var user domain.User
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT id, username, role, password_hash, created_at FROM app_user WHERE `+where, arg).
+		Scan(&user.ID, &user.Username, &user.Role, &user.PasswordHash, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &e.RowsNotFoundError{
+				Err:  "Пользователь не существует",
+				Code: http.StatusNotFound,
+			}
+		}
+		return nil, authQueryError(err)
+	}
+
+	return &user, nil
+}
+
+// CreateSession создает сессию пользователя
+// session - сессия с действующим токеном обновления
+func (r *AuthRepo) CreateSession(session domain.Session) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := postgres.DbService.Db.ExecContext(timeoutCtx, `INSERT INTO auth_session (id, user_id, refresh_jti, expires_at) VALUES ($1, $2, $3, $4)`,
+		session.ID, session.UserID, session.RefreshJTI, session.ExpiresAt)
+	if err != nil {
+		return authQueryError(err)
+	}
+
+	return nil
+}
+
+// GetSession получает сессию по идентификатору вместе с текущей ролью пользователя
+// id - идентификатор сессии
+func (r *AuthRepo) GetSession(id string) (*domain.Session, error) {
+	var session domain.Session
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT s.id, s.user_id, u.role, s.refresh_jti, s.expires_at, s.revoked_at IS NOT NULL
+		FROM auth_session s JOIN app_user u ON u.id = s.user_id WHERE s.id = $1`, id).
+		Scan(&session.ID, &session.UserID, &session.Role, &session.RefreshJTI, &session.ExpiresAt, &session.Revoked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &e.RowsNotFoundError{
+				Err:  "Сессия не существует",
+				Code: http.StatusNotFound,
+			}
+		}
+		return nil, authQueryError(err)
+	}
+
+	return &session, nil
+}
+
+// RotateSession заменяет токен обновления действующей сессии, если ее токен - oldJTI
+// id - идентификатор сессии
+// oldJTI - предъявленный токен обновления
+// newJTI - новый токен обновления
+// expiresAt - новое время окончания сессии
+func (r *AuthRepo) RotateSession(id, oldJTI, newJTI string, expiresAt time.Time) (bool, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := postgres.DbService.Db.ExecContext(timeoutCtx, `UPDATE auth_session SET refresh_jti = $3, expires_at = $4
+		WHERE id = $1 AND refresh_jti = $2 AND revoked_at IS NULL AND expires_at > now()`, id, oldJTI, newJTI, expiresAt)
+	if err != nil {
+		return false, authQueryError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, authQueryError(err)
+	}
+
+	return n == 1, nil
+}
+
+// RevokeSession отзывает сессию
+// id - идентификатор сессии
+func (r *AuthRepo) RevokeSession(id string) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := postgres.DbService.Db.ExecContext(timeoutCtx, `UPDATE auth_session SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return authQueryError(err)
+	}
+
+	return nil
+}
+
+// RevokeUserSessions отзывает все сессии пользователя
+// userID - идентификатор пользователя
+func (r *AuthRepo) RevokeUserSessions(userID domain.Id) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := postgres.DbService.Db.ExecContext(timeoutCtx, `UPDATE auth_session SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	if err != nil {
+		return authQueryError(err)
+	}
+
+	return nil
+}
+
+// SetUserRole меняет роль пользователя
+// id - идентификатор пользователя
+// role - новая роль
+func (r *AuthRepo) SetUserRole(id domain.Id, role string) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := postgres.DbService.Db.ExecContext(timeoutCtx, `UPDATE app_user SET role = $2 WHERE id = $1`, id, role)
+	if err != nil {
+		return authQueryError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return authQueryError(err)
+	}
+	if n == 0 {
+		return &e.RowsNotFoundError{
+			Err:  "Пользователь не существует",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return nil
+}
+
+// authQueryError преобразует ошибку базы данных при работе с пользователями
+func authQueryError(err error) error {
+	return &e.DbQueryError{
+		Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
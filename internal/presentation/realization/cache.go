@@ -0,0 +1,170 @@
+package realization
+
+import (
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MemoryCache - хранилище кэша в памяти процесса для запуска без Redis.
+// Значения вытесняются при переполнении, счетчики Incr не вытесняются,
+// чтобы поколение библиотеки не вернулось к прежнему значению
+type MemoryCache struct {
+	mu       sync.Mutex
+	items    *lruCache
+	counters map[string]int64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewMemoryCache создает хранилище кэша в памяти
+// entries - наибольшее число значений
+func NewMemoryCache(entries int) *MemoryCache {
+	logger.Logger.Info(fmt.Sprintf("Memory cache for %d entries was created", entries))
+	return &MemoryCache{
+		items:    newLRUCache(entries),
+		counters: make(map[string]int64),
+	}
+}
+
+// Get получает значение ключа, если ключа нет - nil
+// key - ключ
+func (m *MemoryCache) Get(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if counter, ok := m.counters[key]; ok {
+		m.hits.Add(1)
+		return []byte(strconv.FormatInt(counter, 10)), nil
+	}
+	value, ok := m.items.get(key, time.Now())
+	if !ok {
+		m.misses.Add(1)
+		return nil, nil
+	}
+
+	m.hits.Add(1)
+	return value, nil
+}
+
+// Set записывает значение ключа
+// key - ключ
+// value - значение
+// ttl - время хранения, 0 - без срока хранения
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	delete(m.counters, key)
+	m.items.set(key, value, expires)
+
+	return nil
+}
+
+// Del удаляет ключи
+// keys - ключи
+func (m *MemoryCache) Del(keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.counters, key)
+	}
+	m.items.del(keys...)
+
+	return nil
+}
+
+// Incr увеличивает число в ключе на 1
+// key - ключ
+func (m *MemoryCache) Incr(key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter, ok := m.counters[key]
+	if !ok {
+		if value, found := m.items.get(key, time.Now()); found {
+			var err error
+			counter, err = strconv.ParseInt(string(value), 10, 64)
+			if err != nil {
+				return 0, &domain.BaseError{
+					Err:  fmt.Sprintf("Значение ключа %s не является числом", key),
+					Code: http.StatusInternalServerError,
+				}
+			}
+			m.items.del(key)
+		}
+	}
+
+	counter++
+	m.counters[key] = counter
+	return counter, nil
+}
+
+// Stats возвращает статистику попаданий в память
+func (m *MemoryCache) Stats() []domain.CacheTierStats {
+	m.mu.Lock()
+	counters := len(m.counters)
+	m.mu.Unlock()
+	entries, evictions := m.items.stats()
+
+	return []domain.CacheTierStats{{
+		Tier:      domain.CacheTierMemory,
+		Hits:      m.hits.Load(),
+		Misses:    m.misses.Load(),
+		Evictions: evictions,
+		Entries:   entries + counters,
+	}}
+}
+
+func (m *MemoryCache) Close() error {
+	return nil
+}
+
+// NoopCache - хранилище кэша, которое ничего не хранит: каждое чтение - промах
+type NoopCache struct{}
+
+// NewNoopCache создает хранилище без кэширования
+func NewNoopCache() *NoopCache {
+	logger.Logger.Info("Caching is disabled")
+	return &NoopCache{}
+}
+
+// Get всегда возвращает отсутствие значения
+func (n *NoopCache) Get(key string) ([]byte, error) {
+	return nil, nil
+}
+
+// Set ничего не записывает
+func (n *NoopCache) Set(key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+// Del ничего не удаляет
+func (n *NoopCache) Del(keys ...string) error {
+	return nil
+}
+
+// Incr не хранит счетчик и всегда возвращает 0
+func (n *NoopCache) Incr(key string) (int64, error) {
+	return 0, nil
+}
+
+// Stats возвращает пустую статистику
+func (n *NoopCache) Stats() []domain.CacheTierStats {
+	return []domain.CacheTierStats{}
+}
+
+func (n *NoopCache) Close() error {
+	return nil
+}
@@ -0,0 +1,77 @@
+package realization
+
+import (
+	"errors"
+	"song/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для хранилища кэша в памяти - счетчики не вытесняются значениями
+func TestMemoryCache_Incr(t *testing.T) {
+	cache := NewMemoryCache(1)
+
+	_ = cache.Set("counter", []byte("5"), time.Minute)
+	value, err := cache.Incr("counter")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), value)
+
+	_ = cache.Set("a", []byte("1"), 0)
+	_ = cache.Set("b", []byte("2"), 0)
+
+	data, _ := cache.Get("counter")
+	assert.Equal(t, []byte("6"), data)
+	data, _ = cache.Get("a")
+	assert.Nil(t, data)
+	data, _ = cache.Get("b")
+	assert.Equal(t, []byte("2"), data)
+}
+
+// Тест для хранилища, ошибки которого считаются промахами
+func TestResilientCache_Failure(t *testing.T) {
+	inner := new(mock.MockCacheRepo)
+	cache := NewResilientCache(inner, 0, time.Minute)
+	redisErr := errors.New("connection refused")
+
+	inner.On("Get", "song").Return([]byte(nil), redisErr).Once()
+	inner.On("Set", "song", []byte("value"), time.Minute).Return(redisErr).Once()
+	// Увеличение не вызывается: перед ним снова не удается удалить ключ
+	inner.On("Del", []string{"lyrics"}).Return(redisErr).Twice()
+
+	value, err := cache.Get("song")
+	assert.Nil(t, value)
+	assert.Nil(t, err)
+	assert.Nil(t, cache.Set("song", []byte("value"), time.Minute))
+	assert.Nil(t, cache.Del("lyrics"))
+	generation, err := cache.Incr("generation")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), generation)
+
+	// После восстановления сначала изменяются ключи, которые не удалось изменить
+	inner.On("Del", []string{"lyrics"}).Return(nil).Once()
+	inner.On("Incr", "generation").Return(int64(3), nil).Once()
+	inner.On("Get", "song").Return([]byte("value"), nil).Once()
+
+	value, err = cache.Get("song")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), value)
+	inner.AssertExpectations(t)
+}
+
+// Тест для хранилища, ошибки которого считаются промахами - после ошибок подряд хранилище не вызывается
+func TestResilientCache_Breaker(t *testing.T) {
+	inner := new(mock.MockCacheRepo)
+	cache := NewResilientCache(inner, 2, time.Minute)
+
+	inner.On("Get", "song").Return([]byte(nil), errors.New("timeout")).Twice()
+
+	for i := 0; i < 3; i++ {
+		value, err := cache.Get("song")
+		assert.Nil(t, value)
+		assert.Nil(t, err)
+	}
+
+	inner.AssertNumberOfCalls(t, "Get", 2)
+}
@@ -0,0 +1,65 @@
+package realization
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"strings"
+)
+
+// CatalogProvider - источник данных о песнях из локального JSON или CSV файла.
+// Каталог читается один раз при создании, песни ищутся по группе и названию без учета регистра
+type CatalogProvider struct {
+	songs map[string]domain.SongDataByApi
+}
+
+// NewCatalogProvider читает каталог из файла path, формат определяется по расширению .json или .csv.
+// CSV файл начинается со строки заголовков: group, song и любые из releaseDate, text, link, album
+func NewCatalogProvider(path string) (*CatalogProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("catalog opening error - %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if format != domain.FormatJSON && format != domain.FormatCSV {
+		return nil, fmt.Errorf("catalog %s has unsupported format %q, .json or .csv is expected", path, filepath.Ext(path))
+	}
+
+	entries, err := domain.DecodeSongs(file, format, 0)
+	if err != nil {
+		return nil, fmt.Errorf("catalog %s reading error - %v", path, err)
+	}
+
+	songs := make(map[string]domain.SongDataByApi, len(entries))
+	for _, entry := range entries {
+		songs[domain.SongKey(entry.SongDataByUser)] = entry.SongDataByApi
+	}
+
+	return &CatalogProvider{songs: songs}, nil
+}
+
+// Name возвращает имя источника данных
+func (p *CatalogProvider) Name() string {
+	return domain.MetadataProviderCatalog
+}
+
+// SongInfo находит песню в каталоге
+// data - группа и название песни
+func (p *CatalogProvider) SongInfo(data domain.SongDataByUser) (*domain.SongDataByApi, error) {
+	info, ok := p.songs[domain.SongKey(data)]
+	if !ok {
+		return nil, &e.RowsNotFoundError{
+			Err:  "song is not found in the catalog",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,66 @@
+package realization
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"song/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCatalog записывает каталог во временный файл name
+func writeCatalog(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// Тест для метода SongInfo с JSON каталогом
+func TestCatalogProvider_JSON(t *testing.T) {
+	path := writeCatalog(t, "catalog.json", `[
+		{"group": "Muse", "song": "Hysteria", "releaseDate": "2003-12-01T00:00:00Z", "text": "text", "album": {"title": "Absolution"}}
+	]`)
+
+	provider, err := NewCatalogProvider(path)
+	assert.NoError(t, err)
+
+	info, err := provider.SongInfo(domain.SongDataByUser{Group: "muse", Name: "HYSTERIA"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2003, 12, 1, 0, 0, 0, 0, time.UTC), info.Date)
+	assert.Equal(t, "text", info.Text)
+	assert.Equal(t, "Absolution", info.Album.Title)
+	assert.Empty(t, info.Link)
+}
+
+// Тест для метода SongInfo с CSV каталогом и неизвестной песней
+func TestCatalogProvider_CSV(t *testing.T) {
+	path := writeCatalog(t, "catalog.csv", "song,group,link,releaseDate\n"+
+		"Hysteria,Muse,https://example.com/hysteria,2003-12-01\n")
+
+	provider, err := NewCatalogProvider(path)
+	assert.NoError(t, err)
+
+	info, err := provider.SongInfo(domain.SongDataByUser{Group: "Muse", Name: "Hysteria"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/hysteria", info.Link)
+	assert.Equal(t, time.Date(2003, 12, 1, 0, 0, 0, 0, time.UTC), info.Date)
+
+	_, err = provider.SongInfo(domain.SongDataByUser{Group: "Muse", Name: "Uprising"})
+	assert.Equal(t, http.StatusNotFound, err.(*domain.BaseError).Code)
+}
+
+// Тест для NewCatalogProvider с некорректными файлами
+func TestNewCatalogProvider_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"catalog.csv":  "title,link\nHysteria,link\n",
+		"catalog.xml":  "<songs/>",
+		"catalog.json": "{",
+	} {
+		_, err := NewCatalogProvider(writeCatalog(t, name, content))
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,191 @@
+package realization
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"time"
+)
+
+// maxEnrichmentBody - наибольший размер ответа API
+const maxEnrichmentBody = 1 << 20
+
+// EnrichmentClient - клиент API с дополнительными данными о песнях.
+// Ошибки сети и 5xx повторяются с паузой со случайным разбросом, после серии ошибок
+// запросы на время перестают отправляться, чтобы не нагружать упавший API
+type EnrichmentClient struct {
+	config  domain.EnrichmentConfig
+	client  *http.Client
+	breaker *circuitBreaker
+	sleep   func(context.Context, time.Duration) error
+}
+
+// NewEnrichmentClient создает новый клиент API
+func NewEnrichmentClient(config domain.EnrichmentConfig) *EnrichmentClient {
+	return &EnrichmentClient{
+		config:  config,
+		client:  &http.Client{Timeout: config.Timeout},
+		breaker: newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
+		sleep:   sleepContext,
+	}
+}
+
+// Name возвращает имя источника данных
+func (c *EnrichmentClient) Name() string {
+	return domain.MetadataProviderAPI
+}
+
+// SongInfo получает дату выпуска, текст, ссылку и альбом песни
+// data - группа и название песни
+func (c *EnrichmentClient) SongInfo(data domain.SongDataByUser) (*domain.SongDataByApi, error) {
+	if c.config.BaseURL == nil {
+		return nil, &e.UpstreamError{
+			Err:  "song info API is not configured",
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	// JoinPath возвращает копию, адрес API не меняется между запросами
+	infoUrl := c.config.BaseURL.JoinPath("info")
+	params := url.Values{}
+	params.Add("group", data.Group)
+	params.Add("song", data.Name)
+	infoUrl.RawQuery = params.Encode()
+
+	ctx := context.Background()
+	var err error
+	for attempt := 0; attempt <= c.config.Retries; attempt++ {
+		if attempt > 0 {
+			delay := c.backoff(attempt)
+			logger.Logger.Warn(fmt.Sprintf("Song info request failed, retry %d in %v - %v", attempt, delay, err))
+			if sleepErr := c.sleep(ctx, delay); sleepErr != nil {
+				break
+			}
+		}
+
+		if !c.breaker.allow() {
+			return nil, &e.UpstreamError{
+				Err:  "song info API is unavailable, requests are paused",
+				Code: http.StatusServiceUnavailable,
+			}
+		}
+
+		var info *domain.SongDataByApi
+		var retry bool
+		info, retry, err = c.get(ctx, infoUrl.String())
+		if retry {
+			c.breaker.failure()
+			continue
+		}
+		c.breaker.success()
+
+		return info, err
+	}
+
+	return nil, err
+}
+
+// get выполняет один запрос к API, retry - ошибку стоит повторить
+func (c *EnrichmentClient) get(ctx context.Context, url string) (*domain.SongDataByApi, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, &e.UpstreamError{
+			Err:  fmt.Sprintf("error creating request - %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		code := http.StatusBadGateway
+		var netErr interface{ Timeout() bool }
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			code = http.StatusGatewayTimeout
+		}
+		return nil, true, &e.UpstreamError{
+			Err:  fmt.Sprintf("error making request - %v", err),
+			Code: code,
+		}
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusBadRequest:
+		return nil, false, &e.UpstreamError{
+			Err:  fmt.Sprintf("song info API rejected the song - %s", responseMessage(resp.Body)),
+			Code: http.StatusBadRequest,
+		}
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, &e.UpstreamError{
+			Err:  "song is not found by the song info API",
+			Code: http.StatusNotFound,
+		}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, true, &e.UpstreamError{
+			Err:  fmt.Sprintf("song info API failed with status %d", resp.StatusCode),
+			Code: http.StatusBadGateway,
+		}
+	default:
+		return nil, false, &e.UpstreamError{
+			Err:  fmt.Sprintf("song info API answered with unexpected status %d", resp.StatusCode),
+			Code: http.StatusBadGateway,
+		}
+	}
+
+	var info domain.SongDataByApi
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxEnrichmentBody)).Decode(&info)
+	if err != nil {
+		return nil, false, &e.UpstreamError{
+			Err:  fmt.Sprintf("error decoding response - %v", err),
+			Code: http.StatusBadGateway,
+		}
+	}
+
+	return &info, false, nil
+}
+
+// backoff возвращает паузу перед повтором attempt: случайное время от половины
+// до полной удвоенной паузы, чтобы повторы разных запросов не совпадали
+func (c *EnrichmentClient) backoff(attempt int) time.Duration {
+	delay := c.config.BackoffBase << (attempt - 1)
+	if delay <= 0 || (c.config.BackoffMax > 0 && delay > c.config.BackoffMax) {
+		delay = c.config.BackoffMax
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// responseMessage читает начало тела ответа для сообщения об ошибке
+func responseMessage(body io.Reader) string {
+	message, _ := io.ReadAll(io.LimitReader(body, 256))
+	return string(message)
+}
+
+// sleepContext ждет d или отмены ctx
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,243 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/postgres"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// jobColumns - колонки задачи с группой и названием песни, j - enrichment_job, s - song
+const jobColumns = `j.id, j.song_id, j.status, j.attempts, COALESCE(j.last_error, ''), j.next_attempt_at,
+	j.created_at, j.updated_at, s.group_name, s.song_name`
+
+// EnrichmentRepo - реализация репозитория для работы с задачами получения данных песен в базе данных
+type EnrichmentRepo struct{}
+
+func NewEnrichmentRepo() *EnrichmentRepo {
+	return &EnrichmentRepo{}
+}
+
+// CreateSongJob создает песню с данными пользователя и задачу на получение остальных данных из API
+// song - объект новой песни
+// actor - автор изменения
+func (r *EnrichmentRepo) CreateSongJob(song domain.Song, actor string) (*domain.EnrichmentJob, error) {
+	var job *domain.EnrichmentJob
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := withTx(timeoutCtx, func(tx *sql.Tx) error {
+		id, err := insertSong(timeoutCtx, tx, song, domain.EnrichmentPending)
+		if err != nil {
+			return err
+		}
+
+		err = recordRevision(timeoutCtx, tx, id, domain.RevisionCreate, actor)
+		if err != nil {
+			return err
+		}
+
+		job, err = scanJob(tx.QueryRowContext(timeoutCtx, `WITH j AS (
+			INSERT INTO enrichment_job (song_id) VALUES ($1) RETURNING *
+		) SELECT `+jobColumns+` FROM j JOIN song s ON s.id = j.song_id`, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return jobQueryError(err)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetJob получает задачу по идентификатору
+// id - идентификатор задачи
+func (r *EnrichmentRepo) GetJob(id uint64) (*domain.EnrichmentJob, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := scanJob(postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT `+jobColumns+`
+		FROM enrichment_job j JOIN song s ON s.id = j.song_id WHERE j.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &e.RowsNotFoundError{
+			Err:  "Задача с таким идентификатором не существует",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return job, err
+}
+
+// ClaimJob закрепляет за обработчиком самую раннюю готовую задачу и увеличивает число ее попыток.
+// Задачи, закрепленные за другими обработчиками, пропускаются, после lease задачу может взять другой
+// lease - время, на которое задача закрепляется
+func (r *EnrichmentRepo) ClaimJob(lease time.Duration) (*domain.EnrichmentJob, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := scanJob(postgres.DbService.Db.QueryRowContext(timeoutCtx, `UPDATE enrichment_job j
+		SET attempts = j.attempts + 1, locked_until = now() + $1 * interval '1 millisecond', updated_at = now()
+		FROM song s
+		WHERE s.id = j.song_id AND j.id = (
+			SELECT id FROM enrichment_job
+			WHERE status = 'pending' AND next_attempt_at <= now() AND (locked_until IS NULL OR locked_until < now())
+			ORDER BY next_attempt_at
+			LIMIT 1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns, lease.Milliseconds()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return job, err
+}
+
+// CompleteJob сохраняет дату выпуска, текст и ссылку песни, записывает ревизию и завершает задачу.
+// Заполняются только поля, которые все еще пусты и не изменены пользователем,
+// чтобы изменения и откаты, сделанные пока задача ждала, не терялись
+// job - выполненная задача
+// info - данные песни, полученные из API
+func (r *EnrichmentRepo) CompleteJob(job domain.EnrichmentJob, info domain.SongDataByApi) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		var date sql.NullTime
+		var text, link sql.NullString
+		sources := domain.MetadataSources{}
+		err := tx.QueryRowContext(timeoutCtx, "SELECT release_date, text, link, metadata_sources FROM song WHERE id = $1 FOR UPDATE",
+			job.SongID).Scan(&date, &text, &link, &jsonColumn{dst: &sources})
+		if err != nil {
+			return jobQueryError(err)
+		}
+
+		// Пустое поле песни без данных пользователя можно заполнить данными источника
+		empty := func(field string, isEmpty bool) bool {
+			return isEmpty && sources[field] != domain.MetadataSourceUser
+		}
+		filled := domain.MetadataSources{}
+		query := psql.Update("song").Set("enrichment_status", domain.EnrichmentSucceeded).Where("id = ?", job.SongID)
+		if !info.Date.IsZero() && empty(domain.FieldReleaseDate, !date.Valid) {
+			query = query.Set("release_date", info.Date)
+			filled[domain.FieldReleaseDate] = info.Sources[domain.FieldReleaseDate]
+		}
+		if info.Text != "" && empty(domain.FieldText, text.String == "") {
+			lyrics, sections := lyricsValues(info.Text)
+			query = query.Set("text", lyrics).Set("sections", sections)
+			filled[domain.FieldText] = info.Sources[domain.FieldText]
+		}
+		if info.Link != "" && empty(domain.FieldLink, link.String == "") {
+			query = query.Set("link", info.Link)
+			filled[domain.FieldLink] = info.Sources[domain.FieldLink]
+		}
+		if source := info.Sources[domain.FieldAlbum]; source != "" {
+			filled[domain.FieldAlbum] = source
+		}
+		for field, source := range filled {
+			if source == "" {
+				delete(filled, field)
+			}
+		}
+		if len(filled) > 0 {
+			query = query.Set("metadata_sources", sq.Expr("metadata_sources || ?::jsonb", sourcesValue(filled)))
+		}
+
+		sqlQuery, args, err := query.ToSql()
+		if err != nil {
+			return jobQueryError(err)
+		}
+		_, err = tx.ExecContext(timeoutCtx, sqlQuery, args...)
+		if err != nil {
+			return jobQueryError(err)
+		}
+
+		err = recordRevision(timeoutCtx, tx, job.SongID, domain.RevisionEnrich, domain.EnrichmentActor)
+		if err != nil {
+			return err
+		}
+
+		return finishJob(timeoutCtx, tx, job.ID, domain.EnrichmentSucceeded, "")
+	})
+}
+
+// RetryJob снимает закрепление задачи и откладывает следующую попытку
+// id - идентификатор задачи
+// errMsg - ошибка последней попытки
+// next - время следующей попытки
+func (r *EnrichmentRepo) RetryJob(id uint64, errMsg string, next time.Time) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := postgres.DbService.Db.ExecContext(timeoutCtx, `UPDATE enrichment_job
+		SET last_error = $2, next_attempt_at = $3, locked_until = NULL, updated_at = now() WHERE id = $1`, id, errMsg, next)
+	if err != nil {
+		return jobQueryError(err)
+	}
+
+	return nil
+}
+
+// FailJob завершает задачу ошибкой и отмечает, что данные песни получить не удалось
+// job - задача
+// errMsg - ошибка последней попытки
+func (r *EnrichmentRepo) FailJob(job domain.EnrichmentJob, errMsg string) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(timeoutCtx, "UPDATE song SET enrichment_status = $2 WHERE id = $1", job.SongID, domain.EnrichmentFailed)
+		if err != nil {
+			return jobQueryError(err)
+		}
+
+		return finishJob(timeoutCtx, tx, job.ID, domain.EnrichmentFailed, errMsg)
+	})
+}
+
+// finishJob переводит задачу в конечное состояние status
+func finishJob(ctx context.Context, tx *sql.Tx, id uint64, status, errMsg string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE enrichment_job
+		SET status = $2, last_error = NULLIF($3, ''), locked_until = NULL, updated_at = now() WHERE id = $1`, id, status, errMsg)
+	if err != nil {
+		return jobQueryError(err)
+	}
+
+	return nil
+}
+
+// scanJob сканирует задачу с группой и названием песни, отсутствие задачи возвращается как sql.ErrNoRows
+func scanJob(row *sql.Row) (*domain.EnrichmentJob, error) {
+	var job domain.EnrichmentJob
+	var next time.Time
+	err := row.Scan(&job.ID, &job.SongID, &job.Status, &job.Attempts, &job.LastError, &next,
+		&job.CreatedAt, &job.UpdatedAt, &job.Data.Group, &job.Data.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, jobQueryError(err)
+	}
+
+	if job.Status == domain.EnrichmentPending {
+		job.NextAttemptAt = &next
+	}
+
+	return &job, nil
+}
+
+// jobQueryError оборачивает ошибку запроса к задачам получения данных
+func jobQueryError(err error) error {
+	return &e.DbQueryError{
+		Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
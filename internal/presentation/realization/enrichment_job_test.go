@@ -0,0 +1,151 @@
+package realization
+
+import (
+	"database/sql"
+	"net/http"
+	"regexp"
+	"song/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для метода CreateSongJob - дата выпуска песни без данных из API неизвестна и сохраняется как NULL
+func TestEnrichmentRepo_CreateSongJob(t *testing.T) {
+	sqlMock := newMockDB(t)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(regexp.QuoteMeta("INSERT INTO artist (name) VALUES ($1)")).
+		WithArgs("Muse").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Muse"))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("INSERT INTO song (song_name, group_name, artist_id, release_date, text, sections, link, enrichment_status, metadata_sources)")).
+		WithArgs("Hysteria", "Muse", 3, nil, "", "[]", "", domain.EnrichmentPending, "{}").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO song_revision (song_id, action, snapshot, actor)")).
+		WithArgs(domain.RevisionCreate, "editor", 10).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("INSERT INTO enrichment_job (song_id) VALUES ($1)")).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "song_id", "status", "attempts", "last_error", "next_attempt_at",
+			"created_at", "updated_at", "group_name", "song_name"}).
+			AddRow(5, 10, domain.EnrichmentPending, 0, "", now, now, now, "Muse", "Hysteria"))
+	sqlMock.ExpectCommit()
+
+	job, err := NewEnrichmentRepo().CreateSongJob(domain.Song{Name: "Hysteria", Group: "Muse"}, "editor")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), job.SongID)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода ClaimJob - задача закрепляется на время lease вместе с данными песни
+func TestEnrichmentRepo_ClaimJob(t *testing.T) {
+	sqlMock := newMockDB(t)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("UPDATE enrichment_job j")).
+		WithArgs(int64(60000)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "song_id", "status", "attempts", "last_error", "next_attempt_at",
+			"created_at", "updated_at", "group_name", "song_name"}).
+			AddRow(5, 10, domain.EnrichmentPending, 1, "", now, now, now, "Muse", "Hysteria"))
+
+	job, err := NewEnrichmentRepo().ClaimJob(time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), job.ID)
+	assert.Equal(t, domain.SongDataByUser{Group: "Muse", Name: "Hysteria"}, job.Data)
+	assert.Equal(t, now, *job.NextAttemptAt)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода ClaimJob без готовых задач
+func TestEnrichmentRepo_ClaimJob_Empty(t *testing.T) {
+	sqlMock := newMockDB(t)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("UPDATE enrichment_job j")).WillReturnError(sql.ErrNoRows)
+
+	job, err := NewEnrichmentRepo().ClaimJob(time.Minute)
+
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+// Тест для метода GetJob с несуществующей задачей
+func TestEnrichmentRepo_GetJob_NotFound(t *testing.T) {
+	sqlMock := newMockDB(t)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta("FROM enrichment_job j JOIN song s")).WithArgs(7).WillReturnError(sql.ErrNoRows)
+
+	_, err := NewEnrichmentRepo().GetJob(7)
+
+	assert.Equal(t, http.StatusNotFound, err.(*domain.BaseError).Code)
+}
+
+// Тест для метода CompleteJob - поля, измененные пока задача ждала, не перезаписываются
+func TestEnrichmentRepo_CompleteJob_EditedWhilePending(t *testing.T) {
+	sqlMock := newMockDB(t)
+	date := time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT release_date, text, link, metadata_sources FROM song WHERE id = $1 FOR UPDATE")).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"release_date", "text", "link", "metadata_sources"}).
+			AddRow(nil, "edited text", "", `{"text":"user"}`))
+	sqlMock.ExpectExec(regexp.QuoteMeta(
+		"UPDATE song SET enrichment_status = $1, release_date = $2, link = $3, metadata_sources = metadata_sources || $4::jsonb WHERE id = $5",
+	)).
+		WithArgs(domain.EnrichmentSucceeded, date, "link", `{"link":"catalog","releaseDate":"catalog"}`, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO song_revision (song_id, action, snapshot, actor)")).
+		WithArgs(domain.RevisionEnrich, domain.EnrichmentActor, 10).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE enrichment_job")).
+		WithArgs(5, domain.EnrichmentSucceeded, "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	err := NewEnrichmentRepo().CompleteJob(domain.EnrichmentJob{ID: 5, SongID: 10}, domain.SongDataByApi{
+		Date: date, Text: "provider text", Link: "link",
+		Sources: domain.MetadataSources{
+			domain.FieldReleaseDate: domain.MetadataProviderCatalog,
+			domain.FieldText:        domain.MetadataProviderCatalog,
+			domain.FieldLink:        domain.MetadataProviderCatalog,
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода CompleteJob - данные и ревизия сохраняются в одной транзакции с завершением задачи
+func TestEnrichmentRepo_CompleteJob(t *testing.T) {
+	sqlMock := newMockDB(t)
+	date := time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT release_date, text, link, metadata_sources FROM song WHERE id = $1 FOR UPDATE")).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"release_date", "text", "link", "metadata_sources"}).AddRow(nil, "", "", "{}"))
+	sqlMock.ExpectExec(regexp.QuoteMeta(
+		"UPDATE song SET enrichment_status = $1, release_date = $2, text = $3, sections = $4, link = $5, metadata_sources = metadata_sources || $6::jsonb WHERE id = $7",
+	)).
+		WithArgs(domain.EnrichmentSucceeded, date, "text", `[{"index":1,"type":"verse","lines":["text"]}]`, "link", `{"text":"catalog"}`, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO song_revision (song_id, action, snapshot, actor)")).
+		WithArgs(domain.RevisionEnrich, domain.EnrichmentActor, 10).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE enrichment_job")).
+		WithArgs(5, domain.EnrichmentSucceeded, "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	err := NewEnrichmentRepo().CompleteJob(domain.EnrichmentJob{ID: 5, SongID: 10}, domain.SongDataByApi{
+		Date: date, Text: "text", Link: "link", Sources: domain.MetadataSources{domain.FieldText: domain.MetadataProviderCatalog},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,125 @@
+package realization
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"song/internal/domain"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestEnrichmentClient создает клиент API ts без пауз между повторами
+func newTestEnrichmentClient(t *testing.T, ts *httptest.Server, retries, threshold int) *EnrichmentClient {
+	base, err := url.Parse(ts.URL + "/api")
+	assert.NoError(t, err)
+
+	client := NewEnrichmentClient(domain.EnrichmentConfig{
+		BaseURL:          base,
+		Timeout:          time.Second,
+		Retries:          retries,
+		BreakerThreshold: threshold,
+		BreakerCooldown:  time.Minute,
+	})
+	client.sleep = func(context.Context, time.Duration) error { return nil }
+
+	return client
+}
+
+// Тест для метода SongInfo: повтор после 5xx и неизменный адрес API между запросами
+func TestEnrichmentClient_SongInfo_Retry(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/info", r.URL.Path)
+		assert.Equal(t, "Muse", r.URL.Query().Get("group"))
+		if atomic.AddInt32(&calls, 1)%2 == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"releaseDate":"2006-07-16T00:00:00Z","text":"text","link":"link"}`))
+	}))
+	defer ts.Close()
+	client := newTestEnrichmentClient(t, ts, 1, 0)
+
+	for i := 0; i < 2; i++ {
+		info, err := client.SongInfo(domain.SongDataByUser{Group: "Muse", Name: "Hole"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "text", info.Text)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+	assert.Equal(t, "/api", client.config.BaseURL.Path)
+}
+
+// Тест для метода SongInfo: ответы API преобразуются в ошибки со статусами, 4xx не повторяются
+func TestEnrichmentClient_SongInfo_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected int
+		calls    int32
+	}{
+		{name: "Неверные данные", status: http.StatusBadRequest, expected: http.StatusBadRequest, calls: 1},
+		{name: "Песня не найдена", status: http.StatusNotFound, expected: http.StatusNotFound, calls: 1},
+		{name: "Ошибка API", status: http.StatusInternalServerError, expected: http.StatusBadGateway, calls: 3},
+		{name: "Неверный ответ", status: http.StatusOK, body: "invalid json", expected: http.StatusBadGateway, calls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			_, err := newTestEnrichmentClient(t, ts, 2, 0).SongInfo(domain.SongDataByUser{Group: "Muse", Name: "Hole"})
+
+			assert.Equal(t, tt.expected, err.(*domain.RequestError).Code)
+			assert.Equal(t, tt.calls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+// Тест для метода SongInfo: после серии ошибок запросы не отправляются до пробного
+func TestEnrichmentClient_SongInfo_CircuitBreaker(t *testing.T) {
+	var calls int32
+	var healthy atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"text"}`))
+	}))
+	defer ts.Close()
+	client := newTestEnrichmentClient(t, ts, 0, 2)
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+	data := domain.SongDataByUser{Group: "Muse", Name: "Hole"}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.SongInfo(data)
+		assert.Equal(t, http.StatusBadGateway, err.(*domain.RequestError).Code)
+	}
+
+	_, err := client.SongInfo(data)
+	assert.Equal(t, http.StatusServiceUnavailable, err.(*domain.RequestError).Code)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	healthy.Store(true)
+	now = now.Add(time.Minute)
+	_, err = client.SongInfo(data)
+	assert.NoError(t, err)
+	_, err = client.SongInfo(data)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,69 @@
+package realization
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ImportRepo - реализация репозитория для проверки импортируемых песен в базе данных
+type ImportRepo struct{}
+
+func NewImportRepo() *ImportRepo {
+	return &ImportRepo{}
+}
+
+// ExistingSongs находит песни библиотеки с теми же группой и названием без учета регистра,
+// песни в корзине не учитываются
+// songs - группы и названия импортируемых песен
+func (r *ImportRepo) ExistingSongs(songs []domain.SongDataByUser) ([]domain.SongDataByUser, error) {
+	if len(songs) == 0 {
+		return nil, nil
+	}
+
+	groups := make([]string, 0, len(songs))
+	names := make([]string, 0, len(songs))
+	for _, song := range songs {
+		groups = append(groups, song.Group)
+		names = append(names, song.Name)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, `SELECT DISTINCT group_name, song_name FROM song
+		WHERE deleted_at IS NULL AND (lower(group_name), lower(song_name)) IN (
+			SELECT lower(g), lower(s) FROM unnest($1::text[], $2::text[]) AS t (g, s)
+		)`, pq.Array(groups), pq.Array(names))
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	var result []domain.SongDataByUser
+	for rows.Next() {
+		var song domain.SongDataByUser
+		if err := rows.Scan(&song.Group, &song.Name); err != nil {
+			return nil, &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		result = append(result, song)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,407 @@
+package realization
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// psql - построитель запросов с плейсхолдерами PostgreSQL
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+// searchConfigs - соответствие языка поиска конфигурации и поисковому вектору
+var searchConfigs = map[string]struct {
+	config string
+	column string
+}{
+	domain.SearchLangRu: {config: "russian", column: "search_ru"},
+	domain.SearchLangEn: {config: "english", column: "search_en"},
+}
+
+// headlineOptions - параметры подсветки совпадений во фрагменте текста
+const headlineOptions = "StartSel=<b>, StopSel=</b>, MaxFragments=1, MaxWords=35, MinWords=15, FragmentDelimiter=\" ... \""
+
+// songColumn - колонка таблицы song, соответствующая полю песни
+type songColumn struct {
+	field  string                              // Имя поля песни
+	column string                              // Имя колонки
+	dest   func(song *domain.Song) interface{} // Указатель для сканирования значения
+}
+
+// songColumns - колонки таблицы song в порядке выборки
+var songColumns = []songColumn{
+	{field: domain.FieldID, column: "id", dest: func(s *domain.Song) interface{} { return &s.ID }},
+	{field: domain.FieldGroup, column: "group_name", dest: func(s *domain.Song) interface{} { return &s.Group }},
+	{field: domain.FieldArtistID, column: "artist_id", dest: func(s *domain.Song) interface{} { return &s.ArtistID }},
+	{field: domain.FieldName, column: "song_name", dest: func(s *domain.Song) interface{} { return &s.Name }},
+	{field: domain.FieldReleaseDate, column: "release_date", dest: func(s *domain.Song) interface{} { return &dateColumn{dst: &s.Date} }},
+	{field: domain.FieldText, column: "text", dest: func(s *domain.Song) interface{} { return &s.Text }},
+	{field: domain.FieldLink, column: "link", dest: func(s *domain.Song) interface{} { return &s.Link }},
+	{field: domain.FieldEnrichment, column: "enrichment_status", dest: func(s *domain.Song) interface{} { return &s.EnrichmentStatus }},
+	{field: domain.FieldSources, column: "metadata_sources", dest: func(s *domain.Song) interface{} { return &jsonColumn{dst: &s.Sources} }},
+}
+
+// jsonColumn сканирует колонку JSONB в dst
+type jsonColumn struct {
+	dst interface{}
+}
+
+func (c *jsonColumn) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(value, c.dst)
+	case string:
+		return json.Unmarshal([]byte(value), c.dst)
+	}
+
+	return fmt.Errorf("unsupported JSON column type %T", src)
+}
+
+// dateColumn сканирует колонку DATE в dst, неизвестная дата (NULL) остается нулевой
+type dateColumn struct {
+	dst *time.Time
+}
+
+func (c *dateColumn) Scan(src interface{}) error {
+	var date sql.NullTime
+	err := date.Scan(src)
+	if err != nil {
+		return err
+	}
+
+	*c.dst = date.Time
+	return nil
+}
+
+// libColumns возвращает колонки для выборки библиотеки: запрошенные поля
+// и поля ключей сортировки, без которых нельзя построить курсор
+func libColumns(filter domain.LibFilter, keys []sortKey) []songColumn {
+	fields := filter.Fields
+	if len(fields) == 0 {
+		fields = domain.DefaultLibFields
+	}
+
+	need := make(map[string]bool, len(fields)+len(keys))
+	for _, field := range fields {
+		need[field] = true
+	}
+	for _, key := range keys {
+		need[key.field] = true
+	}
+
+	var columns []songColumn
+	for _, column := range songColumns {
+		if need[column.field] {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns
+}
+
+// libSelect строит выборку библиотеки по фильтру с колонками columns. При поиске добавляется
+// релевантность, а если headline - фрагмент текста с подсветкой совпадений
+func libSelect(filter domain.LibFilter, columns []songColumn, headline bool) sq.SelectBuilder {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.column)
+	}
+	query := applyLibFilter(psql.Select(names...).From("song"), filter)
+
+	if filter.Query != "" {
+		column, config, tsQuery := searchQuery(filter)
+		query = query.Column(sq.Expr(fmt.Sprintf("ts_rank(%s, %s) AS rank", column, tsQuery), filter.Query))
+		if headline {
+			query = query.Column(sq.Expr(fmt.Sprintf("ts_headline('%s', coalesce(text, ''), %s, ?)", config, tsQuery), filter.Query, headlineOptions))
+		}
+	}
+
+	return query
+}
+
+// libDest возвращает указатели для сканирования строки выборки libSelect в song
+func libDest(song *domain.Song, filter domain.LibFilter, columns []songColumn, headline bool) []interface{} {
+	dest := make([]interface{}, 0, len(columns)+2)
+	for _, column := range columns {
+		dest = append(dest, column.dest(song))
+	}
+	if filter.Query != "" {
+		dest = append(dest, &song.Rank)
+		if headline {
+			dest = append(dest, &song.Snippet)
+		}
+	}
+
+	return dest
+}
+
+// sortKey - ключ сортировки библиотеки, по которому строится курсор
+type sortKey struct {
+	name  string                        // Имя ключа, сохраняется в курсоре
+	field string                        // Поле песни, из которого берется значение ключа
+	order string                        // Выражение для ORDER BY
+	expr  string                        // Выражение для сравнения в WHERE
+	args  []interface{}                 // Аргументы выражения
+	desc  bool                          // Сортировка по убыванию
+	value func(domain.Song) interface{} // Значение ключа у песни
+}
+
+// signature возвращает имя ключа с направлением сортировки для проверки курсора
+func (k sortKey) signature() string {
+	if k.desc {
+		return "-" + k.name
+	}
+	return k.name
+}
+
+// cursor - содержимое курсора пагинации
+type cursor struct {
+	Keys     []string      `json:"k"`           // Ключи сортировки с направлением
+	Values   []interface{} `json:"v"`           // Значения ключей сортировки
+	ID       uint64        `json:"id"`          // Идентификатор песни
+	Backward bool          `json:"b,omitempty"` // Направление - к предыдущей странице
+}
+
+// searchQuery возвращает поисковый вектор и выражение tsquery для фильтра
+func searchQuery(filter domain.LibFilter) (column, config, tsQuery string) {
+	lang := filter.SearchLang
+	if lang == "" {
+		lang = domain.SearchLangRu
+	}
+	cfg := searchConfigs[lang]
+	return cfg.column, cfg.config, fmt.Sprintf("websearch_to_tsquery('%s', ?)", cfg.config)
+}
+
+// matchCondition возвращает условие сравнения строкового поля в заданном режиме
+func matchCondition(column, value, mode string) sq.Sqlizer {
+	if mode == domain.MatchExact {
+		return sq.Eq{column: value}
+	}
+
+	return sq.Expr(column+" LIKE ?", "%"+value+"%")
+}
+
+// applyLibFilter добавляет в запрос условия фильтра библиотеки, песни из корзины исключаются
+func applyLibFilter(query sq.SelectBuilder, filter domain.LibFilter) sq.SelectBuilder {
+	query = query.Where("deleted_at IS NULL")
+
+	match := filter.Song
+	if match.ID != 0 {
+		query = query.Where("id = ?", match.ID)
+	} else {
+		if match.Name != "" {
+			query = query.Where(matchCondition("song_name", match.Name, filter.Match.Name))
+		}
+		if match.Group != "" {
+			query = query.Where(matchCondition("group_name", match.Group, filter.Match.Group))
+		}
+		if match.ArtistID != 0 {
+			query = query.Where("artist_id = ?", match.ArtistID)
+		}
+		if filter.AlbumID != 0 {
+			query = query.Where("id IN (SELECT song_id FROM album_track WHERE album_id = ?)", filter.AlbumID)
+		}
+		if !match.Date.IsZero() {
+			query = query.Where("release_date = ?", match.Date)
+		}
+		if !filter.DateFrom.IsZero() {
+			query = query.Where("release_date >= ?", filter.DateFrom)
+		}
+		if !filter.DateTo.IsZero() {
+			query = query.Where("release_date <= ?", filter.DateTo)
+		}
+		if match.Text != "" {
+			query = query.Where(matchCondition("text", match.Text, filter.Match.Text))
+		}
+		if match.Link != "" {
+			query = query.Where(matchCondition("link", match.Link, filter.Match.Link))
+		}
+	}
+
+	if len(filter.Tags.Names) > 0 {
+		query = query.Where(tagCondition(domain.TagKindTag, filter.Tags))
+	}
+	if len(filter.Genres.Names) > 0 {
+		query = query.Where(tagCondition(domain.TagKindGenre, filter.Genres))
+	}
+
+	if filter.Query != "" {
+		column, _, tsQuery := searchQuery(filter)
+		query = query.Where(fmt.Sprintf("%s @@ %s", column, tsQuery), filter.Query)
+	}
+
+	return query
+}
+
+// releaseDateExpr - выражение сортировки по дате выпуска. Песни с неизвестной датой (NULL)
+// сравниваются как песни с датой позже любой известной, как NULL в сортировке PostgreSQL,
+// иначе условие курсора с NULL пропускало бы их
+const releaseDateExpr = "COALESCE(release_date, '" + unknownReleaseDate + "')"
+
+// unknownReleaseDate - значение ключа сортировки для песни с неизвестной датой выпуска
+const unknownReleaseDate = "infinity"
+
+// sortColumns - ключи сортировки по полям песни
+var sortColumns = map[string]sortKey{
+	domain.SortName: {
+		name:  domain.SortName,
+		field: domain.FieldName,
+		order: "song_name",
+		expr:  "song_name",
+		value: func(s domain.Song) interface{} { return s.Name },
+	},
+	domain.SortGroup: {
+		name:  domain.SortGroup,
+		field: domain.FieldGroup,
+		order: "group_name",
+		expr:  "group_name",
+		value: func(s domain.Song) interface{} { return s.Group },
+	},
+	domain.SortReleaseDate: {
+		name:  domain.SortReleaseDate,
+		field: domain.FieldReleaseDate,
+		order: releaseDateExpr,
+		expr:  releaseDateExpr,
+		value: func(s domain.Song) interface{} {
+			if s.Date.IsZero() {
+				return unknownReleaseDate
+			}
+			return s.Date.Format("2006-01-02")
+		},
+	},
+}
+
+// idKey - ключ сортировки по идентификатору, завершает любой набор ключей
+var idKey = sortKey{
+	name:  domain.SortID,
+	field: domain.FieldID,
+	order: "id",
+	expr:  "id",
+	value: func(s domain.Song) interface{} { return s.ID },
+}
+
+// libSortKeys возвращает ключи сортировки библиотеки, последним всегда идет идентификатор
+func libSortKeys(filter domain.LibFilter) []sortKey {
+	var keys []sortKey
+	if len(filter.Sort) == 0 && filter.Query != "" {
+		column, _, tsQuery := searchQuery(filter)
+		keys = append(keys, sortKey{
+			name:  "rank",
+			order: "rank",
+			expr:  fmt.Sprintf("ts_rank(%s, %s)", column, tsQuery),
+			args:  []interface{}{filter.Query},
+			desc:  true,
+			value: func(s domain.Song) interface{} { return s.Rank },
+		})
+	}
+
+	for _, field := range filter.Sort {
+		if field.Field == domain.SortID {
+			// Идентификатор уникален, следующие за ним ключи не влияют на порядок
+			key := idKey
+			key.desc = field.Desc
+			return append(keys, key)
+		}
+
+		key := sortColumns[field.Field]
+		key.desc = field.Desc
+		keys = append(keys, key)
+	}
+
+	return append(keys, idKey)
+}
+
+// keysetCondition строит условие выборки строк, следующих за курсором в порядке ключей
+func keysetCondition(keys []sortKey, c *cursor) sq.Or {
+	values := append(append([]interface{}{}, c.Values...), c.ID)
+	var or sq.Or
+	for i, key := range keys {
+		var and sq.And
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Expr(fmt.Sprintf("%s = ?", keys[j].expr), withArg(keys[j].args, values[j])...))
+		}
+
+		op := ">"
+		if key.desc != c.Backward {
+			op = "<"
+		}
+		and = append(and, sq.Expr(fmt.Sprintf("%s %s ?", key.expr, op), withArg(key.args, values[i])...))
+		or = append(or, and)
+	}
+
+	return or
+}
+
+// withArg возвращает копию аргументов выражения с добавленным значением
+func withArg(args []interface{}, value interface{}) []interface{} {
+	return append(append([]interface{}{}, args...), value)
+}
+
+// orderBy возвращает выражения сортировки по ключам, backward - в обратном порядке
+func orderBy(keys []sortKey, backward bool) []string {
+	order := make([]string, 0, len(keys))
+	for _, key := range keys {
+		dir := "ASC"
+		if key.desc != backward {
+			dir = "DESC"
+		}
+		order = append(order, fmt.Sprintf("%s %s", key.order, dir))
+	}
+
+	return order
+}
+
+// encodeCursor кодирует курсор, указывающий на песню song
+func encodeCursor(keys []sortKey, song domain.Song, backward bool) string {
+	c := cursor{
+		ID:       song.ID,
+		Backward: backward,
+	}
+	for i, key := range keys {
+		c.Keys = append(c.Keys, key.signature())
+		if i < len(keys)-1 {
+			c.Values = append(c.Values, key.value(song))
+		}
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor разбирает курсор и проверяет, что он построен для тех же ключей сортировки
+func decodeCursor(keys []sortKey, raw string) (*cursor, error) {
+	invalid := &e.InvalidInputData{
+		Err:  "Invalid cursor",
+		Code: http.StatusBadRequest,
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, invalid
+	}
+
+	if len(c.Keys) != len(keys) || len(c.Values) != len(keys)-1 {
+		return nil, invalid
+	}
+	for i, name := range c.Keys {
+		if keys[i].signature() != name {
+			return nil, invalid
+		}
+	}
+
+	return &c, nil
+}
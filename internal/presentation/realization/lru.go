@@ -0,0 +1,108 @@
+package realization
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruCache - ограниченный по числу значений кэш в памяти с вытеснением давно не читавшихся значений
+type lruCache struct {
+	mu        sync.Mutex
+	capacity  int
+	items     map[string]*list.Element
+	order     *list.List // Значения от недавно прочитанных к давно не читавшимся
+	evictions uint64
+}
+
+// lruEntry - значение кэша в памяти
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // Нулевое время - без срока хранения
+}
+
+// newLRUCache создает кэш в памяти
+// capacity - наибольшее число значений
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// get возвращает значение ключа, если оно есть и не устарело к моменту now
+func (c *lruCache) get(key string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := item.Value.(*lruEntry)
+	if !entry.expires.IsZero() && !now.Before(entry.expires) {
+		c.remove(item)
+		return nil, false
+	}
+
+	c.order.MoveToFront(item)
+	return entry.value, true
+}
+
+// set записывает значение ключа до момента expires, нулевое время - без срока хранения.
+// При переполнении вытесняется давно не читавшееся значение
+func (c *lruCache) set(key string, value []byte, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if item, ok := c.items[key]; ok {
+		entry := item.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(item)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	if c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+// del удаляет ключи
+func (c *lruCache) del(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if item, ok := c.items[key]; ok {
+			c.remove(item)
+		}
+	}
+}
+
+// clear удаляет все значения
+func (c *lruCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*list.Element, c.capacity)
+	c.order.Init()
+}
+
+// stats возвращает текущее число значений и количество вытесненных значений
+func (c *lruCache) stats() (int, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len(), c.evictions
+}
+
+// remove удаляет значение, вызывается под блокировкой
+func (c *lruCache) remove(item *list.Element) {
+	c.order.Remove(item)
+	delete(c.items, item.Value.(*lruEntry).key)
+}
@@ -0,0 +1,59 @@
+package realization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для кэша в памяти - при переполнении вытесняется давно не читавшееся значение
+func TestLRUCache_Evict(t *testing.T) {
+	cache := newLRUCache(2)
+	now := time.Unix(0, 0)
+	expires := now.Add(time.Minute)
+
+	cache.set("a", []byte("1"), expires)
+	cache.set("b", []byte("2"), expires)
+	_, _ = cache.get("a", now)
+	cache.set("c", []byte("3"), expires)
+
+	_, ok := cache.get("b", now)
+	assert.False(t, ok)
+	value, ok := cache.get("a", now)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	entries, evictions := cache.stats()
+	assert.Equal(t, 2, entries)
+	assert.Equal(t, uint64(1), evictions)
+}
+
+// Тест для кэша в памяти - устаревшее значение не возвращается и удаляется
+func TestLRUCache_Expire(t *testing.T) {
+	cache := newLRUCache(2)
+	now := time.Unix(0, 0)
+
+	cache.set("a", []byte("1"), now.Add(time.Second))
+
+	_, ok := cache.get("a", now.Add(time.Second))
+	assert.False(t, ok)
+	entries, _ := cache.stats()
+	assert.Equal(t, 0, entries)
+}
+
+// Тест для сообщений об измененных ключах - свои сообщения пропускаются
+func TestTieredCache_Invalidate(t *testing.T) {
+	cache := &TieredCache{local: newLRUCache(4), origin: "self"}
+	expires := time.Now().Add(time.Minute)
+	cache.local.set("a", []byte("1"), expires)
+	cache.local.set("b", []byte("2"), expires)
+
+	cache.invalidate(`{"origin":"self","keys":["a"]}`)
+	cache.invalidate(`{"origin":"other","keys":["b"]}`)
+
+	_, ok := cache.local.get("a", time.Now())
+	assert.True(t, ok)
+	_, ok = cache.local.get("b", time.Now())
+	assert.False(t, ok)
+}
@@ -0,0 +1,143 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"time"
+)
+
+// overrideColumns - колонки таблицы metadata_override в порядке сканирования
+const overrideColumns = "id, group_name, song_name, release_date, COALESCE(text, ''), COALESCE(link, '')"
+
+// OverrideRepo - реализация репозитория ручных исправлений данных песен в базе данных,
+// исправления служат источником данных для цепочки источников
+type OverrideRepo struct{}
+
+func NewOverrideRepo() *OverrideRepo {
+	return &OverrideRepo{}
+}
+
+// Name возвращает имя источника данных
+func (r *OverrideRepo) Name() string {
+	return domain.MetadataProviderOverride
+}
+
+// SongInfo получает исправление песни по группе и названию без учета регистра
+// data - группа и название песни
+func (r *OverrideRepo) SongInfo(data domain.SongDataByUser) (*domain.SongDataByApi, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	override, err := scanOverride(postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT `+overrideColumns+`
+		FROM metadata_override WHERE lower(group_name) = lower($1) AND lower(song_name) = lower($2)`, data.Group, data.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	info := override.SongData()
+	return &info, nil
+}
+
+// GetOverrides получает исправления по номеру страницы
+// page - номер страницы для пагинации
+func (r *OverrideRepo) GetOverrides(page domain.Page) (*[]domain.MetadataOverride, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, `SELECT `+overrideColumns+`
+		FROM metadata_override ORDER BY group_name, song_name, id LIMIT 20 OFFSET $1`, 20*(page-1))
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	result := []domain.MetadataOverride{}
+	for rows.Next() {
+		override, err := scanOverride(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *override)
+	}
+
+	return &result, nil
+}
+
+// SetOverride создает исправление песни или заменяет существующее
+// override - исправление, пустые поля не переопределяются
+func (r *OverrideRepo) SetOverride(override domain.MetadataOverride) (*domain.MetadataOverride, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanOverride(postgres.DbService.Db.QueryRowContext(timeoutCtx, `INSERT INTO metadata_override (group_name, song_name, release_date, text, link)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''))
+		ON CONFLICT (lower(group_name), lower(song_name)) DO UPDATE
+		SET release_date = EXCLUDED.release_date, text = EXCLUDED.text, link = EXCLUDED.link
+		RETURNING `+overrideColumns, override.Group, override.Name, override.Date, override.Text, override.Link))
+}
+
+// DelOverride удаляет исправление по идентификатору
+// id - идентификатор исправления
+func (r *OverrideRepo) DelOverride(id uint64) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := postgres.DbService.Db.ExecContext(timeoutCtx, "DELETE FROM metadata_override WHERE id = $1", id)
+	if err != nil {
+		return &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return &e.RowsNotFoundError{
+			Err:  "Исправление с таким идентификатором не существует",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return nil
+}
+
+// rowScanner - строка результата запроса, *sql.Row или *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOverride сканирует исправление данных песни
+func scanOverride(row rowScanner) (*domain.MetadataOverride, error) {
+	var override domain.MetadataOverride
+	var date sql.NullTime
+	err := row.Scan(&override.ID, &override.Group, &override.Name, &date, &override.Text, &override.Link)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &e.RowsNotFoundError{
+				Err:  "Исправление для этой песни не существует",
+				Code: http.StatusNotFound,
+			}
+		}
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	if date.Valid {
+		override.Date = &date.Time
+	}
+
+	return &override, nil
+}
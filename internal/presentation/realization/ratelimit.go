@@ -0,0 +1,139 @@
+package realization
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// rateLimitScript атомарно проверяет скользящее окно и учитывает запрос.
+// KEYS[1], KEYS[2] - счетчики текущего и предыдущего окон,
+// ARGV[1] - ограничение, ARGV[2] - доля предыдущего окна, ARGV[3] - время жизни счетчика в мс
+var rateLimitScript = redis.NewScript(`
+local cur = tonumber(redis.call('GET', KEYS[1]) or '0')
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+if prev * tonumber(ARGV[2]) + cur + 1 > tonumber(ARGV[1]) then
+	return {0, prev, cur}
+end
+cur = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {1, prev, cur}
+`)
+
+// RedisRateLimiter - счетчики запросов в Redis, общие для всех экземпляров сервиса
+type RedisRateLimiter struct {
+	db *redis.Client
+}
+
+// NewRedisRateLimiter создает счетчики запросов в подключении к Redis
+func NewRedisRateLimiter(r *RedisRepo) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		db: r.db,
+	}
+}
+
+// Allow учитывает запрос клиента key, если он укладывается в ограничение limit
+// key - клиент и правило
+// limit - ограничение
+// now - время запроса
+func (r *RedisRateLimiter) Allow(key string, limit domain.RateLimit, now time.Time) (*domain.RateLimitResult, error) {
+	window, elapsed := windowAt(limit, now)
+	weight := 1 - float64(elapsed)/float64(limit.Window)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	res, err := rateLimitScript.Run(ctx, r.db,
+		[]string{rateLimitKey(key, window), rateLimitKey(key, window-1)},
+		limit.Limit, weight, (2 * limit.Window).Milliseconds()).Int64Slice()
+	if err != nil || len(res) != 3 {
+		return nil, &e.RedisQueryError{
+			Err:  fmt.Sprintf("Ошибка проверки ограничения запросов в Redis: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	result := limit.SlidingWindow(res[0] == 1, res[1], res[2], elapsed)
+	return &result, nil
+}
+
+// MemoryRateLimiter - счетчики запросов в памяти процесса, используются, когда Redis недоступен
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	counters  map[string]*memoryCounter
+	lastSweep time.Time
+}
+
+// memoryCounter - счетчик запросов фиксированного окна
+type memoryCounter struct {
+	count   int64
+	expires time.Time // Время, после которого окно больше не учитывается
+}
+
+// NewMemoryRateLimiter создает счетчики запросов в памяти
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		counters: make(map[string]*memoryCounter),
+	}
+}
+
+// Allow учитывает запрос клиента key, если он укладывается в ограничение limit
+// key - клиент и правило
+// limit - ограничение
+// now - время запроса
+func (m *MemoryRateLimiter) Allow(key string, limit domain.RateLimit, now time.Time) (*domain.RateLimitResult, error) {
+	window, elapsed := windowAt(limit, now)
+	weight := 1 - float64(elapsed)/float64(limit.Window)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	var prev int64
+	if counter, ok := m.counters[rateLimitKey(key, window-1)]; ok {
+		prev = counter.count
+	}
+	cur, ok := m.counters[rateLimitKey(key, window)]
+	if !ok {
+		cur = &memoryCounter{expires: now.Add(2*limit.Window - elapsed)}
+		m.counters[rateLimitKey(key, window)] = cur
+	}
+
+	allowed := float64(prev)*weight+float64(cur.count)+1 <= float64(limit.Limit)
+	if allowed {
+		cur.count++
+	}
+
+	result := limit.SlidingWindow(allowed, prev, cur.count, elapsed)
+	return &result, nil
+}
+
+// sweep раз в минуту удаляет счетчики окон, которые больше не учитываются
+func (m *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+
+	for key, counter := range m.counters {
+		if now.After(counter.expires) {
+			delete(m.counters, key)
+		}
+	}
+}
+
+// windowAt возвращает номер фиксированного окна и время от его начала
+func windowAt(limit domain.RateLimit, now time.Time) (int64, time.Duration) {
+	window := now.UnixNano() / int64(limit.Window)
+	return window, time.Duration(now.UnixNano() - window*int64(limit.Window))
+}
+
+// rateLimitKey возвращает ключ счетчика окна
+func rateLimitKey(key string, window int64) string {
+	return fmt.Sprintf("ratelimit:%s:%d", key, window)
+}
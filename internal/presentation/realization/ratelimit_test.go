@@ -0,0 +1,35 @@
+package realization
+
+import (
+	"song/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для метода Allow счетчиков в памяти
+func TestMemoryRateLimiter_Allow(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	limit := domain.RateLimit{Limit: 3, Window: time.Minute}
+	start := time.Unix(0, 0).Add(time.Hour)
+
+	for i := int64(0); i < limit.Limit; i++ {
+		result, err := limiter.Allow("client", limit, start)
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, limit.Limit-i-1, result.Remaining)
+	}
+
+	result, _ := limiter.Allow("client", limit, start)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Minute, result.RetryAfter)
+
+	other, _ := limiter.Allow("other", limit, start)
+	assert.True(t, other.Allowed)
+
+	// В середине следующего окна учитывается половина запросов предыдущего
+	result, _ = limiter.Allow("client", limit, start.Add(90*time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, int64(0), result.Remaining)
+}
@@ -2,19 +2,23 @@ package realization
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"song/internal/domain"
 	e "song/internal/presentation/customError"
 	"song/internal/presentation/logger"
-	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
 // RedisRepo представляет репозиторий для работы с Redis
 type RedisRepo struct {
-	db *redis.Client
+	db     *redis.Client
+	hits   atomic.Uint64
+	misses atomic.Uint64
 }
 
 // NewConnectRedis создает новое подключение к Redis
@@ -33,14 +37,34 @@ func NewConnectRedis(addr, port, pass string) *RedisRepo {
 	}
 }
 
-// CreateKey создает новый ключ в Redis
-// id - идентификатор ключа
-// text - текст песни
-func (r *RedisRepo) CreateKey(id domain.Id, text domain.SongText) error {
+// Get получает значение ключа из Redis, если ключа нет - nil
+// key - ключ
+func (r *RedisRepo) Get(key string) ([]byte, error) {
 	ctx := context.Background()
-	res := r.db.Set(ctx, strconv.FormatUint(id, 10), text, 0)
-	_, err := res.Result()
+	value, err := r.db.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			r.misses.Add(1)
+			return nil, nil
+		}
+		return nil, &e.RedisQueryError{
+			Err:  fmt.Sprintf("Ошибка получения ключа в Redis: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	r.hits.Add(1)
+	logger.Logger.Debug(fmt.Sprintf("key: %s was got", key))
+	return value, nil
+}
 
+// Set записывает значение ключа в Redis
+// key - ключ
+// value - значение
+// ttl - время хранения, 0 - без срока хранения
+func (r *RedisRepo) Set(key string, value []byte, ttl time.Duration) error {
+	ctx := context.Background()
+	err := r.db.Set(ctx, key, value, ttl).Err()
 	if err != nil {
 		return &e.RedisQueryError{
 			Err:  fmt.Sprintf("Ошибка создания ключа в Redis: %v", err),
@@ -48,47 +72,52 @@ func (r *RedisRepo) CreateKey(id domain.Id, text domain.SongText) error {
 		}
 	}
 
-	logger.Logger.Debug(fmt.Sprintf("key: %d was created", id))
+	logger.Logger.Debug(fmt.Sprintf("key: %s was created", key))
 	return nil
 }
 
-// GetText получает значение ключа из Redis по идентификатору
-// id - идентификатор ключа
-func (r *RedisRepo) GetText(id domain.Id) (*domain.SongText, error) {
-	ctx := context.Background()
-	res := r.db.Get(ctx, strconv.FormatUint(id, 10))
+// Del удаляет ключи из Redis
+// keys - ключи
+func (r *RedisRepo) Del(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
 
-	err := res.Err()
+	ctx := context.Background()
+	err := r.db.Del(ctx, keys...).Err()
 	if err != nil {
-		if err == redis.Nil {
-			return nil, nil
-		}
-		return nil, &e.RedisQueryError{
-			Err:  fmt.Sprintf("Ошибка получения ключа в Redis: %v", err),
+		return &e.RedisQueryError{
+			Err:  fmt.Sprintf("Ошибка удаления ключа в Redis: %v", err),
 			Code: http.StatusInternalServerError,
 		}
 	}
 
-	logger.Logger.Debug(fmt.Sprintf("key: %d was got", id))
-	text := domain.SongText(res.Val())
-	return &text, nil
+	logger.Logger.Debug(fmt.Sprintf("keys: %v were deleted", keys))
+	return nil
 }
 
-// DelKey удаляет ключ из Redis
-// id - идентификатор ключа
-func (r *RedisRepo) DelKey(id domain.Id) error {
+// Incr увеличивает число в ключе Redis на 1
+// key - ключ
+func (r *RedisRepo) Incr(key string) (int64, error) {
 	ctx := context.Background()
-	err := r.db.Del(ctx, strconv.FormatUint(id, 10)).Err()
-
+	value, err := r.db.Incr(ctx, key).Result()
 	if err != nil {
-		return &e.RedisQueryError{
-			Err:  fmt.Sprintf("Ошибка удаления ключа в Redis: %v", err),
+		return 0, &e.RedisQueryError{
+			Err:  fmt.Sprintf("Ошибка увеличения ключа в Redis: %v", err),
 			Code: http.StatusInternalServerError,
 		}
 	}
 
-	logger.Logger.Debug(fmt.Sprintf("key: %d was deleted", id))
-	return nil
+	return value, nil
+}
+
+// Stats возвращает статистику попаданий в Redis
+func (r *RedisRepo) Stats() []domain.CacheTierStats {
+	return []domain.CacheTierStats{{
+		Tier:   domain.CacheTierRedis,
+		Hits:   r.hits.Load(),
+		Misses: r.misses.Load(),
+	}}
 }
 
 func (r *RedisRepo) Close() error {
@@ -0,0 +1,184 @@
+package realization
+
+import (
+	"fmt"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"song/internal/presentation/logger"
+	"sync"
+	"time"
+)
+
+// ResilientCache - хранилище кэша, ошибки которого считаются промахами: чтение возвращает
+// отсутствие значения, запись пропускается, поэтому недоступный Redis не приводит к ошибкам запросов.
+// После threshold ошибок подряд хранилище не вызывается на время cooldown, чтобы запросы не ждали
+// ответа Redis. Ключи, которые не удалось удалить или увеличить, запоминаются и изменяются
+// перед первым успешным обращением, чтобы после восстановления не читать устаревшие значения
+type ResilientCache struct {
+	cache   interfaces.CacheRepo
+	breaker *circuitBreaker
+
+	mu          sync.Mutex
+	staleKeys   map[string]struct{} // Ключи, которые нужно удалить
+	staleCounts map[string]struct{} // Счетчики, которые нужно увеличить
+}
+
+// NewResilientCache создает хранилище, ошибки которого считаются промахами
+// cache - хранилище кэша
+// threshold - число ошибок подряд, после которого хранилище перестает вызываться, 0 - вызывается всегда
+// cooldown - время, на которое хранилище перестает вызываться
+func NewResilientCache(cache interfaces.CacheRepo, threshold int, cooldown time.Duration) *ResilientCache {
+	return &ResilientCache{
+		cache:       cache,
+		breaker:     newCircuitBreaker(threshold, cooldown),
+		staleKeys:   make(map[string]struct{}),
+		staleCounts: make(map[string]struct{}),
+	}
+}
+
+// Get получает значение ключа, при ошибке хранилища - nil
+// key - ключ
+func (c *ResilientCache) Get(key string) ([]byte, error) {
+	var value []byte
+	c.call("get", func() (err error) {
+		value, err = c.cache.Get(key)
+		return err
+	})
+
+	return value, nil
+}
+
+// Set записывает значение ключа, ошибка хранилища пропускается
+// key - ключ
+// value - значение
+// ttl - время хранения, 0 - без срока хранения
+func (c *ResilientCache) Set(key string, value []byte, ttl time.Duration) error {
+	c.call("set", func() error {
+		return c.cache.Set(key, value, ttl)
+	})
+
+	return nil
+}
+
+// Del удаляет ключи, при ошибке хранилища ключи удаляются после его восстановления
+// keys - ключи
+func (c *ResilientCache) Del(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ok := c.call("del", func() error {
+		return c.cache.Del(keys...)
+	})
+	if !ok {
+		c.mu.Lock()
+		for _, key := range keys {
+			c.staleKeys[key] = struct{}{}
+		}
+		c.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Incr увеличивает число в ключе на 1, при ошибке хранилища возвращает 0,
+// а ключ увеличивается после восстановления хранилища
+// key - ключ
+func (c *ResilientCache) Incr(key string) (int64, error) {
+	var value int64
+	ok := c.call("incr", func() (err error) {
+		value, err = c.cache.Incr(key)
+		return err
+	})
+	if !ok {
+		c.mu.Lock()
+		c.staleCounts[key] = struct{}{}
+		c.mu.Unlock()
+	}
+
+	return value, nil
+}
+
+// Stats возвращает статистику попаданий хранилища
+func (c *ResilientCache) Stats() []domain.CacheTierStats {
+	return c.cache.Stats()
+}
+
+func (c *ResilientCache) Close() error {
+	return c.cache.Close()
+}
+
+// call выполняет обращение к хранилищу, если автомат его пропускает, и возвращает его успешность.
+// Перед обращением изменяются ключи, которые не удалось изменить ранее
+// op - название обращения для журнала
+func (c *ResilientCache) call(op string, fn func() error) bool {
+	if !c.breaker.allow() {
+		return false
+	}
+
+	err := c.replay()
+	if err == nil {
+		err = fn()
+	}
+	if err != nil {
+		c.breaker.failure()
+		logger.Logger.Warn(fmt.Sprintf("Cache %s error, treated as a miss - %v", op, err))
+		return false
+	}
+
+	c.breaker.success()
+	return true
+}
+
+// replay удаляет и увеличивает ключи, которые не удалось изменить ранее.
+// При ошибке ключи остаются для следующей попытки
+func (c *ResilientCache) replay() error {
+	c.mu.Lock()
+	if len(c.staleKeys) == 0 && len(c.staleCounts) == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	staleKeys, staleCounts := c.staleKeys, c.staleCounts
+	c.staleKeys, c.staleCounts = make(map[string]struct{}), make(map[string]struct{})
+	c.mu.Unlock()
+
+	err := c.applyStale(staleKeys, staleCounts)
+	if err != nil {
+		c.mu.Lock()
+		for key := range staleKeys {
+			c.staleKeys[key] = struct{}{}
+		}
+		for key := range staleCounts {
+			c.staleCounts[key] = struct{}{}
+		}
+		c.mu.Unlock()
+		return err
+	}
+
+	logger.Logger.Info(fmt.Sprintf("Cache has recovered, %d stale keys were invalidated", len(staleKeys)+len(staleCounts)))
+	return nil
+}
+
+// applyStale удаляет ключи staleKeys и увеличивает счетчики staleCounts
+func (c *ResilientCache) applyStale(staleKeys, staleCounts map[string]struct{}) error {
+	if len(staleKeys) > 0 {
+		keys := make([]string, 0, len(staleKeys))
+		for key := range staleKeys {
+			keys = append(keys, key)
+		}
+		err := c.cache.Del(keys...)
+		if err != nil {
+			return err
+		}
+	}
+
+	for key := range staleCounts {
+		_, err := c.cache.Incr(key)
+		if err != nil {
+			return err
+		}
+		delete(staleCounts, key)
+	}
+
+	return nil
+}
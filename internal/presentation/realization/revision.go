@@ -0,0 +1,190 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// songSnapshot - выражение полного снимка строки song в формате domain.Song
+const songSnapshot = `jsonb_build_object(
+	'id', id,
+	'name', song_name,
+	'group', group_name,
+	'artistId', artist_id,
+	'releaseDate', to_char(release_date, 'YYYY-MM-DD') || 'T00:00:00Z',
+	'text', text,
+	'link', link
+)`
+
+// RevisionRepo - реализация репозитория для работы с историей изменений песен в базе данных
+type RevisionRepo struct{}
+
+func NewRevisionRepo() *RevisionRepo {
+	return &RevisionRepo{}
+}
+
+// GetRevisions получает ревизии песни без снимков, от старых к новым
+// songID - идентификатор песни
+func (r *RevisionRepo) GetRevisions(songID domain.Id) (*[]domain.Revision, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, `SELECT id, song_id, action, actor, created_at
+		FROM song_revision WHERE song_id = $1 ORDER BY id`, songID)
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	result := []domain.Revision{}
+	for rows.Next() {
+		var revision domain.Revision
+		if err := rows.Scan(&revision.ID, &revision.SongID, &revision.Action, &revision.Actor, &revision.CreatedAt); err != nil {
+			return nil, &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		result = append(result, revision)
+	}
+
+	if len(result) == 0 {
+		return nil, &e.RowsNotFoundError{
+			Err:  "Песня с таким идентификатором не существует",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return &result, nil
+}
+
+// GetRevision получает ревизию песни со снимком
+// songID - идентификатор песни
+// id - идентификатор ревизии
+func (r *RevisionRepo) GetRevision(songID, id domain.Id) (*domain.Revision, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanRevision(postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT id, song_id, action, actor, created_at, snapshot
+		FROM song_revision WHERE song_id = $1 AND id = $2`, songID, id))
+}
+
+// RevertSong возвращает песню к состоянию ревизии, возврат записывается новой ревизией
+// songID - идентификатор песни
+// id - идентификатор ревизии
+// actor - автор изменения
+func (r *RevisionRepo) RevertSong(songID, id domain.Id, actor string) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		var lockedID uint64
+		err := tx.QueryRowContext(timeoutCtx, `SELECT id FROM song WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, songID).Scan(&lockedID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &e.RowsNotFoundError{
+					Err:  "Песня с таким идентификатором не существует",
+					Code: http.StatusNotFound,
+				}
+			}
+			return revisionQueryError(err)
+		}
+
+		revision, err := scanRevision(tx.QueryRowContext(timeoutCtx, `SELECT id, song_id, action, actor, created_at, snapshot
+			FROM song_revision WHERE song_id = $1 AND id = $2`, songID, id))
+		if err != nil {
+			return err
+		}
+
+		snapshot := revision.Snapshot
+		text, sections := lyricsValues(snapshot.Text)
+		_, err = tx.ExecContext(timeoutCtx, `UPDATE song SET song_name = $2, artist_id = $3,
+			group_name = (SELECT name FROM artist WHERE id = $3), release_date = $4, text = $5, sections = $6, link = $7
+			WHERE id = $1`, songID, snapshot.Name, snapshot.ArtistID, nullDate(snapshot.Date), text, sections, snapshot.Link)
+		if err != nil {
+			return revisionQueryError(err)
+		}
+
+		return recordRevision(timeoutCtx, tx, songID, domain.RevisionRevert, actor)
+	})
+}
+
+// recordRevision записывает ревизию с текущим состоянием песни в транзакции изменения.
+// Если песни нет, ревизия не записывается
+func recordRevision(ctx context.Context, tx *sql.Tx, songID domain.Id, action, actor string) error {
+	return recordRevisions(ctx, tx, action, actor, "id = $3", songID)
+}
+
+// recordRevisions записывает ревизии с текущим состоянием песен, подходящих под условие where.
+// Аргументы условия нумеруются с $3
+func recordRevisions(ctx context.Context, tx *sql.Tx, action, actor, where string, args ...interface{}) error {
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO song_revision (song_id, action, snapshot, actor)
+		SELECT id, $1, %s, $2 FROM song WHERE %s`, songSnapshot, where), append([]interface{}{action, actor}, args...)...)
+	if err != nil {
+		return &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка записи ревизии песни: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
+
+// scanRevision сканирует ревизию со снимком песни
+func scanRevision(row *sql.Row) (*domain.Revision, error) {
+	var revision domain.Revision
+	var snapshot []byte
+	err := row.Scan(&revision.ID, &revision.SongID, &revision.Action, &revision.Actor, &revision.CreatedAt, &snapshot)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &e.RowsNotFoundError{
+				Err:  "Ревизия с таким идентификатором не существует",
+				Code: http.StatusNotFound,
+			}
+		}
+		return nil, revisionQueryError(err)
+	}
+
+	revision.Snapshot = &domain.Song{}
+	if err := json.Unmarshal(snapshot, revision.Snapshot); err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка разбора снимка песни: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return &revision, nil
+}
+
+// revisionQueryError преобразует ошибку базы данных при работе с ревизиями
+func revisionQueryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && (pqErr.Code == pqForeignKeyViolation || pqErr.Code == pqNotNullViolation) {
+		return &e.ConflictError{
+			Err:  "Artist of the revision no longer exists",
+			Code: http.StatusConflict,
+		}
+	}
+
+	return &e.DbQueryError{
+		Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
@@ -3,6 +3,7 @@ package realization
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -10,11 +11,10 @@ import (
 	e "song/internal/presentation/customError"
 	"song/internal/presentation/logger"
 	"song/internal/presentation/postgres"
-	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 // SongRepo - реализация репозитория для работы с песнями в базе данных
@@ -24,38 +24,34 @@ func NewSongRepo() *SongRepo {
 	return &SongRepo{}
 }
 
-// GetLib получает библиотеку песен по фильтру и номеру страницы
-// filter - фильтр для песен
-// page - номер страницы для пагинации
-func (r *SongRepo) GetLib(filter domain.Song, page domain.Page) (*[]domain.Song, error) {
-	query := sq.Select("id", "group_name", "song_name", "release_date", "text", "link").From("song")
-	count := 1
+// GetLib получает страницу библиотеки песен по фильтру
+// filter - фильтр для песен и поисковый запрос
+// pagination - параметры пагинации
+func (r *SongRepo) GetLib(filter domain.LibFilter, pagination domain.Pagination) (*domain.LibPage, error) {
+	keys := libSortKeys(filter)
+	columns := libColumns(filter, keys)
+	query := libSelect(filter, columns, true)
 
-	if filter.ID != 0 {
-		query = query.Where(fmt.Sprintf("id = $%d", count), filter.ID)
+	limit := pagination.Limit
+	if limit <= 0 {
+		limit = domain.DefaultLibLimit
+	}
+
+	var after *cursor
+	if pagination.Page > 0 {
+		query = query.OrderBy(orderBy(keys, false)...).Offset(uint64(limit * (pagination.Page - 1))).Limit(uint64(limit))
 	} else {
-		if filter.Name != "" {
-			query = query.Where(fmt.Sprintf("song_name LIKE $%d", count), "%"+filter.Name+"%")
-			count++
-		}
-		if filter.Group != "" {
-			query = query.Where(fmt.Sprintf("group_name LIKE $%d", count), "%"+filter.Group+"%")
-			count++
-		}
-		if !filter.Date.IsZero() {
-			query = query.Where(fmt.Sprintf("release_date = $%d", count), filter.Date)
-			count++
-		}
-		if filter.Text != "" {
-			query = query.Where(fmt.Sprintf("text LIKE $%d", count), "%"+filter.Text+"%")
-			count++
-		}
-		if filter.Link != "" {
-			query = query.Where(fmt.Sprintf("link LIKE $%d", count), "%"+filter.Link+"%")
+		if pagination.Cursor != "" {
+			var err error
+			after, err = decodeCursor(keys, pagination.Cursor)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where(keysetCondition(keys, after))
 		}
+		query = query.OrderBy(orderBy(keys, after != nil && after.Backward)...).Limit(uint64(limit + 1))
 	}
 
-	query = query.Offset(uint64(20 * (page - 1))).Limit(20)
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
 		return nil, &e.DbQueryError{
@@ -85,10 +81,10 @@ func (r *SongRepo) GetLib(filter domain.Song, page domain.Page) (*[]domain.Song,
 		}
 	}()
 
-	var result []domain.Song
+	result := []domain.Song{}
 	for rows.Next() {
 		var song domain.Song
-		if err := rows.Scan(&song.ID, &song.Group, &song.Name, &song.Date, &song.Text, &song.Link); err != nil {
+		if err := rows.Scan(libDest(&song, filter, columns, true)...); err != nil {
 			return nil, &e.DbQueryError{
 				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
 				Code: http.StatusInternalServerError,
@@ -96,23 +92,161 @@ func (r *SongRepo) GetLib(filter domain.Song, page domain.Page) (*[]domain.Song,
 		}
 		result = append(result, song)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка чтения строк: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
 
-	return &result, nil
+	page := &domain.LibPage{Items: result}
+	if pagination.Page == 0 {
+		page = keysetPage(keys, result, limit, after)
+	}
+
+	if pagination.Total {
+		total, err := r.countLib(filter)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = total
+	}
+
+	return page, nil
 }
 
-// GetText получает текст песни по идентификатору
-// id - идентификатор песни
-func (r *SongRepo) GetText(id domain.Id) (*domain.SongText, error) {
-	var text string
+// ExportLib передает fn все песни библиотеки по фильтру в порядке сортировки.
+// Строки читаются из базы данных по мере записи, запрос прерывается отменой ctx
+// filter - фильтр для песен и поисковый запрос
+// fn - вызывается для каждой песни, ошибка прерывает выгрузку
+func (r *SongRepo) ExportLib(ctx context.Context, filter domain.LibFilter, fn func(domain.Song) error) error {
+	keys := libSortKeys(filter)
+	columns := libColumns(filter, keys)
+	sqlQuery, args, err := libSelect(filter, columns, false).OrderBy(orderBy(keys, false)...).ToSql()
+	if err != nil {
+		return &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка генерации SQL-запроса: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	rows, err := postgres.DbService.Db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	for rows.Next() {
+		var song domain.Song
+		if err := rows.Scan(libDest(&song, filter, columns, false)...); err != nil {
+			return &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		if err := fn(song); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка чтения строк: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
+
+// keysetPage формирует страницу из выборки с одной лишней строкой и проставляет курсоры
+// after - курсор, от которого шла выборка, nil для первой страницы
+func keysetPage(keys []sortKey, songs []domain.Song, limit int, after *cursor) *domain.LibPage {
+	backward := after != nil && after.Backward
+	more := len(songs) > limit
+	if more {
+		songs = songs[:limit]
+	}
+	if backward {
+		for i, j := 0, len(songs)-1; i < j; i, j = i+1, j-1 {
+			songs[i], songs[j] = songs[j], songs[i]
+		}
+	}
+
+	page := &domain.LibPage{Items: songs}
+	if len(songs) == 0 {
+		return page
+	}
+
+	if more || backward {
+		page.NextCursor = encodeCursor(keys, songs[len(songs)-1], false)
+	}
+	if (more && backward) || (after != nil && !backward) {
+		page.PrevCursor = encodeCursor(keys, songs[0], true)
+	}
+
+	return page
+}
+
+// countLib подсчитывает количество песен по фильтру
+func (r *SongRepo) countLib(filter domain.LibFilter) (*uint64, error) {
+	sqlQuery, args, err := applyLibFilter(psql.Select("COUNT(*)").From("song"), filter).ToSql()
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка генерации SQL-запроса: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	var total uint64
 	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT text FROM song WHERE id = $1`, id).Scan(&text)
+	err = postgres.DbService.Db.QueryRowContext(timeoutCtx, sqlQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return &total, nil
+}
+
+// GetSong получает песню со всеми полями по идентификатору
+// id - идентификатор песни
+func (r *SongRepo) GetSong(id domain.Id) (*domain.Song, error) {
+	names := make([]string, 0, len(songColumns))
+	for _, column := range songColumns {
+		names = append(names, column.column)
+	}
+	sqlQuery, args, err := psql.Select(names...).From("song").Where("id = ? AND deleted_at IS NULL", id).ToSql()
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка генерации SQL-запроса: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	var song domain.Song
+	dest := make([]interface{}, 0, len(songColumns))
+	for _, column := range songColumns {
+		dest = append(dest, column.dest(&song))
+	}
 
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = postgres.DbService.Db.QueryRowContext(timeoutCtx, sqlQuery, args...).Scan(dest...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, &e.RowsNotFoundError{
 				Err:  "Песня с таким идентификатором не существует",
-				Code: http.StatusBadRequest,
+				Code: http.StatusNotFound,
 			}
 		}
 		return nil, &e.DbQueryError{
@@ -121,100 +255,218 @@ func (r *SongRepo) GetText(id domain.Id) (*domain.SongText, error) {
 		}
 	}
 
-	return &text, nil
+	return &song, nil
 }
 
-// DelSong удаляет песню по идентификатору
+// GetLyrics получает разделы текста песни по идентификатору
 // id - идентификатор песни
-func (r *SongRepo) DelSong(id domain.Id) error {
+func (r *SongRepo) GetLyrics(id domain.Id) (*domain.Lyrics, error) {
+	var sections []byte
 	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	_, err := postgres.DbService.Db.ExecContext(timeoutCtx, "DELETE FROM song WHERE id = $1", id)
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT sections FROM song WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&sections)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return &e.RowsNotFoundError{
+			return nil, &e.RowsNotFoundError{
 				Err:  "Песня с таким идентификатором не существует",
 				Code: http.StatusBadRequest,
 			}
 		}
-		return &e.DbQueryError{
+		return nil, &e.DbQueryError{
 			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
 			Code: http.StatusInternalServerError,
 		}
 	}
 
-	return nil
-}
-
-// ChangeSong изменяет данные песни
-// song - объект песни с новыми данными
-func (r *SongRepo) ChangeSong(song domain.Song) error {
-	query := sq.Update("song").Where("id = ?", song.ID)
-
-	if song.Name != "" {
-		query = query.Set("song_name", song.Name)
-	}
-	if song.Group != "" {
-		query = query.Set("group_name", song.Group)
-	}
-	if !song.Date.IsZero() {
-		query = query.Set("release_date", song.Date)
-	}
-	if song.Text != "" {
-		query = query.Set("text", song.Text)
-	}
-	if song.Link != "" {
-		query = query.Set("link", song.Link)
+	var lyrics domain.Lyrics
+	if err := json.Unmarshal(sections, &lyrics); err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка разбора разделов текста: %v", err),
+			Code: http.StatusInternalServerError,
+		}
 	}
 
-	sqlQuery, args, err := query.ToSql()
-	placeholderCount := strings.Count(sqlQuery, "?")
-	for i := 0; i <= placeholderCount; i++ {
-		sqlQuery = strings.Replace(sqlQuery, "?", fmt.Sprintf("$%d", i+1), 1)
-	}
+	return &lyrics, nil
+}
 
-	if err != nil {
-		return &e.DbQueryError{
-			Err:  fmt.Sprintf("Sql query generation error: %v", err),
-			Code: http.StatusInternalServerError,
+// DelSong перемещает песню в корзину, окончательно она удаляется через TrashRepo
+// id - идентификатор песни
+// actor - автор изменения
+func (r *SongRepo) DelSong(id domain.Id, actor string) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(timeoutCtx, "UPDATE song SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL", id)
+		if err != nil {
+			return &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+				Code: http.StatusInternalServerError,
+			}
 		}
-	}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return nil
+		}
+
+		return recordRevision(timeoutCtx, tx, id, domain.RevisionDelete, actor)
+	})
+}
 
+// ChangeSong изменяет данные песни и записывает ревизию
+// song - объект песни с новыми данными
+// actor - автор изменения
+func (r *SongRepo) ChangeSong(song domain.Song, actor string) error {
 	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	_, err = postgres.DbService.Db.ExecContext(timeoutCtx, sqlQuery, args...)
 
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return &e.RowsNotFoundError{
-				Err:  "Song with this id not exist",
-				Code: http.StatusBadRequest,
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		query := psql.Update("song").Where("id = ? AND deleted_at IS NULL", song.ID)
+
+		if song.Name != "" {
+			query = query.Set("song_name", song.Name)
+		}
+		if song.ArtistID != 0 {
+			query = query.
+				Set("artist_id", song.ArtistID).
+				Set("group_name", sq.Expr("(SELECT name FROM artist WHERE id = ?)", song.ArtistID))
+		} else if song.Group != "" {
+			artistID, group, err := upsertArtist(timeoutCtx, tx, song.Group)
+			if err != nil {
+				return err
 			}
+			query = query.Set("artist_id", artistID).Set("group_name", group)
 		}
-		return &e.DbQueryError{
-			Err:  fmt.Sprintf("DB query error: %v", err),
-			Code: http.StatusInternalServerError,
+		// Измененные пользователем поля больше не принадлежат источнику данных
+		sources := domain.MetadataSources{}
+		if !song.Date.IsZero() {
+			query = query.Set("release_date", song.Date)
+			sources[domain.FieldReleaseDate] = domain.MetadataSourceUser
+		}
+		if song.Text != "" {
+			text, sections := lyricsValues(song.Text)
+			query = query.Set("text", text).Set("sections", sections)
+			sources[domain.FieldText] = domain.MetadataSourceUser
+		}
+		if song.Link != "" {
+			query = query.Set("link", song.Link)
+			sources[domain.FieldLink] = domain.MetadataSourceUser
+		}
+		if len(sources) > 0 {
+			query = query.Set("metadata_sources", sq.Expr("metadata_sources || ?::jsonb", sourcesValue(sources)))
 		}
-	}
 
-	return nil
+		sqlQuery, args, err := query.ToSql()
+		if err != nil {
+			return &e.DbQueryError{
+				Err:  fmt.Sprintf("Sql query generation error: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+
+		_, err = tx.ExecContext(timeoutCtx, sqlQuery, args...)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && (pqErr.Code == pqForeignKeyViolation || pqErr.Code == pqNotNullViolation) {
+				return &e.InvalidInputData{
+					Err:  "Artist with this id not exist",
+					Code: http.StatusBadRequest,
+				}
+			}
+			return &e.DbQueryError{
+				Err:  fmt.Sprintf("DB query error: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+
+		return recordRevision(timeoutCtx, tx, song.ID, domain.RevisionChange, actor)
+	})
 }
 
-// CreateSong создает новую песню
+// CreateSong создает новую песню, исполнитель находится по названию или создается
 // song - объект новой песни
-func (r *SongRepo) CreateSong(song domain.Song) (*domain.Id, error) {
+// actor - автор изменения
+func (r *SongRepo) CreateSong(song domain.Song, actor string) (*domain.Id, error) {
 	var id uint64
 	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `INSERT INTO song (song_name, group_name, release_date, text, link) VALUES ($1, $2, $3, $4, $5) RETURNING id`, song.Name, song.Group, song.Date, song.Text, song.Link).Scan(&id)
 
+	err := withTx(timeoutCtx, func(tx *sql.Tx) error {
+		var err error
+		id, err = insertSong(timeoutCtx, tx, song, domain.EnrichmentSucceeded)
+		if err != nil {
+			return err
+		}
+
+		return recordRevision(timeoutCtx, tx, id, domain.RevisionCreate, actor)
+	})
 	if err != nil {
-		return nil, &e.DbQueryError{
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// insertSong добавляет песню в транзакции tx, исполнитель находится по названию или создается
+// status - состояние получения дополнительных данных песни
+func insertSong(ctx context.Context, tx *sql.Tx, song domain.Song, status string) (uint64, error) {
+	artistID, group, err := upsertArtist(ctx, tx, song.Group)
+	if err != nil {
+		return 0, err
+	}
+
+	var id uint64
+	text, sections := lyricsValues(song.Text)
+	err = tx.QueryRowContext(ctx, `INSERT INTO song (song_name, group_name, artist_id, release_date, text, sections, link, enrichment_status, metadata_sources) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`, song.Name, group, artistID, nullDate(song.Date), text, sections, song.Link, status, sourcesValue(song.Sources)).Scan(&id)
+	if err != nil {
+		return 0, &e.DbQueryError{
 			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
 			Code: http.StatusInternalServerError,
 		}
 	}
 
-	return &id, nil
+	return id, nil
+}
+
+// sourcesValue возвращает источники полей песни для сохранения
+func sourcesValue(sources domain.MetadataSources) string {
+	if sources == nil {
+		return "{}"
+	}
+	data, _ := json.Marshal(sources)
+	return string(data)
+}
+
+// lyricsValues возвращает нормализованный текст и его разделы для сохранения
+func lyricsValues(text string) (string, string) {
+	text = domain.NormalizeText(text)
+	sections, _ := json.Marshal(domain.ParseLyrics(text))
+	return text, string(sections)
+}
+
+// withTx выполняет fn в транзакции, фиксируя ее при успехе и откатывая при ошибке
+func withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := postgres.DbService.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка начала транзакции: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		if err := tx.Rollback(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error rolling back transaction: %v", err))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка фиксации транзакции: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,389 @@
+package realization
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	_ = logger.NewLogger()
+}
+
+// newMockDB подменяет подключение к базе данных на sqlmock
+func newMockDB(t *testing.T) sqlmock.Sqlmock {
+	mockDB, sqlMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = mockDB.Close()
+	})
+
+	postgres.DbService = &postgres.DB{Db: mockDB}
+	return sqlMock
+}
+
+// Тест для метода GetLib с полнотекстовым поиском
+func TestSongRepo_GetLib_Search(t *testing.T) {
+	sqlMock := newMockDB(t)
+	date := time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, text, link, enrichment_status, metadata_sources, "+
+			"ts_rank(search_en, websearch_to_tsquery('english', $1)) AS rank, "+
+			"ts_headline('english', coalesce(text, ''), websearch_to_tsquery('english', $2), $3) "+
+			"FROM song WHERE deleted_at IS NULL AND group_name LIKE $4 AND search_en @@ websearch_to_tsquery('english', $5) "+
+			"ORDER BY rank DESC, id ASC LIMIT 21",
+	)).
+		WithArgs("love", "love", headlineOptions, "%Muse%", "love").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_name", "artist_id", "song_name", "release_date", "text", "link", "enrichment_status", "metadata_sources", "rank", "ts_headline"}).
+			AddRow(1, "Muse", 1, "Supermassive Black Hole", date, "text", "link", "succeeded", []byte(`{"text":"api"}`), 0.6, "I <b>love</b>"))
+
+	repo := NewSongRepo()
+	songs, err := repo.GetLib(domain.LibFilter{
+		Song:       domain.Song{Group: "Muse"},
+		Query:      "love",
+		SearchLang: domain.SearchLangEn,
+		Fields:     domain.SongFields,
+	}, domain.Pagination{Limit: domain.DefaultLibLimit})
+
+	assert.NoError(t, err)
+	assert.Len(t, songs.Items, 1)
+	assert.Equal(t, 0.6, songs.Items[0].Rank)
+	assert.Equal(t, "I <b>love</b>", songs.Items[0].Snippet)
+	assert.Equal(t, domain.MetadataSources{domain.FieldText: domain.MetadataProviderAPI}, songs.Items[0].Sources)
+	assert.Empty(t, songs.NextCursor)
+	assert.Empty(t, songs.PrevCursor)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода GetLib с постраничной пагинацией
+func TestSongRepo_GetLib_Page(t *testing.T) {
+	sqlMock := newMockDB(t)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, link, enrichment_status FROM song WHERE deleted_at IS NULL AND song_name LIKE $1 ORDER BY id ASC LIMIT 20 OFFSET 20",
+	)).
+		WithArgs("%Hole%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_name", "artist_id", "song_name", "release_date", "link", "enrichment_status"}))
+
+	repo := NewSongRepo()
+	songs, err := repo.GetLib(domain.LibFilter{Song: domain.Song{Name: "Hole"}}, domain.Pagination{Page: 2, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Empty(t, songs.Items)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода GetLib с ошибкой при чтении строк - неполная страница не возвращается
+func TestSongRepo_GetLib_RowError(t *testing.T) {
+	sqlMock := newMockDB(t)
+	columns := []string{"id", "group_name", "artist_id", "song_name", "release_date", "link", "enrichment_status"}
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, link, enrichment_status FROM song WHERE deleted_at IS NULL ORDER BY id ASC LIMIT 3",
+	)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "g", 1, "s1", nil, "", "succeeded").
+			AddRow(2, "g", 1, "s2", nil, "", "succeeded").
+			RowError(1, errors.New("connection reset")))
+
+	page, err := NewSongRepo().GetLib(domain.LibFilter{}, domain.Pagination{Limit: 2})
+
+	assert.Nil(t, page)
+	assert.Equal(t, http.StatusInternalServerError, err.(*domain.BaseError).Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода GetLib с пагинацией курсором и подсчетом общего количества
+func TestSongRepo_GetLib_Cursor(t *testing.T) {
+	sqlMock := newMockDB(t)
+	columns := []string{"id", "group_name", "artist_id", "song_name", "release_date", "link", "enrichment_status"}
+	date := time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, link, enrichment_status FROM song WHERE deleted_at IS NULL ORDER BY id ASC LIMIT 3",
+	)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "g", 1, "s1", date, "", "succeeded").
+			AddRow(2, "g", 1, "s2", date, "", "succeeded").
+			AddRow(3, "g", 1, "s3", date, "", "succeeded"))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM song WHERE deleted_at IS NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	repo := NewSongRepo()
+	first, err := repo.GetLib(domain.LibFilter{}, domain.Pagination{Limit: 2, Total: true})
+	assert.NoError(t, err)
+	assert.Len(t, first.Items, 2)
+	assert.Equal(t, uint64(3), *first.Total)
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Empty(t, first.PrevCursor)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, link, enrichment_status FROM song WHERE deleted_at IS NULL AND ((id > $1)) ORDER BY id ASC LIMIT 3",
+	)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "g", 1, "s3", date, "", "succeeded"))
+
+	second, err := repo.GetLib(domain.LibFilter{}, domain.Pagination{Limit: 2, Cursor: first.NextCursor})
+	assert.NoError(t, err)
+	assert.Len(t, second.Items, 1)
+	assert.Empty(t, second.NextCursor)
+	assert.NotEmpty(t, second.PrevCursor)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, link, enrichment_status FROM song WHERE deleted_at IS NULL AND ((id < $1)) ORDER BY id DESC LIMIT 3",
+	)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(2, "g", 1, "s2", date, "", "succeeded").
+			AddRow(1, "g", 1, "s1", date, "", "succeeded"))
+
+	prev, err := repo.GetLib(domain.LibFilter{}, domain.Pagination{Limit: 2, Cursor: second.PrevCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), prev.Items[0].ID)
+	assert.Equal(t, uint64(2), prev.Items[1].ID)
+	assert.NotEmpty(t, prev.NextCursor)
+	assert.Empty(t, prev.PrevCursor)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода GetLib с некорректным курсором
+func TestSongRepo_GetLib_InvalidCursor(t *testing.T) {
+	newMockDB(t)
+
+	repo := NewSongRepo()
+	_, err := repo.GetLib(domain.LibFilter{Query: "love"}, domain.Pagination{Limit: 2, Cursor: encodeCursor(libSortKeys(domain.LibFilter{}), domain.Song{ID: 1}, false)})
+
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.BaseError).Code)
+}
+
+// Тест для метода GetLib с сортировкой, диапазоном дат и точным совпадением
+func TestSongRepo_GetLib_SortAndRange(t *testing.T) {
+	sqlMock := newMockDB(t)
+	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := domain.LibFilter{
+		Song:     domain.Song{Group: "Muse", Name: "Hole"},
+		Match:    domain.MatchMode{Group: domain.MatchExact},
+		DateFrom: from,
+		DateTo:   to,
+		Sort: []domain.SortField{
+			{Field: domain.SortReleaseDate},
+			{Field: domain.SortName, Desc: true},
+		},
+	}
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, link, enrichment_status FROM song "+
+			"WHERE deleted_at IS NULL AND song_name LIKE $1 AND group_name = $2 AND release_date >= $3 AND release_date <= $4 "+
+			"ORDER BY COALESCE(release_date, 'infinity') ASC, song_name DESC, id ASC LIMIT 2",
+	)).
+		WithArgs("%Hole%", "Muse", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_name", "artist_id", "song_name", "release_date", "link", "enrichment_status"}).
+			AddRow(7, "Muse", 1, "Black Hole", from, "", "succeeded").
+			AddRow(8, "Muse", 1, "Hole", to, "", "succeeded"))
+
+	repo := NewSongRepo()
+	page, err := repo.GetLib(filter, domain.Pagination{Limit: 1})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, page.NextCursor)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, link, enrichment_status FROM song "+
+			"WHERE deleted_at IS NULL AND song_name LIKE $1 AND group_name = $2 AND release_date >= $3 AND release_date <= $4 "+
+			"AND ((COALESCE(release_date, 'infinity') > $5) OR (COALESCE(release_date, 'infinity') = $6 AND song_name < $7) "+
+			"OR (COALESCE(release_date, 'infinity') = $8 AND song_name = $9 AND id > $10)) "+
+			"ORDER BY COALESCE(release_date, 'infinity') ASC, song_name DESC, id ASC LIMIT 2",
+	)).
+		WithArgs("%Hole%", "Muse", from, to, "2000-01-01", "2000-01-01", "Black Hole", "2000-01-01", "Black Hole", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_name", "artist_id", "song_name", "release_date", "link", "enrichment_status"}).
+			AddRow(8, "Muse", 1, "Hole", to, "", "succeeded"))
+
+	_, err = repo.GetLib(filter, domain.Pagination{Limit: 1, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+
+	filter.Sort = filter.Sort[:1]
+	_, err = repo.GetLib(filter, domain.Pagination{Limit: 1, Cursor: page.NextCursor})
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.BaseError).Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода GetLib с песней с неизвестной датой выпуска - дата остается нулевой,
+// а курсор продолжает выборку после песен с известной датой
+func TestSongRepo_GetLib_UnknownReleaseDate(t *testing.T) {
+	sqlMock := newMockDB(t)
+	filter := domain.LibFilter{Sort: []domain.SortField{{Field: domain.SortReleaseDate}}}
+	columns := []string{"id", "group_name", "artist_id", "song_name", "release_date", "link", "enrichment_status"}
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, link, enrichment_status FROM song " +
+			"WHERE deleted_at IS NULL ORDER BY COALESCE(release_date, 'infinity') ASC, id ASC LIMIT 2",
+	)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(7, "Muse", 1, "Hysteria", nil, "", domain.EnrichmentPending).
+			AddRow(8, "Muse", 1, "Uprising", nil, "", domain.EnrichmentPending))
+
+	repo := NewSongRepo()
+	page, err := repo.GetLib(filter, domain.Pagination{Limit: 1})
+	assert.NoError(t, err)
+	assert.True(t, page.Items[0].Date.IsZero())
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, link, enrichment_status FROM song "+
+			"WHERE deleted_at IS NULL AND ((COALESCE(release_date, 'infinity') > $1) OR (COALESCE(release_date, 'infinity') = $2 AND id > $3)) "+
+			"ORDER BY COALESCE(release_date, 'infinity') ASC, id ASC LIMIT 2",
+	)).
+		WithArgs("infinity", "infinity", 7).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(8, "Muse", 1, "Uprising", nil, "", domain.EnrichmentPending))
+
+	_, err = repo.GetLib(filter, domain.Pagination{Limit: 1, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода GetLib с проекцией полей
+func TestSongRepo_GetLib_Fields(t *testing.T) {
+	sqlMock := newMockDB(t)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, text FROM song WHERE deleted_at IS NULL ORDER BY group_name DESC, id ASC LIMIT 21",
+	)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_name", "text"}).AddRow(1, "Muse", "text"))
+
+	repo := NewSongRepo()
+	page, err := repo.GetLib(domain.LibFilter{
+		Fields: []string{domain.FieldText},
+		Sort:   []domain.SortField{{Field: domain.SortGroup, Desc: true}},
+	}, domain.Pagination{Limit: domain.DefaultLibLimit})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "text", page.Items[0].Text)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода ExportLib - песни передаются по одной в порядке сортировки без лимита
+func TestSongRepo_ExportLib(t *testing.T) {
+	sqlMock := newMockDB(t)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, song_name, text FROM song WHERE deleted_at IS NULL AND group_name = $1 ORDER BY song_name ASC, id ASC",
+	)).
+		WithArgs("Muse").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_name", "song_name", "text"}).
+			AddRow(2, "Muse", "Hysteria", "text").
+			AddRow(1, "Muse", "Uprising", "text"))
+
+	var names []string
+	err := NewSongRepo().ExportLib(context.Background(), domain.LibFilter{
+		Song:   domain.Song{Group: "Muse"},
+		Match:  domain.MatchMode{Group: domain.MatchExact},
+		Sort:   []domain.SortField{{Field: domain.SortName}},
+		Fields: []string{domain.FieldID, domain.FieldGroup, domain.FieldText},
+	}, func(song domain.Song) error {
+		names = append(names, song.Name)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Hysteria", "Uprising"}, names)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода GetSong с несуществующей песней
+func TestSongRepo_GetSong_NotFound(t *testing.T) {
+	sqlMock := newMockDB(t)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, group_name, artist_id, song_name, release_date, text, link, enrichment_status, metadata_sources FROM song WHERE id = $1 AND deleted_at IS NULL",
+	)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	song, err := NewSongRepo().GetSong(7)
+
+	assert.Nil(t, song)
+	assert.Equal(t, http.StatusNotFound, err.(*domain.BaseError).Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода CreateSong с привязкой к исполнителю
+func TestSongRepo_CreateSong(t *testing.T) {
+	sqlMock := newMockDB(t)
+	date := time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(regexp.QuoteMeta("INSERT INTO artist (name) VALUES ($1)")).
+		WithArgs("muse").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Muse"))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("INSERT INTO song (song_name, group_name, artist_id, release_date, text, sections, link, enrichment_status, metadata_sources)")).
+		WithArgs("Hysteria", "Muse", 3, date, "text", `[{"index":1,"type":"verse","lines":["text"]}]`, "link", domain.EnrichmentSucceeded, "{}").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO song_revision (song_id, action, snapshot, actor)")).
+		WithArgs(domain.RevisionCreate, "editor", 10).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+
+	repo := NewSongRepo()
+	id, err := repo.CreateSong(*domain.NewSong("Hysteria", "muse", "text", "link", date), "editor")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), *id)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода GetLib с фильтром по всем меткам и любому из жанров, повторы меток
+// в разном регистре учитываются один раз
+func TestSongRepo_GetLib_Tags(t *testing.T) {
+	sqlMock := newMockDB(t)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id FROM song "+
+			"WHERE deleted_at IS NULL AND id IN (SELECT l.song_id FROM song_tag l JOIN tag t ON t.id = l.tag_id WHERE lower(t.name) = ANY ($1) "+
+			"GROUP BY l.song_id HAVING COUNT(DISTINCT t.id) = $2) "+
+			"AND id IN (SELECT l.song_id FROM song_genre l JOIN genre t ON t.id = l.genre_id WHERE lower(t.name) = ANY ($3)) "+
+			"ORDER BY id ASC LIMIT 21",
+	)).
+		WithArgs(pq.Array([]string{"wedding", "cleared"}), 2, pq.Array([]string{"rock"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	repo := NewSongRepo()
+	page, err := repo.GetLib(domain.LibFilter{
+		Tags:   domain.TagFilter{Names: []string{"Wedding", "cleared", "wedding"}, All: true},
+		Genres: domain.TagFilter{Names: []string{"rock"}},
+		Fields: []string{domain.FieldID},
+	}, domain.Pagination{Limit: domain.DefaultLibLimit})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// Тест для метода DelSong с перемещением песни в корзину
+func TestSongRepo_DelSong(t *testing.T) {
+	sqlMock := newMockDB(t)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE song SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL")).
+		WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO song_revision (song_id, action, snapshot, actor)")).
+		WithArgs(domain.RevisionDelete, "editor", 10).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+
+	repo := NewSongRepo()
+	err := repo.DelSong(10, "editor")
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
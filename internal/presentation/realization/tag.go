@@ -0,0 +1,191 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+// tagTable - таблицы классификатора и его связей с песнями
+type tagTable struct {
+	table  string // Таблица классификатора
+	link   string // Таблица связей с песнями
+	column string // Колонка классификатора в таблице связей
+}
+
+// tagTables - таблицы по видам классификаторов
+var tagTables = map[string]tagTable{
+	domain.TagKindTag:   {table: "tag", link: "song_tag", column: "tag_id"},
+	domain.TagKindGenre: {table: "genre", link: "song_genre", column: "genre_id"},
+}
+
+// TagRepo - реализация репозитория для работы с жанрами и метками в базе данных
+type TagRepo struct{}
+
+func NewTagRepo() *TagRepo {
+	return &TagRepo{}
+}
+
+// GetTags получает жанры или метки с количеством песен, песни из корзины не учитываются
+// kind - вид классификатора
+func (r *TagRepo) GetTags(kind string) (*[]domain.Tag, error) {
+	t := tagTables[kind]
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, fmt.Sprintf(`SELECT t.id, t.name, COUNT(s.id)
+		FROM %s t LEFT JOIN %s l ON l.%s = t.id
+		LEFT JOIN song s ON s.id = l.song_id AND s.deleted_at IS NULL
+		GROUP BY t.id, t.name ORDER BY t.name`, t.table, t.link, t.column))
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	result := []domain.Tag{}
+	for rows.Next() {
+		var tag domain.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Songs); err != nil {
+			return nil, &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		result = append(result, tag)
+	}
+
+	return &result, nil
+}
+
+// AttachTags привязывает жанры и метки к песням, отсутствующие создаются
+// binding - песни и названия жанров и меток
+func (r *TagRepo) AttachTags(binding domain.TagBinding) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		for kind, names := range bindingNames(binding) {
+			t := tagTables[kind]
+			_, err := tx.ExecContext(timeoutCtx, fmt.Sprintf(`INSERT INTO %s (name) SELECT unnest($1::text[])
+				ON CONFLICT ((lower(name))) DO NOTHING`, t.table), pq.Array(names))
+			if err != nil {
+				return tagQueryError(err)
+			}
+
+			_, err = tx.ExecContext(timeoutCtx, fmt.Sprintf(`INSERT INTO %s (song_id, %s)
+				SELECT s.id, t.id FROM unnest($1::integer[]) AS s (id) CROSS JOIN %s t
+				WHERE lower(t.name) = ANY ($2)
+				ON CONFLICT DO NOTHING`, t.link, t.column, t.table), pq.Array(songIds(binding.Songs)), pq.Array(lowerNames(names)))
+			if err != nil {
+				return tagQueryError(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DetachTags отвязывает жанры и метки от песен, сами жанры и метки остаются
+// binding - песни и названия жанров и меток
+func (r *TagRepo) DetachTags(binding domain.TagBinding) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		for kind, names := range bindingNames(binding) {
+			t := tagTables[kind]
+			_, err := tx.ExecContext(timeoutCtx, fmt.Sprintf(`DELETE FROM %s
+				WHERE song_id = ANY ($1) AND %s IN (SELECT id FROM %s WHERE lower(name) = ANY ($2))`, t.link, t.column, t.table),
+				pq.Array(songIds(binding.Songs)), pq.Array(lowerNames(names)))
+			if err != nil {
+				return tagQueryError(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// tagCondition возвращает условие фильтра библиотеки по жанрам или меткам
+func tagCondition(kind string, filter domain.TagFilter) sq.Sqlizer {
+	t := tagTables[kind]
+	subquery := fmt.Sprintf("SELECT l.song_id FROM %s l JOIN %s t ON t.id = l.%s WHERE lower(t.name) = ANY (?)", t.link, t.table, t.column)
+	names := lowerNames(filter.Names)
+	if !filter.All {
+		return sq.Expr(fmt.Sprintf("id IN (%s)", subquery), pq.Array(names))
+	}
+
+	return sq.Expr(fmt.Sprintf("id IN (%s GROUP BY l.song_id HAVING COUNT(DISTINCT t.id) = ?)", subquery), pq.Array(names), len(names))
+}
+
+// bindingNames возвращает непустые списки названий по видам классификаторов
+func bindingNames(binding domain.TagBinding) map[string][]string {
+	result := make(map[string][]string, 2)
+	if len(binding.Tags) > 0 {
+		result[domain.TagKindTag] = binding.Tags
+	}
+	if len(binding.Genres) > 0 {
+		result[domain.TagKindGenre] = binding.Genres
+	}
+
+	return result
+}
+
+// lowerNames приводит названия к нижнему регистру для сравнения без учета регистра,
+// повторы отбрасываются, чтобы режим all сравнивал число меток с числом разных названий
+func lowerNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// songIds преобразует идентификаторы песен для передачи массивом
+func songIds(ids []domain.Id) []int64 {
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, int64(id))
+	}
+
+	return result
+}
+
+// tagQueryError преобразует ошибку базы данных при изменении жанров и меток
+func tagQueryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation {
+		return &e.InvalidInputData{
+			Err:  "Song with this id not exist",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return &e.DbQueryError{
+		Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
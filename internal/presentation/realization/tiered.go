@@ -0,0 +1,224 @@
+package realization
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// cacheInvalidationChannel - канал Redis, в который экземпляры сервиса рассылают измененные ключи
+const cacheInvalidationChannel = "cache:invalidate"
+
+// TieredCache - двухуровневый кэш: значения в памяти процесса перед Redis.
+// Записи идут в оба уровня, об удаленных ключах и измененных счетчиках остальные экземпляры
+// сервиса узнают через pub/sub Redis и удаляют их из памяти. Сообщения, потерянные при разрыве
+// подключения, не приводят к устареванию дольше времени хранения в памяти
+type TieredCache struct {
+	local  *lruCache
+	remote *RedisRepo
+	ttl    time.Duration
+	origin string // Идентификатор экземпляра, свои сообщения не обрабатываются
+
+	pubsub *redis.PubSub
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// cacheInvalidation - сообщение об измененных ключах
+type cacheInvalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys"`
+}
+
+// NewTieredCache создает кэш в памяти перед Redis и подписывается на сообщения об измененных ключах
+// remote - подключение к Redis
+// config - размер и время хранения кэша в памяти
+func NewTieredCache(remote *RedisRepo, config domain.LocalCacheConfig) *TieredCache {
+	origin := make([]byte, 8)
+	_, _ = rand.Read(origin)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &TieredCache{
+		local:  newLRUCache(config.Entries),
+		remote: remote,
+		ttl:    config.TTL,
+		origin: hex.EncodeToString(origin),
+		pubsub: remote.db.Subscribe(ctx, cacheInvalidationChannel),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go c.listen(ctx)
+
+	logger.Logger.Info(fmt.Sprintf("Local cache for %d entries was created", config.Entries))
+	return c
+}
+
+// Get получает значение ключа из памяти, при промахе - из Redis с записью в память
+// key - ключ
+func (c *TieredCache) Get(key string) ([]byte, error) {
+	if value, ok := c.local.get(key, time.Now()); ok {
+		c.hits.Add(1)
+		return value, nil
+	}
+	c.misses.Add(1)
+
+	value, err := c.remote.Get(key)
+	if err != nil || value == nil {
+		return value, err
+	}
+
+	c.local.set(key, value, time.Now().Add(c.ttl))
+	return value, nil
+}
+
+// Set записывает значение ключа в Redis и в память. Остальные экземпляры не оповещаются:
+// ключи кэша содержат версию и после записи не меняются, поэтому их копии остаются верными
+// key - ключ
+// value - значение
+// ttl - время хранения, 0 - без срока хранения; в памяти не дольше времени хранения кэша в памяти
+func (c *TieredCache) Set(key string, value []byte, ttl time.Duration) error {
+	err := c.remote.Set(key, value, ttl)
+	if err != nil {
+		return err
+	}
+
+	if ttl <= 0 || ttl > c.ttl {
+		ttl = c.ttl
+	}
+	c.local.set(key, value, time.Now().Add(ttl))
+
+	return nil
+}
+
+// Del удаляет ключи из Redis и из памяти всех экземпляров
+// keys - ключи
+func (c *TieredCache) Del(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	err := c.remote.Del(keys...)
+	if err != nil {
+		return err
+	}
+	c.local.del(keys...)
+
+	return c.publish(keys...)
+}
+
+// Incr увеличивает число в ключе Redis на 1, ключ удаляется из памяти всех экземпляров
+// key - ключ
+func (c *TieredCache) Incr(key string) (int64, error) {
+	value, err := c.remote.Incr(key)
+	if err != nil {
+		return 0, err
+	}
+	c.local.del(key)
+
+	return value, c.publish(key)
+}
+
+// Stats возвращает статистику попаданий в память и в Redis
+func (c *TieredCache) Stats() []domain.CacheTierStats {
+	entries, evictions := c.local.stats()
+
+	return append([]domain.CacheTierStats{{
+		Tier:      domain.CacheTierLocal,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: evictions,
+		Entries:   entries,
+	}}, c.remote.Stats()...)
+}
+
+// Close отписывается от сообщений и закрывает подключение к Redis
+func (c *TieredCache) Close() error {
+	c.cancel()
+	err := c.pubsub.Close()
+	if err != nil {
+		logger.Logger.Error(fmt.Sprintf("Cache invalidation unsubscribing error - %v", err))
+	}
+	<-c.done
+
+	return c.remote.Close()
+}
+
+// publish рассылает остальным экземплярам измененные ключи
+func (c *TieredCache) publish(keys ...string) error {
+	data, err := json.Marshal(cacheInvalidation{Origin: c.origin, Keys: keys})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = c.remote.db.Publish(ctx, cacheInvalidationChannel, data).Err()
+	if err != nil {
+		return &e.RedisQueryError{
+			Err:  fmt.Sprintf("Ошибка рассылки измененных ключей в Redis: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
+
+// listen удаляет из памяти ключи, измененные другими экземплярами, до отмены ctx.
+// После разрыва подключения сообщения могли потеряться, поэтому память очищается
+func (c *TieredCache) listen(ctx context.Context) {
+	defer close(c.done)
+
+	for {
+		msg, err := c.pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Logger.Warn(fmt.Sprintf("Cache invalidation receiving error - %v", err))
+			c.local.clear()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			c.local.clear()
+		case *redis.Message:
+			c.invalidate(msg.Payload)
+		}
+	}
+}
+
+// invalidate удаляет из памяти ключи из сообщения другого экземпляра
+func (c *TieredCache) invalidate(payload string) {
+	var msg cacheInvalidation
+	err := json.Unmarshal([]byte(payload), &msg)
+	if err != nil {
+		logger.Logger.Warn(fmt.Sprintf("Invalid cache invalidation message - %v", err))
+		c.local.clear()
+		return
+	}
+	if msg.Origin == c.origin {
+		return
+	}
+
+	c.local.del(msg.Keys...)
+}
@@ -0,0 +1,120 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"time"
+)
+
+// TimingRepo - реализация репозитория для работы с синхронизированным текстом песен в базе данных
+type TimingRepo struct{}
+
+func NewTimingRepo() *TimingRepo {
+	return &TimingRepo{}
+}
+
+// GetTiming получает синхронизированный текст песни, строки упорядочены по времени
+// songID - идентификатор песни
+func (r *TimingRepo) GetTiming(songID domain.Id) (*domain.TimedLyrics, error) {
+	lyrics := domain.TimedLyrics{SongID: songID, Lines: []domain.TimedLine{}}
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT song_name, group_name FROM song WHERE id = $1 AND deleted_at IS NULL`, songID).
+		Scan(&lyrics.Name, &lyrics.Group)
+	if err != nil {
+		return nil, timingQueryError(err)
+	}
+
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, `SELECT time_ms, text, words FROM song_timing
+		WHERE song_id = $1 ORDER BY position`, songID)
+	if err != nil {
+		return nil, timingQueryError(err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	for rows.Next() {
+		var line domain.TimedLine
+		var words []byte
+		if err := rows.Scan(&line.TimeMs, &line.Text, &words); err != nil {
+			return nil, &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		if words != nil {
+			if err := json.Unmarshal(words, &line.Words); err != nil {
+				return nil, &e.DbQueryError{
+					Err:  fmt.Sprintf("Ошибка разбора слов строки: %v", err),
+					Code: http.StatusInternalServerError,
+				}
+			}
+		}
+		lyrics.Lines = append(lyrics.Lines, line)
+	}
+
+	return &lyrics, nil
+}
+
+// SetTiming заменяет синхронизированный текст песни
+// songID - идентификатор песни
+// lines - строки в порядке времени
+func (r *TimingRepo) SetTiming(songID domain.Id, lines []domain.TimedLine) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		var id uint64
+		err := tx.QueryRowContext(timeoutCtx, `SELECT id FROM song WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, songID).Scan(&id)
+		if err != nil {
+			return timingQueryError(err)
+		}
+
+		_, err = tx.ExecContext(timeoutCtx, `DELETE FROM song_timing WHERE song_id = $1`, songID)
+		if err != nil {
+			return timingQueryError(err)
+		}
+
+		for i, line := range lines {
+			var words sql.NullString
+			if len(line.Words) > 0 {
+				data, _ := json.Marshal(line.Words)
+				words = sql.NullString{String: string(data), Valid: true}
+			}
+
+			_, err = tx.ExecContext(timeoutCtx, `INSERT INTO song_timing (song_id, position, time_ms, text, words) VALUES ($1, $2, $3, $4, $5)`,
+				songID, i+1, line.TimeMs, line.Text, words)
+			if err != nil {
+				return timingQueryError(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// timingQueryError преобразует ошибку базы данных при работе с синхронизированным текстом
+func timingQueryError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return &e.RowsNotFoundError{
+			Err:  "Песня с таким идентификатором не существует",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return &e.DbQueryError{
+		Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
@@ -0,0 +1,139 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// TranslationRepo - реализация репозитория для работы с переводами текстов песен в базе данных
+type TranslationRepo struct{}
+
+func NewTranslationRepo() *TranslationRepo {
+	return &TranslationRepo{}
+}
+
+// GetLangs получает языки, на которые переведен текст песни
+// songID - идентификатор песни
+func (r *TranslationRepo) GetLangs(songID domain.Id) (*[]string, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, `SELECT lang FROM song_translation
+		WHERE song_id = $1 ORDER BY lang`, songID)
+	if err != nil {
+		return nil, translationQueryError(err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	result := []string{}
+	for rows.Next() {
+		var lang string
+		if err := rows.Scan(&lang); err != nil {
+			return nil, &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		result = append(result, lang)
+	}
+
+	return &result, nil
+}
+
+// GetTranslation получает первый найденный перевод из списка языков по порядку,
+// если перевода нет ни на один из языков, возвращается nil
+// songID - идентификатор песни
+// langs - коды языков в порядке предпочтения
+func (r *TranslationRepo) GetTranslation(songID domain.Id, langs []string) (*domain.Translation, error) {
+	translation := domain.Translation{SongID: songID}
+	var sections []byte
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := postgres.DbService.Db.QueryRowContext(timeoutCtx, `SELECT lang, text, sections FROM song_translation
+		WHERE song_id = $1 AND lang = ANY ($2)
+		ORDER BY array_position($2, lang::text) LIMIT 1`, songID, pq.Array(langs)).
+		Scan(&translation.Lang, &translation.Text, &sections)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, translationQueryError(err)
+	}
+
+	if err := json.Unmarshal(sections, &translation.Sections); err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка разбора разделов перевода: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return &translation, nil
+}
+
+// SetTranslation создает или заменяет перевод текста песни
+// translation - перевод с выровненными разделами
+func (r *TranslationRepo) SetTranslation(translation domain.Translation) error {
+	sections, _ := json.Marshal(translation.Sections)
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := postgres.DbService.Db.ExecContext(timeoutCtx, `INSERT INTO song_translation (song_id, lang, text, sections)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (song_id, lang) DO UPDATE SET text = EXCLUDED.text, sections = EXCLUDED.sections`,
+		translation.SongID, translation.Lang, translation.Text, string(sections))
+	if err != nil {
+		return translationQueryError(err)
+	}
+
+	return nil
+}
+
+// DelTranslation удаляет перевод текста песни
+// songID - идентификатор песни
+// lang - код языка
+func (r *TranslationRepo) DelTranslation(songID domain.Id, lang string) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := postgres.DbService.Db.ExecContext(timeoutCtx, `DELETE FROM song_translation WHERE song_id = $1 AND lang = $2`, songID, lang)
+	if err != nil {
+		return translationQueryError(err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return &e.RowsNotFoundError{
+			Err:  "Перевода на этот язык нет",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return nil
+}
+
+// translationQueryError преобразует ошибку базы данных при работе с переводами
+func translationQueryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation {
+		return &e.RowsNotFoundError{
+			Err:  "Песня с таким идентификатором не существует",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return &e.DbQueryError{
+		Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
@@ -0,0 +1,152 @@
+package realization
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/postgres"
+	"time"
+)
+
+// TrashRepo - реализация репозитория для работы с корзиной песен в базе данных
+type TrashRepo struct{}
+
+func NewTrashRepo() *TrashRepo {
+	return &TrashRepo{}
+}
+
+// GetTrash получает песни из корзины по номеру страницы, недавно удаленные первыми
+// page - номер страницы для пагинации
+func (r *TrashRepo) GetTrash(page domain.Page) (*[]domain.TrashedSong, error) {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := postgres.DbService.Db.QueryContext(timeoutCtx, `SELECT id, group_name, artist_id, song_name, release_date, text, link, deleted_at
+		FROM song WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id
+		OFFSET $1 LIMIT 20`, 20*(page-1))
+	if err != nil {
+		return nil, &e.DbQueryError{
+			Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	result := []domain.TrashedSong{}
+	for rows.Next() {
+		var song domain.TrashedSong
+		err := rows.Scan(&song.ID, &song.Group, &song.ArtistID, &song.Name, &dateColumn{dst: &song.Date}, &song.Text, &song.Link, &song.DeletedAt)
+		if err != nil {
+			return nil, &e.DbQueryError{
+				Err:  fmt.Sprintf("Ошибка сканирования строки: %v", err),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		result = append(result, song)
+	}
+
+	return &result, nil
+}
+
+// RestoreSong возвращает песню из корзины в библиотеку
+// id - идентификатор песни
+// actor - автор изменения
+func (r *TrashRepo) RestoreSong(id domain.Id, actor string) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(timeoutCtx, `UPDATE song SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+		if err != nil {
+			return trashQueryError(err)
+		}
+		if err := checkTrashAffected(res); err != nil {
+			return err
+		}
+
+		return recordRevision(timeoutCtx, tx, id, domain.RevisionRestore, actor)
+	})
+}
+
+// PurgeSong окончательно удаляет песню из корзины, история изменений сохраняется
+// id - идентификатор песни
+// actor - автор изменения
+func (r *TrashRepo) PurgeSong(id domain.Id, actor string) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withTx(timeoutCtx, func(tx *sql.Tx) error {
+		err := recordRevisions(timeoutCtx, tx, domain.RevisionPurge, actor, "id = $3 AND deleted_at IS NOT NULL", id)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(timeoutCtx, `DELETE FROM song WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+		if err != nil {
+			return trashQueryError(err)
+		}
+
+		return checkTrashAffected(res)
+	})
+}
+
+// PurgeExpired окончательно удаляет песни, находящиеся в корзине с момента before
+// before - граница времени перемещения в корзину
+// actor - автор изменения
+func (r *TrashRepo) PurgeExpired(before time.Time, actor string) (int64, error) {
+	var purged int64
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := withTx(timeoutCtx, func(tx *sql.Tx) error {
+		err := recordRevisions(timeoutCtx, tx, domain.RevisionPurge, actor, "deleted_at < $3", before)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(timeoutCtx, `DELETE FROM song WHERE deleted_at < $1`, before)
+		if err != nil {
+			return trashQueryError(err)
+		}
+
+		purged, err = res.RowsAffected()
+		if err != nil {
+			return trashQueryError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// checkTrashAffected возвращает ошибку, если запрос не затронул ни одной песни в корзине
+func checkTrashAffected(res sql.Result) error {
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return &e.RowsNotFoundError{
+			Err:  "Песни с таким идентификатором нет в корзине",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return nil
+}
+
+// trashQueryError преобразует ошибку базы данных при работе с корзиной
+func trashQueryError(err error) error {
+	return &e.DbQueryError{
+		Err:  fmt.Sprintf("Ошибка выполнения запроса к базе данных: %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
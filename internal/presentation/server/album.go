@@ -0,0 +1,213 @@
+package server
+
+import (
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Get albums
+// @Description	Get albums list without tracks
+// @Tags			album
+// @Accept			json
+// @Produce		json
+// @Param			artistId	query		int		false	"Artist ID"
+// @Param			page		query		int		false	"Page number"
+// @Success		200			{array}		domain.Album
+// @Failure		400			{object}	map[string]string
+// @Failure		500			{object}	map[string]string
+// @Router			/albums [get]
+func (h *Handlers) GetAlbums(ctx *gin.Context) {
+	page, err := parsePage(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	var artistId uint64
+	artistIdStr := ctx.Request.URL.Query().Get("artistId")
+	if artistIdStr != "" {
+		artistId, err = strconv.ParseUint(artistIdStr, 10, 64)
+		if err != nil {
+			answerError(ctx, &e.InvalidInputData{
+				Err:  "Invalid artistId",
+				Code: http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	albums, err := AlbumService.GetAlbums(artistId, page)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, albums)
+}
+
+// @Summary		Get album
+// @Description	Get an album with its ordered track list
+// @Tags			album
+// @Accept			json
+// @Produce		json
+// @Param			id	path		uint64	true	"Album ID"
+// @Success		200	{object}	domain.Album
+// @Failure		400	{object}	map[string]string
+// @Failure		404	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/albums/{id} [get]
+func (h *Handlers) GetAlbum(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	album, err := AlbumService.GetAlbum(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, album)
+}
+
+// @Summary		Create album
+// @Description	Create a new album, tracks are numbered in the order of the list
+// @Tags			album
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			body	body		domain.Album	true	"Album details"
+// @Success		200		{object}	map[string]domain.Id
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		409		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/albums [post]
+func (h *Handlers) CreateAlbum(ctx *gin.Context) {
+	var album domain.Album
+	if err := decodeBody(ctx, &album); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	id, err := AlbumService.CreateAlbum(album)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]domain.Id{"album_id": *id})
+}
+
+// @Summary		Change album
+// @Description	Change album title, artist or release date
+// @Tags			album
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64			true	"Album ID"
+// @Param			body	body		domain.Album	true	"Album details"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		409		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/albums/{id} [patch]
+func (h *Handlers) ChangeAlbum(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	var album domain.Album
+	if err := decodeBody(ctx, &album); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	album.ID = id
+	err = AlbumService.ChangeAlbum(album)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary		Delete album
+// @Description	Delete an album, its songs stay in the library
+// @Tags			album
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id	path		uint64	true	"Album ID"
+// @Success		200	{object}	nil
+// @Failure		400	{object}	map[string]string
+// @Failure		401	{object}	map[string]string
+// @Failure		403	{object}	map[string]string
+// @Failure		404	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/albums/{id} [delete]
+func (h *Handlers) DelAlbum(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = AlbumService.DelAlbum(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary		Reorder album tracks
+// @Description	Replace the album track list, tracks are numbered in the order of the list
+// @Tags			album
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64				true	"Album ID"
+// @Param			body	body		domain.AlbumTracks	true	"Song IDs in track order"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/albums/{id}/tracks [put]
+func (h *Handlers) SetAlbumTracks(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	var tracks domain.AlbumTracks
+	if err := decodeBody(ctx, &tracks); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = AlbumService.SetTracks(id, tracks.Songs)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"net/http"
+	"song/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Get API keys
+// @Description	Get active API keys of the current user, the keys themselves are not returned
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{array}		domain.APIKey
+// @Failure		401	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/api-keys [get]
+func (h *Handlers) GetAPIKeys(ctx *gin.Context) {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	keys, err := AuthService.GetAPIKeys(*principal)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// @Summary		Create API key
+// @Description	Create an API key with scopes songs:read, songs:write, songs:delete or admin, the scopes must be granted to the user.
+// @Description	The key is returned only once, pass it in the X-API-Key header or as a Bearer token
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			body	body		domain.APIKeyByUser	true	"Key name and scopes"
+// @Success		201		{object}	domain.CreatedAPIKey
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/api-keys [post]
+func (h *Handlers) CreateAPIKey(ctx *gin.Context) {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	var data domain.APIKeyByUser
+	if err := decodeBody(ctx, &data); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	key, err := AuthService.CreateAPIKey(*principal, data)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, key)
+}
+
+// @Summary		Revoke API key
+// @Description	Revoke an API key of the current user, administrators can revoke any key
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id	path		uint64	true	"Key ID"
+// @Success		200	{object}	nil
+// @Failure		400	{object}	map[string]string
+// @Failure		401	{object}	map[string]string
+// @Failure		403	{object}	map[string]string
+// @Failure		404	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/api-keys/{id} [delete]
+func (h *Handlers) RevokeAPIKey(ctx *gin.Context) {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = AuthService.RevokeAPIKey(*principal, id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary		Set user role
+// @Description	Set the role of a user: viewer (songs:read), editor (songs:read, songs:write, songs:delete) or admin (all scopes).
+// @Description	Requires the admin scope
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64				true	"User ID"
+// @Param			body	body		domain.RoleByUser	true	"New role"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/users/{id}/role [put]
+func (h *Handlers) SetUserRole(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	var data domain.RoleByUser
+	if err := decodeBody(ctx, &data); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = AuthService.SetUserRole(id, data.Role)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"net/http"
+	"song/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Get artists
+// @Description	Get artists list ordered by name
+// @Tags			artist
+// @Accept			json
+// @Produce		json
+// @Param			page	query		int		false	"Page number"
+// @Success		200		{array}		domain.Artist
+// @Failure		400		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/artists [get]
+func (h *Handlers) GetArtists(ctx *gin.Context) {
+	page, err := parsePage(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	artists, err := ArtistService.GetArtists(page)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, artists)
+}
+
+// @Summary		Get artist
+// @Description	Get an artist by ID
+// @Tags			artist
+// @Accept			json
+// @Produce		json
+// @Param			id	path		uint64	true	"Artist ID"
+// @Success		200	{object}	domain.Artist
+// @Failure		400	{object}	map[string]string
+// @Failure		404	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/artists/{id} [get]
+func (h *Handlers) GetArtist(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	artist, err := ArtistService.GetArtist(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, artist)
+}
+
+// @Summary		Create artist
+// @Description	Create a new artist
+// @Tags			artist
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			body	body		domain.Artist	true	"Artist details"
+// @Success		200		{object}	map[string]domain.Id
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		409		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/artists [post]
+func (h *Handlers) CreateArtist(ctx *gin.Context) {
+	artist, err := parseArtist(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	id, err := ArtistService.CreateArtist(*artist)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]domain.Id{"artist_id": *id})
+}
+
+// @Summary		Change artist
+// @Description	Rename an artist, the new name is applied to all of its songs
+// @Tags			artist
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64			true	"Artist ID"
+// @Param			body	body		domain.Artist	true	"Artist details"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		409		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/artists/{id} [patch]
+func (h *Handlers) ChangeArtist(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	artist, err := parseArtist(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	artist.ID = id
+	err = ArtistService.ChangeArtist(*artist, actor(ctx))
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary		Delete artist
+// @Description	Delete an artist without songs
+// @Tags			artist
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id	path		uint64	true	"Artist ID"
+// @Success		200	{object}	nil
+// @Failure		400	{object}	map[string]string
+// @Failure		401	{object}	map[string]string
+// @Failure		403	{object}	map[string]string
+// @Failure		404	{object}	map[string]string
+// @Failure		409	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/artists/{id} [delete]
+func (h *Handlers) DelArtist(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = ArtistService.DelArtist(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// parseArtist разбирает данные исполнителя из тела запроса
+func parseArtist(ctx *gin.Context) (*domain.Artist, error) {
+	var artist domain.Artist
+	if err := decodeBody(ctx, &artist); err != nil {
+		return nil, err
+	}
+
+	return &artist, nil
+}
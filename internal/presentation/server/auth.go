@@ -0,0 +1,194 @@
+package server
+
+import (
+	"net/http"
+	"song/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Register
+// @Description	Create a user account with the default role. Depending on the registration mode anyone can register,
+// @Description	only an administrator can register users or registration is disabled
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			credentials	body		domain.Credentials	true	"Username and password"
+// @Success		201			{object}	domain.User
+// @Failure		400			{object}	map[string]string
+// @Failure		401			{object}	map[string]string
+// @Failure		403			{object}	map[string]string
+// @Failure		409			{object}	map[string]string
+// @Failure		500			{object}	map[string]string
+// @Router			/auth/register [post]
+func (h *Handlers) Register(ctx *gin.Context) {
+	var credentials domain.Credentials
+	if err := decodeBody(ctx, &credentials); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	var registrar *domain.Principal
+	if value, ok := ctx.Get(PRINCIPAL_KEY); ok {
+		principal := value.(domain.Principal)
+		registrar = &principal
+	}
+
+	user, err := AuthService.Register(credentials, registrar)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, user)
+}
+
+// @Summary		Login
+// @Description	Check the password and issue an access token and a refresh token
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Param			credentials	body		domain.Credentials	true	"Username and password"
+// @Success		200			{object}	domain.TokenPair
+// @Failure		400			{object}	map[string]string
+// @Failure		401			{object}	map[string]string
+// @Failure		500			{object}	map[string]string
+// @Router			/auth/login [post]
+func (h *Handlers) Login(ctx *gin.Context) {
+	var credentials domain.Credentials
+	if err := decodeBody(ctx, &credentials); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	tokens, err := AuthService.Login(credentials)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// @Summary		Refresh tokens
+// @Description	Exchange a refresh token for a new token pair. The refresh token can be used once, reusing it revokes the session
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Param			request	body		domain.RefreshRequest	true	"Refresh token"
+// @Success		200		{object}	domain.TokenPair
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/auth/refresh [post]
+func (h *Handlers) Refresh(ctx *gin.Context) {
+	var request domain.RefreshRequest
+	if err := decodeBody(ctx, &request); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	tokens, err := AuthService.Refresh(request.RefreshToken)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// @Summary		Logout
+// @Description	Revoke the current session
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	nil
+// @Failure		400	{object}	map[string]string
+// @Failure		401	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/auth/logout [post]
+func (h *Handlers) Logout(ctx *gin.Context) {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = AuthService.Logout(*principal)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary		Logout everywhere
+// @Description	Revoke all sessions of the current user
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	nil
+// @Failure		400	{object}	map[string]string
+// @Failure		401	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/auth/logout-all [post]
+func (h *Handlers) LogoutAll(ctx *gin.Context) {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = AuthService.LogoutAll(*principal)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary		Current user
+// @Description	Get the user the access token belongs to, for OIDC tokens the user is taken from the token claims
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	domain.User
+// @Failure		401	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/auth/me [get]
+func (h *Handlers) Me(ctx *gin.Context) {
+	principal, err := currentPrincipal(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	user, err := AuthService.Me(*principal)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// currentPrincipal возвращает пользователя, прошедшего аутентификацию
+func currentPrincipal(ctx *gin.Context) (*domain.Principal, error) {
+	value, ok := ctx.Get(PRINCIPAL_KEY)
+	if !ok {
+		ctx.Header("WWW-Authenticate", domain.TokenBearer)
+		return nil, &domain.BaseError{
+			Err:  "Bearer token is missing",
+			Code: http.StatusUnauthorized,
+		}
+	}
+
+	principal := value.(domain.Principal)
+	return &principal, nil
+}
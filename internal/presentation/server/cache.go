@@ -0,0 +1,20 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Get cache statistics
+// @Description	Get hit and miss counters of every cache tier since the service start: the in-process cache and Redis
+// @Tags			cache
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{array}		domain.CacheTierStats
+// @Failure		401	{object}	map[string]string
+// @Failure		403	{object}	map[string]string
+// @Router			/cache/stats [get]
+func (h *Handlers) GetCacheStats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, SongService.CacheStats())
+}
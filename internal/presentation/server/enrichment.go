@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createSongAsync создает песню без ожидания API и отвечает 202 с задачей получения данных
+func createSongAsync(ctx *gin.Context, data domain.SongDataByUser) {
+	if EnrichmentService == nil {
+		answerError(ctx, &e.InvalidInputData{
+			Err:  "Asynchronous song creation is disabled",
+			Code: http.StatusBadRequest,
+		})
+		return
+	}
+
+	job, err := EnrichmentService.Enqueue(data, actor(ctx))
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.Header("Location", fmt.Sprintf("/jobs/%d", job.ID))
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+// @Summary		Get enrichment job
+// @Description	Get the state of a background song enrichment job: pending, succeeded or failed
+// @Tags			song
+// @Accept			json
+// @Produce		json
+// @Param			id		path		uint64	true	"Job ID"
+// @Success		200		{object}	domain.EnrichmentJob
+// @Failure		400		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/jobs/{id} [get]
+func (h *Handlers) GetJob(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	if EnrichmentService == nil {
+		answerError(ctx, &e.RowsNotFoundError{
+			Err:  "Enrichment job does not exist",
+			Code: http.StatusNotFound,
+		})
+		return
+	}
+
+	job, err := EnrichmentService.GetJob(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Export library
+// @Description	Stream the whole library or the songs matching the same filters as /lib as CSV, a JSON array or NDJSON.
+// @Description	Songs are written while they are read from the database. If the export fails midway the response is cut short
+// @Tags			library
+// @Produce		text/csv
+// @Produce		json
+// @Produce		application/x-ndjson
+// @Param			format		query		string	false	"File format: csv (default), json or ndjson"
+// @Param			song		query		string	false	"Song name"
+// @Param			group		query		string	false	"Group name"
+// @Param			artistId	query		int		false	"Artist ID"
+// @Param			albumId		query		int		false	"Album ID"
+// @Param			tag			query		string	false	"Comma-separated tag names"
+// @Param			tagMode		query		string	false	"Tag match mode: any (default) or all"
+// @Param			genre		query		string	false	"Comma-separated genre names"
+// @Param			genreMode	query		string	false	"Genre match mode: any (default) or all"
+// @Param			releaseDate		query		string	false	"Release date in format dd.mm.yyyy"
+// @Param			releaseDateFrom	query		string	false	"Release date range start in format dd.mm.yyyy, inclusive"
+// @Param			releaseDateTo	query		string	false	"Release date range end in format dd.mm.yyyy, inclusive"
+// @Param			text		query		string	false	"Song text"
+// @Param			link		query		string	false	"Link"
+// @Param			sort		query		string	false	"Comma-separated sort fields, prefix - for descending order: id, song_name, group_name, releaseDate"
+// @Param			q			query		string	false	"Full-text search query over titles, groups and lyrics"
+// @Param			lang		query		string	false	"Full-text search language: ru (default) or en"
+// @Param			fields		query		string	false	"Comma-separated song fields to export. By default all fields except enrichment_status and sources"
+// @Success		200			{string}	string
+// @Failure		400			{object}	map[string]string
+// @Failure		429			{object}	map[string]string
+// @Failure		500			{object}	map[string]string
+// @Router			/lib/export [get]
+func (h *Handlers) ExportLib(ctx *gin.Context) {
+	filter, format, err := parseExport(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	w := &exportWriter{ctx: ctx, format: format}
+	count, err := SongService.ExportLib(ctx.Request.Context(), *filter, format, w)
+	if err != nil {
+		if !w.started {
+			answerError(ctx, err)
+			return
+		}
+		logger.Logger.Error(fmt.Sprintf("Library export was interrupted after %d songs: %v", count, err))
+		return
+	}
+
+	logger.Logger.Debug(fmt.Sprintf("Library export finished, %d songs", count))
+}
+
+// ParseExportFilter разбирает фильтр и формат экспорта библиотеки из параметров запроса,
+// параметры те же, что у /lib, и format: csv (по умолчанию), json или ndjson
+func ParseExportFilter(query url.Values) (*domain.LibFilter, string, error) {
+	return parseExport(&gin.Context{Request: &http.Request{URL: &url.URL{RawQuery: query.Encode()}}})
+}
+
+// parseExport разбирает фильтр и формат экспорта библиотеки
+func parseExport(ctx *gin.Context) (*domain.LibFilter, string, error) {
+	format := ctx.Request.URL.Query().Get("format")
+	if format == "" {
+		format = domain.FormatCSV
+	}
+	if domain.SongFileContentType(format) == "" {
+		return nil, "", &e.InvalidInputData{
+			Err:  fmt.Sprintf("Invalid format, supported values - %s, %s, %s", domain.FormatCSV, domain.FormatJSON, domain.FormatNDJSON),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	filter, err := parseLibFilter(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if ctx.Request.URL.Query().Get("fields") == "" {
+		filter.Fields = domain.DefaultExportFields
+	}
+
+	return filter, format, nil
+}
+
+// exportWriter записывает файл экспорта в ответ, заголовки отправляются с первыми данными,
+// чтобы до них можно было ответить ошибкой
+type exportWriter struct {
+	ctx     *gin.Context
+	format  string
+	started bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.ctx.Header("Content-Type", domain.SongFileContentType(w.format))
+		w.ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=library.%s", w.format))
+		w.ctx.Status(http.StatusOK)
+	}
+
+	return w.ctx.Writer.Write(p)
+}
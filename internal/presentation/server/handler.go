@@ -8,15 +8,27 @@ import (
 	e "song/internal/presentation/customError"
 	"song/internal/presentation/logger"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	TIME_FORMAT = "02.01.2006"
+	TIME_FORMAT     = "02.01.2006"
+	ACTOR_KEY       = "actor"
+	PRINCIPAL_KEY   = "principal"
+	ANONYMOUS_ACTOR = "anonymous"
 )
 
+// libResponse - страница библиотеки с проекцией полей песен
+type libResponse struct {
+	Items      []map[string]interface{} `json:"items"`                 // Песни только с полями из параметра fields
+	NextCursor string                   `json:"next_cursor,omitempty"` // Курсор следующей страницы
+	PrevCursor string                   `json:"prev_cursor,omitempty"` // Курсор предыдущей страницы
+	Total      *uint64                  `json:"total,omitempty"`       // Общее количество песен по фильтру
+}
+
 // Handlers определяет хендлеры для обработки HTTP-запросов
 type Handlers struct{}
 
@@ -26,58 +38,92 @@ func NewHandlers() *Handlers {
 }
 
 // @Summary		Get library
-// @Description	Get songs library
+// @Description	Get songs library. Every song contains only the fields from the fields parameter,
+// @Description	by default id, name, group, artistId, releaseDate, link and enrichment_status, plus rank and snippet with q.
+// @Description	Without page the response is an envelope with the songs in items and keyset cursors.
+// @Description	With page the response is a plain JSON array of the songs without the envelope, for old clients
 // @Tags			library
 // @Accept			json
 // @Produce		json
 // @Param			song		query		string	false	"Song name"
 // @Param			group		query		string	false	"Group name"
-// @Param			releaseDate	query		string	false	"Release date in format dd.mm.yyyy"
+// @Param			artistId	query		int		false	"Artist ID"
+// @Param			albumId		query		int		false	"Album ID"
+// @Param			tag			query		string	false	"Comma-separated tag names"
+// @Param			tagMode		query		string	false	"Tag match mode: any (default) or all"
+// @Param			genre		query		string	false	"Comma-separated genre names"
+// @Param			genreMode	query		string	false	"Genre match mode: any (default) or all"
+// @Param			releaseDate		query		string	false	"Release date in format dd.mm.yyyy"
+// @Param			releaseDateFrom	query		string	false	"Release date range start in format dd.mm.yyyy, inclusive"
+// @Param			releaseDateTo	query		string	false	"Release date range end in format dd.mm.yyyy, inclusive"
 // @Param			text		query		string	false	"Song text"
 // @Param			link		query		string	false	"Link"
-// @Param			page		query		int		false	"Page number"
-// @Success		200			{array}		domain.Song
+// @Param			songMatch	query		string	false	"Song name match mode: substring (default) or exact"
+// @Param			groupMatch	query		string	false	"Group name match mode: substring (default) or exact"
+// @Param			textMatch	query		string	false	"Song text match mode: substring (default) or exact"
+// @Param			linkMatch	query		string	false	"Link match mode: substring (default) or exact"
+// @Param			sort		query		string	false	"Comma-separated sort fields, prefix - for descending order: id, song_name, group_name, releaseDate"
+// @Param			q			query		string	false	"Full-text search query over titles, groups and lyrics, results are ordered by relevance"
+// @Param			lang		query		string	false	"Full-text search language: ru (default) or en"
+// @Param			cursor		query		string	false	"Cursor from next_cursor or prev_cursor"
+// @Param			limit		query		int		false	"Page size, 20 by default, 100 at most"
+// @Param			total		query		bool	false	"Count songs matching the filter"
+// @Param			fields		query		string	false	"Comma-separated song fields to return: id, name, group, artistId, releaseDate, text, link, enrichment_status, sources. Text and sources are omitted by default"
+// @Param			page		query		int		false	"Page number (deprecated, use cursor)"
+// @Success		200			{object}	libResponse	"Envelope without page, a plain array of items with page"
 // @Failure		400			{object}	map[string]string
+// @Failure		429			{object}	map[string]string
 // @Failure		500			{object}	map[string]string
 // @Router			/lib [get]
 func (h *Handlers) GetLib(ctx *gin.Context) {
-	song, err := parseSong(ctx)
+	filter, err := parseLibFilter(ctx)
 	if err != nil {
 		answerError(ctx, err)
 		return
 	}
 
-	pageStr := ctx.Request.URL.Query().Get("page")
-	var page int = 1
-	if pageStr != "" {
-		page, err = strconv.Atoi(pageStr)
-		if err != nil {
-			answerError(ctx, &e.InvalidInputData{
-				Err:  "Invalid page",
-				Code: http.StatusBadRequest,
-			})
-			return
-		}
+	pagination, err := parsePagination(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
 	}
 
-	lib, err := SongService.GetLib(*song, page)
-
+	lib, err := SongService.GetLib(*filter, *pagination)
 	if err != nil {
 		answerError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, lib)
+	items := make([]map[string]interface{}, 0, len(lib.Items))
+	for _, song := range lib.Items {
+		items = append(items, song.Project(filter.Fields))
+	}
+
+	if pagination.Page > 0 {
+		ctx.JSON(http.StatusOK, items)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, libResponse{
+		Items:      items,
+		NextCursor: lib.NextCursor,
+		PrevCursor: lib.PrevCursor,
+		Total:      lib.Total,
+	})
 }
 
 // @Summary		Get song text
-// @Description	Get the text of a song
+// @Description	Get a section of the song text with its type, index and the total number of sections.
+// @Description	With lang the section of the translation is returned, falling back to the original if there is none.
+// @Description	With view=side-by-side the original and translated sections are returned together as domain.SideBySide
 // @Tags			song
 // @Accept			json
 // @Produce		json
 // @Param			id		query		uint64	true	"Song ID"
-// @Param			page	query		int		false	"Page number"
-// @Success		200		{object}	domain.SongText
+// @Param			page	query		int		false	"Section number, starting from 1"
+// @Param			lang	query		string	false	"BCP-47 language code of the translation"
+// @Param			view	query		string	false	"single (default) or side-by-side, side-by-side requires lang"
+// @Success		200		{object}	domain.TextSection
 // @Failure		400		{object}	map[string]string
 // @Failure		500		{object}	map[string]string
 // @Router			/text [get]
@@ -102,20 +148,27 @@ func (h *Handlers) GetText(ctx *gin.Context) {
 		}
 	}
 
-	pageStr := ctx.Request.URL.Query().Get("page")
-	var page int = 1
-	if pageStr != "" {
-		page, err = strconv.Atoi(pageStr)
-		if err != nil {
-			answerError(ctx, &e.InvalidInputData{
-				Err:  "Invalid page",
-				Code: http.StatusBadRequest,
-			})
-			return
-		}
+	page, err := parsePage(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
 	}
 
-	text, err := SongService.GetText(id, page)
+	lang := ctx.Request.URL.Query().Get("lang")
+	var text interface{}
+	switch view := ctx.Request.URL.Query().Get("view"); {
+	case view == domain.TextViewSideBySide && lang != "":
+		text, err = TranslationService.GetSideBySide(id, page, lang)
+	case view != "" && view != domain.TextViewSingle:
+		err = &e.InvalidInputData{
+			Err:  "Invalid view, side-by-side requires lang",
+			Code: http.StatusBadRequest,
+		}
+	case lang != "":
+		text, err = TranslationService.GetText(id, page, lang)
+	default:
+		text, err = SongService.GetText(id, page)
+	}
 	if err != nil {
 		answerError(ctx, err)
 		return
@@ -124,14 +177,44 @@ func (h *Handlers) GetText(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, text)
 }
 
+// @Summary		Get song
+// @Description	Get a song with all its fields by ID
+// @Tags			song
+// @Accept			json
+// @Produce		json
+// @Param			id	path		uint64	true	"Song ID"
+// @Success		200	{object}	domain.Song
+// @Failure		400	{object}	map[string]string
+// @Failure		404	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/songs/{id} [get]
+func (h *Handlers) GetSong(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	song, err := SongService.GetSong(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, song)
+}
+
 // @Summary		Delete song
-// @Description	Delete a song by ID
+// @Description	Move a song to the trash by ID. It can be restored until the retention period expires
 // @Tags			song
 // @Accept			json
 // @Produce		json
-// @Param			id	query		uint64	true	"Song ID"
-// @Success		200	{object}	nil
+// @Security		BearerAuth
+// @Param			id		query		uint64	true	"Song ID"
+// @Success		200		{object}	nil
 // @Failure		400	{object}	map[string]string
+// @Failure		401	{object}	map[string]string
+// @Failure		403	{object}	map[string]string
 // @Failure		500	{object}	map[string]string
 // @Router			/song [delete]
 func (h *Handlers) DelSong(ctx *gin.Context) {
@@ -155,7 +238,7 @@ func (h *Handlers) DelSong(ctx *gin.Context) {
 		}
 	}
 
-	err = SongService.DelSong(id)
+	err = SongService.DelSong(id, actor(ctx))
 	if err != nil {
 		answerError(ctx, err)
 		return
@@ -169,10 +252,13 @@ func (h *Handlers) DelSong(ctx *gin.Context) {
 // @Tags			song
 // @Accept			json
 // @Produce		json
+// @Security		BearerAuth
 // @Param			id		query		uint64		true	"Song ID"
 // @Param			body	body		domain.Song	true	"Song details"
 // @Success		200		{object}	nil
 // @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
 // @Failure		500		{object}	map[string]string
 // @Router			/song [patch]
 func (h *Handlers) ChangeSong(ctx *gin.Context) {
@@ -211,7 +297,7 @@ func (h *Handlers) ChangeSong(ctx *gin.Context) {
 		return
 	}
 
-	if song.Date.IsZero() && song.Group == "" && song.Link == "" && song.Name == "" && song.Text == "" {
+	if song.Date.IsZero() && song.Group == "" && song.ArtistID == 0 && song.Link == "" && song.Name == "" && song.Text == "" {
 		answerError(ctx, &e.InvalidInputData{
 			Err:  "Invalid body",
 			Code: http.StatusBadRequest,
@@ -220,7 +306,7 @@ func (h *Handlers) ChangeSong(ctx *gin.Context) {
 	}
 
 	song.ID = id
-	err = SongService.ChangeSong(song)
+	err = SongService.ChangeSong(song, actor(ctx))
 	if err != nil {
 		answerError(ctx, err)
 		return
@@ -230,14 +316,25 @@ func (h *Handlers) ChangeSong(ctx *gin.Context) {
 }
 
 // @Summary		Create song
-// @Description	Create a new song
+// @Description	Create a new song with release date, text and link from the song info API. 404 - the API does not know the song, 502/504 - the API failed or timed out, 503 - requests to the API are paused after repeated failures.
+// @Description	With async=true the song is stored at once with enrichment_status pending and the API is queried by a background job, the response is 202 with the job and its URL in Location
 // @Tags			song
 // @Accept			json
 // @Produce		json
+// @Security		BearerAuth
+// @Param			async	query		bool					false	"Query the song info API in the background"
 // @Param			body	body		domain.SongDataByUser	true	"Song details"
 // @Success		200		{object}	map[string]domain.Id
+// @Success		202		{object}	domain.EnrichmentJob
 // @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		429		{object}	map[string]string
 // @Failure		500		{object}	map[string]string
+// @Failure		502		{object}	map[string]string
+// @Failure		503		{object}	map[string]string
+// @Failure		504		{object}	map[string]string
 // @Router			/song [post]
 func (h *Handlers) CreateSong(ctx *gin.Context) {
 	var data domain.SongDataByUser
@@ -255,7 +352,17 @@ func (h *Handlers) CreateSong(ctx *gin.Context) {
 		return
 	}
 
-	id, err := SongService.CreateSong(data, ApiUrl)
+	async, err := parseBool(ctx, "async")
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+	if async {
+		createSongAsync(ctx, data)
+		return
+	}
+
+	id, err := SongService.CreateSong(data, actor(ctx))
 	if err != nil {
 		answerError(ctx, err)
 		return
@@ -280,31 +387,315 @@ func parseSong(ctx *gin.Context) (*domain.Song, error) {
 
 	name := ctx.Request.URL.Query().Get("song")
 	group := ctx.Request.URL.Query().Get("group")
-	dateStr := ctx.Request.URL.Query().Get("releaseDate")
-	var date time.Time
-	if dateStr != "" {
-		date, err = time.Parse(TIME_FORMAT, dateStr)
+	artistIdStr := ctx.Request.URL.Query().Get("artistId")
+	var artistId uint64
+	if artistIdStr != "" {
+		artistId, err = strconv.ParseUint(artistIdStr, 10, 64)
 		if err != nil {
 			return nil, &e.InvalidInputData{
-				Err:  "Invalid date format, correct format - 16.07.2006",
+				Err:  "Invalid artistId",
 				Code: http.StatusBadRequest,
 			}
 		}
 	}
+	date, err := parseDate(ctx, "releaseDate")
+	if err != nil {
+		return nil, err
+	}
 
 	text := ctx.Request.URL.Query().Get("text")
 	link := ctx.Request.URL.Query().Get("link")
 
 	return &domain.Song{
-		ID:    id,
-		Name:  name,
-		Group: group,
-		Date:  date,
-		Text:  text,
-		Link:  link,
+		ID:       id,
+		Name:     name,
+		Group:    group,
+		ArtistID: artistId,
+		Date:     date,
+		Text:     text,
+		Link:     link,
 	}, nil
 }
 
+// parseLibFilter разбирает фильтр, поисковый запрос и сортировку библиотеки
+func parseLibFilter(ctx *gin.Context) (*domain.LibFilter, error) {
+	song, err := parseSong(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.LibFilter{
+		Song:       *song,
+		Query:      strings.TrimSpace(ctx.Request.URL.Query().Get("q")),
+		SearchLang: ctx.Request.URL.Query().Get("lang"),
+	}
+	if filter.SearchLang != "" && !domain.IsSearchLang(filter.SearchLang) {
+		return nil, &e.InvalidInputData{
+			Err:  fmt.Sprintf("Invalid lang, supported values - %s, %s", domain.SearchLangRu, domain.SearchLangEn),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	albumIdStr := ctx.Request.URL.Query().Get("albumId")
+	if albumIdStr != "" {
+		filter.AlbumID, err = strconv.ParseUint(albumIdStr, 10, 64)
+		if err != nil {
+			return nil, &e.InvalidInputData{
+				Err:  "Invalid albumId",
+				Code: http.StatusBadRequest,
+			}
+		}
+	}
+
+	filter.Tags, err = parseTagFilter(ctx, "tag", "tagMode")
+	if err != nil {
+		return nil, err
+	}
+	filter.Genres, err = parseTagFilter(ctx, "genre", "genreMode")
+	if err != nil {
+		return nil, err
+	}
+
+	filter.DateFrom, err = parseDate(ctx, "releaseDateFrom")
+	if err != nil {
+		return nil, err
+	}
+	filter.DateTo, err = parseDate(ctx, "releaseDateTo")
+	if err != nil {
+		return nil, err
+	}
+
+	modes := map[string]*string{
+		"songMatch":  &filter.Match.Name,
+		"groupMatch": &filter.Match.Group,
+		"textMatch":  &filter.Match.Text,
+		"linkMatch":  &filter.Match.Link,
+	}
+	for param, mode := range modes {
+		*mode = ctx.Request.URL.Query().Get(param)
+		if *mode != "" && !domain.IsMatchMode(*mode) {
+			return nil, &e.InvalidInputData{
+				Err:  fmt.Sprintf("Invalid %s, supported values - %s, %s", param, domain.MatchSubstring, domain.MatchExact),
+				Code: http.StatusBadRequest,
+			}
+		}
+	}
+
+	sortStr := ctx.Request.URL.Query().Get("sort")
+	if sortStr != "" {
+		for _, name := range strings.Split(sortStr, ",") {
+			name = strings.TrimSpace(name)
+			desc := strings.HasPrefix(name, "-")
+			field, ok := domain.SortFieldByName(strings.TrimPrefix(name, "-"))
+			if !ok {
+				return nil, &e.InvalidInputData{
+					Err:  fmt.Sprintf("Invalid sort field %q", name),
+					Code: http.StatusBadRequest,
+				}
+			}
+
+			filter.Sort = append(filter.Sort, domain.SortField{Field: field, Desc: desc})
+		}
+	}
+
+	filter.Fields = domain.DefaultLibFields
+	fieldsStr := ctx.Request.URL.Query().Get("fields")
+	if fieldsStr != "" {
+		filter.Fields = nil
+		seen := make(map[string]bool)
+		for _, field := range strings.Split(fieldsStr, ",") {
+			field = strings.TrimSpace(field)
+			if !domain.IsSongField(field) {
+				return nil, &e.InvalidInputData{
+					Err:  fmt.Sprintf("Invalid field %q", field),
+					Code: http.StatusBadRequest,
+				}
+			}
+			if !seen[field] {
+				seen[field] = true
+				filter.Fields = append(filter.Fields, field)
+			}
+		}
+	}
+
+	return &filter, nil
+}
+
+// parseTagFilter разбирает список названий через запятую и режим any или all
+func parseTagFilter(ctx *gin.Context, param, modeParam string) (domain.TagFilter, error) {
+	var filter domain.TagFilter
+	for _, name := range strings.Split(ctx.Request.URL.Query().Get(param), ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			filter.Names = append(filter.Names, name)
+		}
+	}
+
+	switch ctx.Request.URL.Query().Get(modeParam) {
+	case "", "any":
+	case "all":
+		filter.All = true
+	default:
+		return filter, &e.InvalidInputData{
+			Err:  fmt.Sprintf("Invalid %s, supported values - any, all", modeParam),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return filter, nil
+}
+
+// parseDate разбирает дату из параметра запроса, пустой параметр дает нулевую дату
+func parseDate(ctx *gin.Context, param string) (time.Time, error) {
+	dateStr := ctx.Request.URL.Query().Get(param)
+	if dateStr == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(TIME_FORMAT, dateStr)
+	if err != nil {
+		return time.Time{}, &e.InvalidInputData{
+			Err:  "Invalid date format, correct format - 16.07.2006",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return date, nil
+}
+
+// parsePagination разбирает параметры пагинации библиотеки
+func parsePagination(ctx *gin.Context) (*domain.Pagination, error) {
+	pagination := domain.Pagination{
+		Cursor: ctx.Request.URL.Query().Get("cursor"),
+		Limit:  domain.DefaultLibLimit,
+	}
+
+	var err error
+	pageStr := ctx.Request.URL.Query().Get("page")
+	if pageStr != "" {
+		pagination.Page, err = strconv.Atoi(pageStr)
+		if err != nil || pagination.Page <= 0 {
+			return nil, &e.InvalidInputData{
+				Err:  "Invalid page",
+				Code: http.StatusBadRequest,
+			}
+		}
+		if pagination.Cursor != "" {
+			return nil, &e.InvalidInputData{
+				Err:  "page and cursor can't be used together",
+				Code: http.StatusBadRequest,
+			}
+		}
+	}
+
+	limitStr := ctx.Request.URL.Query().Get("limit")
+	if limitStr != "" {
+		pagination.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || pagination.Limit <= 0 || pagination.Limit > domain.MaxLibLimit {
+			return nil, &e.InvalidInputData{
+				Err:  fmt.Sprintf("Invalid limit, must be from 1 to %d", domain.MaxLibLimit),
+				Code: http.StatusBadRequest,
+			}
+		}
+	}
+
+	totalStr := ctx.Request.URL.Query().Get("total")
+	if totalStr != "" {
+		pagination.Total, err = strconv.ParseBool(totalStr)
+		if err != nil {
+			return nil, &e.InvalidInputData{
+				Err:  "Invalid total",
+				Code: http.StatusBadRequest,
+			}
+		}
+	}
+
+	return &pagination, nil
+}
+
+// parsePage разбирает номер страницы, по умолчанию первая
+func parsePage(ctx *gin.Context) (int, error) {
+	pageStr := ctx.Request.URL.Query().Get("page")
+	if pageStr == "" {
+		return 1, nil
+	}
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page <= 0 {
+		return 0, &e.InvalidInputData{
+			Err:  "Invalid page",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return page, nil
+}
+
+// parseBool разбирает логический параметр param из строки запроса, по умолчанию false
+func parseBool(ctx *gin.Context, param string) (bool, error) {
+	value := ctx.Request.URL.Query().Get(param)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &e.InvalidInputData{
+			Err:  fmt.Sprintf("Invalid %s", param),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return b, nil
+}
+
+// parsePathId разбирает идентификатор из пути запроса
+func parsePathId(ctx *gin.Context) (uint64, error) {
+	return parsePathParam(ctx, "id")
+}
+
+// parsePathParam разбирает числовой параметр name из пути запроса
+func parsePathParam(ctx *gin.Context, name string) (uint64, error) {
+	id, err := strconv.ParseUint(ctx.Param(name), 10, 64)
+	if err != nil {
+		return 0, &e.InvalidInputData{
+			Err:  fmt.Sprintf("Invalid %s", name),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return id, nil
+}
+
+// actor возвращает автора изменения для истории ревизий: аутентифицированного пользователя
+// из контекста запроса, иначе ANONYMOUS_ACTOR. Заголовки запроса не учитываются,
+// чтобы автора ревизии нельзя было подделать
+func actor(ctx *gin.Context) string {
+	if name := ctx.GetString(ACTOR_KEY); name != "" {
+		return name
+	}
+
+	return ANONYMOUS_ACTOR
+}
+
+// decodeBody разбирает JSON из тела запроса в v
+func decodeBody(ctx *gin.Context, v interface{}) error {
+	err := json.NewDecoder(ctx.Request.Body).Decode(v)
+	defer func() {
+		if err := ctx.Request.Body.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+	if err != nil {
+		return &e.InvalidInputData{
+			Err:  "Invalid body",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
 // answerError обрабатывает ошибки и возвращает соответствующий HTTP-статус
 func answerError(ctx *gin.Context, err error) {
 	baseErr := err.(*domain.BaseError)
@@ -316,6 +707,19 @@ func answerError(ctx *gin.Context, err error) {
 	case http.StatusBadRequest:
 		logger.Logger.Debug("Invalid data from user")
 		ctx.JSON(http.StatusBadRequest, map[string]string{"errors": fmt.Sprintf("%s: %s", STATUS_BAD_REQUEST, baseErr.Error())})
+	case http.StatusUnauthorized:
+		ctx.JSON(http.StatusUnauthorized, map[string]string{"errors": fmt.Sprintf("%s: %s", STATUS_UNAUTHORIZED, baseErr.Error())})
+	case http.StatusForbidden:
+		ctx.JSON(http.StatusForbidden, map[string]string{"errors": fmt.Sprintf("%s: %s", STATUS_FORBIDDEN, baseErr.Error())})
+	case http.StatusNotFound:
+		ctx.JSON(http.StatusNotFound, map[string]string{"errors": fmt.Sprintf("%s: %s", STATUS_NOT_FOUND, baseErr.Error())})
+	case http.StatusConflict:
+		ctx.JSON(http.StatusConflict, map[string]string{"errors": fmt.Sprintf("%s: %s", STATUS_CONFLICT, baseErr.Error())})
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		logger.Logger.Warn(baseErr.Error())
+		ctx.JSON(baseErr.Code, map[string]string{"errors": fmt.Sprintf("%s: %s", STATUS_UPSTREAM, baseErr.Error())})
+	case http.StatusTooManyRequests:
+		ctx.JSON(http.StatusTooManyRequests, map[string]string{"errors": fmt.Sprintf("%s: %s", STATUS_TOO_MANY_REQUESTS, baseErr.Error())})
 	}
 
 	ctx.Abort()
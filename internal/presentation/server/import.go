@@ -0,0 +1,84 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MAX_IMPORT_BODY - наибольший размер файла импорта в байтах
+const MAX_IMPORT_BODY = 32 << 20
+
+// MAX_IMPORT_ROWS - наибольшее число песен в импорте через HTTP, чтобы запрос успевал завершиться.
+// Файлы большего размера импортируются командой import
+const MAX_IMPORT_ROWS = 1000
+
+// @Summary		Import songs
+// @Description	Create songs from a CSV file with a header row (group, song or name and optional releaseDate, text, link, album), a JSON array or NDJSON.
+// @Description	Missing song data is requested from the metadata providers concurrently, data from the file takes precedence.
+// @Description	Songs repeated in the file or already in the library are skipped. The report lists the result of every row.
+// @Description	At most 1000 songs are accepted, larger files are imported with the import command.
+// @Description	If the request is canceled, no more songs are created and the remaining rows fail
+// @Tags			song
+// @Accept			text/csv
+// @Accept			json
+// @Accept			application/x-ndjson
+// @Produce		json
+// @Security		BearerAuth
+// @Param			format	query		string	false	"File format: csv, json or ndjson, by default taken from Content-Type"
+// @Param			dry_run	query		bool	false	"Only validate the file and report what would be created"
+// @Success		200		{object}	domain.ImportReport
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		413		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/songs/import [post]
+func (h *Handlers) ImportSongs(ctx *gin.Context) {
+	dryRun, err := parseBool(ctx, "dry_run")
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	format := ctx.Query("format")
+	if format == "" {
+		format = domain.SongFileFormat(ctx.ContentType())
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, MAX_IMPORT_BODY))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+				"errors": fmt.Sprintf("Import file is larger than %d bytes", MAX_IMPORT_BODY),
+			})
+			return
+		}
+		answerError(ctx, &e.InvalidInputData{
+			Err:  "Invalid body",
+			Code: http.StatusBadRequest,
+		})
+		return
+	}
+
+	rows, err := domain.DecodeSongs(bytes.NewReader(body), format, MAX_IMPORT_ROWS)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	report, err := ImportService.Import(ctx.Request.Context(), rows, dryRun, actor(ctx))
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
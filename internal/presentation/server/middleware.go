@@ -1,8 +1,14 @@
 package server
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"song/internal/domain"
 	"song/internal/presentation/logger"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -22,3 +28,252 @@ func LoggerMiddleware() gin.HandlerFunc {
 			time.Since(start)))
 	}
 }
+
+// AuthMiddleware возвращает middleware, который проверяет токен доступа или API-ключ.
+// Изменяющие запросы и административные маршруты требуют токен всегда, чтение - если включена защита чтения.
+// Пользователь из токена сохраняется в контексте и становится автором изменений
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := publicPaths[c.FullPath()]; ok {
+			// Токен не обязателен, но по нему администратор регистрирует пользователей
+			if token, found := bearerToken(c); found {
+				principal, err := authenticate(c, token)
+				if err == nil {
+					c.Set(PRINCIPAL_KEY, *principal)
+					c.Set(ACTOR_KEY, principal.Username)
+				} else if tooManyRequests(err) {
+					answerError(c, err)
+					return
+				}
+			}
+			c.Next()
+			return
+		}
+
+		token, found := bearerToken(c)
+		if !found {
+			if isReadMethod(c.Request.Method) && !AuthService.ProtectReads() &&
+				requiredScope(c.Request.Method, c.FullPath()) != domain.ScopeAdmin {
+				c.Next()
+				return
+			}
+			c.Header("WWW-Authenticate", domain.TokenBearer)
+			answerError(c, &domain.BaseError{
+				Err:  "Bearer token is missing",
+				Code: http.StatusUnauthorized,
+			})
+			return
+		}
+
+		principal, err := authenticate(c, token)
+		if err != nil {
+			if !tooManyRequests(err) {
+				c.Header("WWW-Authenticate", fmt.Sprintf("%s error=\"invalid_token\"", domain.TokenBearer))
+			}
+			answerError(c, err)
+			return
+		}
+
+		c.Set(PRINCIPAL_KEY, *principal)
+		c.Set(ACTOR_KEY, principal.Username)
+		c.Next()
+	}
+}
+
+// authenticate проверяет токен или API-ключ клиента. Неудачные попытки учитываются
+// в ограничении IP-адреса клиента, после его превышения токены с этого адреса
+// не проверяются до истечения паузы, чтобы перебор ключей не обращался к базе данных
+func authenticate(c *gin.Context, token string) (*domain.Principal, error) {
+	if RateLimitService == nil {
+		return AuthService.Authenticate(token)
+	}
+
+	identity := ipClient(c)
+	if retryAfter := RateLimitService.AuthBlocked(identity); retryAfter > 0 {
+		return nil, authFailuresExceeded(c, retryAfter)
+	}
+
+	principal, err := AuthService.Authenticate(token)
+	var baseErr *domain.BaseError
+	if err == nil || !errors.As(err, &baseErr) || baseErr.Code != http.StatusUnauthorized {
+		return principal, err
+	}
+
+	result, limitErr := RateLimitService.AuthFailure(identity, c.Request.Method, c.FullPath())
+	if limitErr != nil {
+		logger.Logger.Error(limitErr.Error())
+		return nil, err
+	}
+	if result != nil && !result.Allowed {
+		return nil, authFailuresExceeded(c, result.RetryAfter)
+	}
+
+	return nil, err
+}
+
+// authFailuresExceeded возвращает ошибку 429 для клиента, превысившего число неудачных аутентификаций
+func authFailuresExceeded(c *gin.Context, retryAfter time.Duration) error {
+	c.Header("Retry-After", strconv.FormatInt(ceilSeconds(retryAfter), 10))
+	return &domain.BaseError{
+		Err:  "too many failed authentication attempts",
+		Code: http.StatusTooManyRequests,
+	}
+}
+
+// tooManyRequests проверяет, что запрос отклонен ограничением частоты
+func tooManyRequests(err error) bool {
+	var baseErr *domain.BaseError
+	return errors.As(err, &baseErr) && baseErr.Code == http.StatusTooManyRequests
+}
+
+// publicPaths - маршруты, доступные без токена
+var publicPaths = map[string]struct{}{
+	"/auth/register": {},
+	"/auth/login":    {},
+	"/auth/refresh":  {},
+}
+
+// isReadMethod проверяет, что метод запроса не изменяет данные
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// bearerToken получает токен из заголовка Authorization или API-ключ из X-API-Key
+func bearerToken(c *gin.Context) (string, bool) {
+	if key := strings.TrimSpace(c.GetHeader("X-API-Key")); key != "" {
+		return key, true
+	}
+
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, domain.TokenBearer) || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+// ScopeMiddleware возвращает middleware, который проверяет разрешения пользователя:
+// чтение требует songs:read, удаление - songs:delete, остальные изменения - songs:write.
+// Анонимные запросы пропускаются, их ограничивает AuthMiddleware
+func ScopeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := requiredScope(c.Request.Method, c.FullPath())
+		value, ok := c.Get(PRINCIPAL_KEY)
+		if scope == "" || !ok {
+			c.Next()
+			return
+		}
+
+		if !value.(domain.Principal).HasScope(scope) {
+			answerError(c, &domain.BaseError{
+				Err:  fmt.Sprintf("scope %s is required", scope),
+				Code: http.StatusForbidden,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// routeScopes - разрешения маршрутов, отличающиеся от разрешений их метода,
+// пустое разрешение - достаточно аутентификации
+var routeScopes = map[string]string{
+	"POST /auth/register":   "",
+	"POST /auth/login":      "",
+	"POST /auth/refresh":    "",
+	"POST /auth/logout":     "",
+	"POST /auth/logout-all": "",
+	"GET /auth/me":          "",
+	"GET /api-keys":         "",
+	"POST /api-keys":        "",
+	"DELETE /api-keys/:id":  "",
+	"PUT /users/:id/role":   domain.ScopeAdmin,
+	"GET /cache/stats":      domain.ScopeAdmin,
+}
+
+// requiredScope возвращает разрешение, необходимое для маршрута
+func requiredScope(method, path string) string {
+	if path == "" {
+		return ""
+	}
+	if scope, ok := routeScopes[method+" "+path]; ok {
+		return scope
+	}
+
+	switch {
+	case isReadMethod(method):
+		return domain.ScopeSongsRead
+	case method == http.MethodDelete:
+		return domain.ScopeSongsDelete
+	default:
+		return domain.ScopeSongsWrite
+	}
+}
+
+// RateLimitMiddleware возвращает middleware, который ограничивает частоту запросов клиента:
+// пользователя, API-ключа или, для анонимных запросов, IP-адреса. Ответ содержит заголовки
+// RateLimit-*, отклоненный запрос - 429 с Retry-After
+func RateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if RateLimitService == nil || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		kind, identity := rateLimitClient(c)
+		result, err := RateLimitService.Allow(kind, identity, c.Request.Method, c.FullPath())
+		if err != nil {
+			// Без счетчиков запрос пропускается, ограничение не должно ломать сервис
+			logger.Logger.Error(err.Error())
+			c.Next()
+			return
+		}
+		if result == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+		c.Header("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+		c.Header("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.Reset), 10))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.FormatInt(ceilSeconds(result.RetryAfter), 10))
+			answerError(c, &domain.BaseError{
+				Err:  fmt.Sprintf("limit of %d requests is exceeded", result.Limit),
+				Code: http.StatusTooManyRequests,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitClient возвращает вид и идентификатор клиента для ограничения запросов
+func rateLimitClient(c *gin.Context) (string, string) {
+	value, ok := c.Get(PRINCIPAL_KEY)
+	if !ok {
+		return domain.RateLimitIP, ipClient(c)
+	}
+
+	principal := value.(domain.Principal)
+	switch {
+	case principal.APIKeyID != 0:
+		return domain.RateLimitKey, fmt.Sprintf("%s:%d", domain.RateLimitKey, principal.APIKeyID)
+	case principal.UserID != 0:
+		return domain.RateLimitUser, fmt.Sprintf("%s:%d", domain.RateLimitUser, principal.UserID)
+	default:
+		return domain.RateLimitUser, fmt.Sprintf("%s:oidc:%s", domain.RateLimitUser, principal.Username)
+	}
+}
+
+// ipClient возвращает идентификатор клиента по IP-адресу
+func ipClient(c *gin.Context) string {
+	return domain.RateLimitIP + ":" + c.ClientIP()
+}
+
+// ceilSeconds округляет длительность вверх до целых секунд, но не меньше 1
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Max(1, math.Ceil(d.Seconds())))
+}
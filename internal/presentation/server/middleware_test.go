@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"song/internal/presentation/realization"
+	"song/internal/services"
+	"song/test/mock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	_ = logger.NewLogger()
+}
+
+// serve выполняет запрос к серверу и возвращает ответ
+func serve(s *Server, method, target, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	s.srv.ServeHTTP(w, req)
+
+	return w
+}
+
+// Тест для регистрации - новый пользователь получает роль viewer и не может удалять песни
+func TestRegister_DefaultRoleCannotDelete(t *testing.T) {
+	authRepo := new(mock.MockAuthRepo)
+	AuthService = services.NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, domain.AuthConfig{
+		Secret:     []byte("test secret"),
+		AccessTTL:  time.Minute,
+		RefreshTTL: time.Hour,
+	})
+	RateLimitService = nil
+	s, _ := NewServer(nil)
+
+	var created domain.User
+	authRepo.On("CreateUser", testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		created = args.Get(0).(domain.User)
+		created.ID = 7
+	}).Return(&created, nil)
+	var session domain.Session
+	authRepo.On("CreateSession", testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		session = args.Get(0).(domain.Session)
+		session.Role = created.Role
+	}).Return(nil)
+	authRepo.On("GetSession", testifyMock.Anything).Return(&session, nil)
+
+	w := serve(s, http.MethodPost, "/auth/register", `{"username":"mallory","password":"password1"}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, domain.RoleViewer, created.Role)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password1"), bcrypt.MinCost)
+	authRepo.On("GetUserByName", "mallory").Return(&domain.User{ID: 7, Username: "mallory", Role: created.Role, PasswordHash: string(hash)}, nil)
+	w = serve(s, http.MethodPost, "/auth/login", `{"username":"mallory","password":"password1"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var tokens domain.TokenPair
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+
+	w = serve(s, http.MethodDelete, "/song?id=1", "", http.Header{"Authorization": {"Bearer " + tokens.AccessToken}})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// Тест для ограничения запросов - подмена X-Forwarded-For не меняет адрес анонимного клиента
+func TestRateLimit_SpoofedForwardedFor(t *testing.T) {
+	rules, err := domain.ParseRateLimitRules("ip:*=1/1m")
+	assert.Nil(t, err)
+	tests := []struct {
+		name    string
+		proxies []string
+		second  int
+	}{
+		{name: "Прокси не доверяются", proxies: nil, second: http.StatusTooManyRequests},
+		{name: "Доверенный прокси", proxies: []string{"192.0.2.1"}, second: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AuthService = services.NewAuthService(new(mock.MockAuthRepo), new(mock.MockAPIKeyRepo), nil, domain.AuthConfig{})
+			RateLimitService = services.NewRateLimitService(realization.NewMemoryRateLimiter(), realization.NewMemoryRateLimiter(), rules)
+			defer func() { RateLimitService = nil }()
+			s, err := NewServer(tt.proxies)
+			assert.Nil(t, err)
+
+			request := func(forwardedFor string) int {
+				req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+				req.RemoteAddr = "192.0.2.1:40000"
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				w := httptest.NewRecorder()
+				s.srv.ServeHTTP(w, req)
+				return w.Code
+			}
+
+			assert.Equal(t, http.StatusUnauthorized, request("203.0.113.1"))
+			assert.Equal(t, tt.second, request("203.0.113.2"))
+		})
+	}
+}
+
+// Тест для функции actor - автор ревизии берется только из аутентифицированного пользователя
+func TestActor_IgnoresHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPatch, "/song", nil)
+	ctx.Request.Header.Set("X-Actor", "admin")
+
+	assert.Equal(t, ANONYMOUS_ACTOR, actor(ctx))
+
+	ctx.Set(ACTOR_KEY, "alice")
+	assert.Equal(t, "alice", actor(ctx))
+}
+
+// Тест для ограничения запросов - после превышения ограничения адреса неудачными
+// аутентификациями ключи с этого адреса не проверяются
+func TestRateLimit_AuthFailures(t *testing.T) {
+	rules, err := domain.ParseRateLimitRules("ip:*=2/1m")
+	assert.Nil(t, err)
+	apiKeyRepo := new(mock.MockAPIKeyRepo)
+	apiKeyRepo.On("UseAPIKey", testifyMock.Anything).Return((*domain.Principal)(nil), &domain.BaseError{Code: http.StatusNotFound})
+	AuthService = services.NewAuthService(new(mock.MockAuthRepo), apiKeyRepo, nil, domain.AuthConfig{})
+	RateLimitService = services.NewRateLimitService(realization.NewMemoryRateLimiter(), realization.NewMemoryRateLimiter(), rules)
+	defer func() { RateLimitService = nil }()
+	s, _ := NewServer(nil)
+
+	codes := make([]int, 0, 4)
+	for i := 0; i < 4; i++ {
+		w := serve(s, http.MethodGet, "/auth/me", "", http.Header{"X-Api-Key": {domain.APIKeyPrefix + "guess"}})
+		codes = append(codes, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusTooManyRequests}, codes)
+	apiKeyRepo.AssertNumberOfCalls(t, "UseAPIKey", 3)
+}
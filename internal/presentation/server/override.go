@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+	"song/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Get metadata overrides
+// @Description	Get manual corrections of song metadata. They take precedence over the catalogue and the song info API when new songs are created
+// @Tags			metadata
+// @Accept			json
+// @Produce		json
+// @Param			page	query		int		false	"Page number"
+// @Success		200		{array}		domain.MetadataOverride
+// @Failure		400		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/metadata/overrides [get]
+func (h *Handlers) GetOverrides(ctx *gin.Context) {
+	page, err := parsePage(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	overrides, err := OverrideService.GetOverrides(page)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, overrides)
+}
+
+// @Summary		Set metadata override
+// @Description	Create or replace the manual correction of a song found by group and song name, case-insensitive. Empty fields are taken from other providers
+// @Tags			metadata
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			body	body		domain.MetadataOverride	true	"Override details"
+// @Success		200		{object}	domain.MetadataOverride
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/metadata/overrides [put]
+func (h *Handlers) SetOverride(ctx *gin.Context) {
+	var override domain.MetadataOverride
+	if err := decodeBody(ctx, &override); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	result, err := OverrideService.SetOverride(override)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// @Summary		Delete metadata override
+// @Description	Delete a manual correction of song metadata. Songs that already got its data are not changed
+// @Tags			metadata
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64	true	"Override ID"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/metadata/overrides/{id} [delete]
+func (h *Handlers) DelOverride(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = OverrideService.DelOverride(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
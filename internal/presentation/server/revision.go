@@ -0,0 +1,171 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	e "song/internal/presentation/customError"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Get song revisions
+// @Description	Get the change history of a song, oldest first. Snapshots are omitted
+// @Tags			revision
+// @Accept			json
+// @Produce		json
+// @Param			id	path		uint64	true	"Song ID"
+// @Success		200	{array}		domain.Revision
+// @Failure		400	{object}	map[string]string
+// @Failure		404	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/songs/{id}/revisions [get]
+func (h *Handlers) GetRevisions(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	revisions, err := RevisionService.GetRevisions(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revisions)
+}
+
+// @Summary		Get song revision
+// @Description	Get a song revision with the full song snapshot
+// @Tags			revision
+// @Accept			json
+// @Produce		json
+// @Param			id	path		uint64	true	"Song ID"
+// @Param			rev	path		uint64	true	"Revision ID"
+// @Success		200	{object}	domain.Revision
+// @Failure		400	{object}	map[string]string
+// @Failure		404	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/songs/{id}/revisions/{rev} [get]
+func (h *Handlers) GetRevision(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	rev, err := parsePathParam(ctx, "rev")
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	revision, err := RevisionService.GetRevision(id, rev)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revision)
+}
+
+// @Summary		Diff song revisions
+// @Description	Get field-level differences between two song revisions, the text is compared line by line
+// @Tags			revision
+// @Accept			json
+// @Produce		json
+// @Param			id		path		uint64	true	"Song ID"
+// @Param			from	query		uint64	true	"Source revision ID"
+// @Param			to		query		uint64	true	"Target revision ID"
+// @Success		200		{object}	domain.RevisionDiff
+// @Failure		400		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/songs/{id}/diff [get]
+func (h *Handlers) DiffRevisions(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	from, err := parseRevisionQuery(ctx, "from")
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	to, err := parseRevisionQuery(ctx, "to")
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	diff, err := RevisionService.DiffRevisions(id, from, to)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, diff)
+}
+
+// @Summary		Revert song
+// @Description	Restore the song to the state of a revision. The revert is recorded as a new revision
+// @Tags			revision
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64	true	"Song ID"
+// @Param			rev		path		uint64	true	"Revision ID"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		409		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/songs/{id}/revisions/{rev}/revert [post]
+func (h *Handlers) RevertSong(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	rev, err := parsePathParam(ctx, "rev")
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = RevisionService.RevertSong(id, rev, actor(ctx))
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// parseRevisionQuery разбирает обязательный идентификатор ревизии из параметра запроса
+func parseRevisionQuery(ctx *gin.Context, param string) (uint64, error) {
+	value := ctx.Request.URL.Query().Get(param)
+	if value == "" {
+		return 0, &e.InvalidInputData{
+			Err:  param + " is a required parameter",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, &e.InvalidInputData{
+			Err:  "Invalid " + param,
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return id, nil
+}
@@ -1,7 +1,7 @@
 package server
 
 import (
-	"net/url"
+	"fmt"
 	"song/internal/presentation/logger"
 	"song/internal/presentation/postgres"
 	"song/internal/services"
@@ -11,46 +11,156 @@ import (
 
 // Переменные для доступа к сервисам
 var (
-	SongService *services.SongService
-	ApiUrl      *url.URL
+	SongService        *services.SongService
+	ArtistService      *services.ArtistService
+	AlbumService       *services.AlbumService
+	TagService         *services.TagService
+	RevisionService    *services.RevisionService
+	TrashService       *services.TrashService
+	TimingService      *services.TimingService
+	TranslationService *services.TranslationService
+	AuthService        *services.AuthService
+	RateLimitService   *services.RateLimitService
+	EnrichmentService  *services.EnrichmentService
+	OverrideService    *services.OverrideService
+	ImportService      *services.ImportService
 )
 
 // Константы http ответов
 const (
-	STATUS_UNAUTHORIZED    = "Authorization required"
-	STATUS_INTERNAL_SERVER = "Sorry, something went wrong, we are already solving the problem"
-	STATUS_BAD_REQUEST     = "Invalid data"
+	STATUS_UNAUTHORIZED      = "Authorization required"
+	STATUS_FORBIDDEN         = "Access denied"
+	STATUS_INTERNAL_SERVER   = "Sorry, something went wrong, we are already solving the problem"
+	STATUS_BAD_REQUEST       = "Invalid data"
+	STATUS_NOT_FOUND         = "Not found"
+	STATUS_CONFLICT          = "Conflict"
+	STATUS_TOO_MANY_REQUESTS = "Too many requests"
+	STATUS_UPSTREAM          = "Song info API error"
 )
 
+// Services - сервисы, с которыми работают хендлеры
+type Services struct {
+	Song        *services.SongService
+	Artist      *services.ArtistService
+	Album       *services.AlbumService
+	Tag         *services.TagService
+	Revision    *services.RevisionService
+	Trash       *services.TrashService
+	Timing      *services.TimingService
+	Translation *services.TranslationService
+	Auth        *services.AuthService
+	RateLimit   *services.RateLimitService  // nil - частота запросов не ограничивается
+	Enrichment  *services.EnrichmentService // nil - песни создаются только с ожиданием API
+	Override    *services.OverrideService
+	Import      *services.ImportService
+}
+
 // Server определяет сервер с сервисами
 type Server struct {
 	srv *gin.Engine
 }
 
 // NewServer создает новый экземпляр Server
-func NewServer() *Server {
+// trustedProxies - адреса и подсети прокси, которым разрешено передавать адрес клиента
+// в X-Forwarded-For, nil - адресом клиента считается адрес соединения
+func NewServer(trustedProxies []string) (*Server, error) {
 	gin.SetMode(gin.ReleaseMode)
 	srv := gin.New()
+	err := srv.SetTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies - %v", err)
+	}
 
 	h := NewHandlers()
 
 	srv.Use(LoggerMiddleware())
+	srv.Use(AuthMiddleware())
+	srv.Use(RateLimitMiddleware())
+	srv.Use(ScopeMiddleware())
+
+	srv.POST("/auth/register", h.Register)
+	srv.POST("/auth/login", h.Login)
+	srv.POST("/auth/refresh", h.Refresh)
+	srv.POST("/auth/logout", h.Logout)
+	srv.POST("/auth/logout-all", h.LogoutAll)
+	srv.GET("/auth/me", h.Me)
+	srv.GET("/api-keys", h.GetAPIKeys)
+	srv.POST("/api-keys", h.CreateAPIKey)
+	srv.DELETE("/api-keys/:id", h.RevokeAPIKey)
+	srv.PUT("/users/:id/role", h.SetUserRole)
+
 	srv.GET("/lib", h.GetLib)
+	srv.GET("/lib/export", h.ExportLib)
 	srv.GET("/text", h.GetText)
 	srv.DELETE("/song", h.DelSong)
 	srv.PATCH("/song", h.ChangeSong)
 	srv.POST("/song", h.CreateSong)
+	srv.GET("/jobs/:id", h.GetJob)
+	srv.POST("/songs/import", h.ImportSongs)
+	srv.GET("/songs/:id", h.GetSong)
+
+	srv.GET("/metadata/overrides", h.GetOverrides)
+	srv.PUT("/metadata/overrides", h.SetOverride)
+	srv.DELETE("/metadata/overrides/:id", h.DelOverride)
+
+	srv.GET("/artists", h.GetArtists)
+	srv.POST("/artists", h.CreateArtist)
+	srv.GET("/artists/:id", h.GetArtist)
+	srv.PATCH("/artists/:id", h.ChangeArtist)
+	srv.DELETE("/artists/:id", h.DelArtist)
+
+	srv.GET("/albums", h.GetAlbums)
+	srv.POST("/albums", h.CreateAlbum)
+	srv.GET("/albums/:id", h.GetAlbum)
+	srv.PATCH("/albums/:id", h.ChangeAlbum)
+	srv.DELETE("/albums/:id", h.DelAlbum)
+	srv.PUT("/albums/:id/tracks", h.SetAlbumTracks)
+
+	srv.GET("/tags", h.GetTags)
+	srv.GET("/genres", h.GetGenres)
+	srv.POST("/tags/attach", h.AttachTags)
+	srv.POST("/tags/detach", h.DetachTags)
+
+	srv.GET("/songs/:id/revisions", h.GetRevisions)
+	srv.GET("/songs/:id/revisions/:rev", h.GetRevision)
+	srv.POST("/songs/:id/revisions/:rev/revert", h.RevertSong)
+	srv.GET("/songs/:id/diff", h.DiffRevisions)
+	srv.GET("/songs/:id/lrc", h.ExportLRC)
+	srv.PUT("/songs/:id/lrc", h.ImportLRC)
+	srv.GET("/songs/:id/lrc/at", h.LineAt)
+	srv.GET("/songs/:id/lrc/stream", h.StreamLines)
+	srv.GET("/songs/:id/translations", h.GetTranslationLangs)
+	srv.GET("/songs/:id/translations/:lang", h.GetTranslation)
+	srv.PUT("/songs/:id/translations/:lang", h.SetTranslation)
+	srv.DELETE("/songs/:id/translations/:lang", h.DelTranslation)
+
+	srv.GET("/cache/stats", h.GetCacheStats)
+
+	srv.GET("/trash", h.GetTrash)
+	srv.POST("/trash/:id/restore", h.RestoreSong)
+	srv.DELETE("/trash/:id", h.PurgeSong)
 
 	logger.Logger.Info("Server has been created")
 	return &Server{
 		srv: srv,
-	}
+	}, nil
 }
 
 // Start запускает сервер
-func (s *Server) Start(songService *services.SongService, api *url.URL, port string) error {
-	SongService = songService
-	ApiUrl = api
+func (s *Server) Start(svc Services, port string) error {
+	SongService = svc.Song
+	ArtistService = svc.Artist
+	AlbumService = svc.Album
+	TagService = svc.Tag
+	RevisionService = svc.Revision
+	TrashService = svc.Trash
+	TimingService = svc.Timing
+	TranslationService = svc.Translation
+	AuthService = svc.Auth
+	RateLimitService = svc.RateLimit
+	EnrichmentService = svc.Enrichment
+	OverrideService = svc.Override
+	ImportService = svc.Import
 
 	logger.Logger.Debug("Starting server")
 	err := s.srv.Run(":" + port)
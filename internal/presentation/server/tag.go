@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net/http"
+	"song/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Get tags
+// @Description	Get tags with song counts
+// @Tags			tag
+// @Accept			json
+// @Produce		json
+// @Success		200	{array}		domain.Tag
+// @Failure		500	{object}	map[string]string
+// @Router			/tags [get]
+func (h *Handlers) GetTags(ctx *gin.Context) {
+	getTags(ctx, domain.TagKindTag)
+}
+
+// @Summary		Get genres
+// @Description	Get genres with song counts
+// @Tags			tag
+// @Accept			json
+// @Produce		json
+// @Success		200	{array}		domain.Tag
+// @Failure		500	{object}	map[string]string
+// @Router			/genres [get]
+func (h *Handlers) GetGenres(ctx *gin.Context) {
+	getTags(ctx, domain.TagKindGenre)
+}
+
+// @Summary		Attach tags
+// @Description	Attach tags and genres to songs in bulk, missing tags and genres are created
+// @Tags			tag
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			body	body		domain.TagBinding	true	"Songs with tags and genres"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/tags/attach [post]
+func (h *Handlers) AttachTags(ctx *gin.Context) {
+	var binding domain.TagBinding
+	if err := decodeBody(ctx, &binding); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err := TagService.AttachTags(binding)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary		Detach tags
+// @Description	Detach tags and genres from songs in bulk
+// @Tags			tag
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			body	body		domain.TagBinding	true	"Songs with tags and genres"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/tags/detach [post]
+func (h *Handlers) DetachTags(ctx *gin.Context) {
+	var binding domain.TagBinding
+	if err := decodeBody(ctx, &binding); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err := TagService.DetachTags(binding)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// getTags отвечает списком жанров или меток с количеством песен
+func getTags(ctx *gin.Context, kind string) {
+	tags, err := TagService.GetTags(kind)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tags)
+}
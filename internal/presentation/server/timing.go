@@ -0,0 +1,210 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"song/internal/domain"
+	e "song/internal/presentation/customError"
+	"song/internal/presentation/logger"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLrcSize - наибольший размер загружаемого LRC в байтах
+const maxLrcSize = 1 << 20
+
+// @Summary		Import LRC
+// @Description	Replace the time-synced lyrics of a song with lines from an LRC file. Enhanced word tags and the offset tag are supported
+// @Tags			timing
+// @Accept			plain
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64	true	"Song ID"
+// @Param			body	body		string	true	"LRC file"
+// @Success		200		{object}	map[string]int
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/songs/{id}/lrc [put]
+func (h *Handlers) ImportLRC(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxLrcSize+1))
+	defer func() {
+		if err := ctx.Request.Body.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+	if err != nil || len(data) > maxLrcSize {
+		answerError(ctx, &e.InvalidInputData{
+			Err:  "Invalid body",
+			Code: http.StatusBadRequest,
+		})
+		return
+	}
+
+	lines, err := TimingService.ImportLRC(id, string(data))
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]int{"lines": lines})
+}
+
+// @Summary		Export LRC
+// @Description	Get the time-synced lyrics of a song in LRC format
+// @Tags			timing
+// @Accept			json
+// @Produce		plain
+// @Param			id	path		uint64	true	"Song ID"
+// @Success		200	{string}	string
+// @Failure		400	{object}	map[string]string
+// @Failure		404	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/songs/{id}/lrc [get]
+func (h *Handlers) ExportLRC(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	lrc, err := TimingService.ExportLRC(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(lrc))
+}
+
+// @Summary		Get line at position
+// @Description	Get the line active at a playback position and the lines that follow it
+// @Tags			timing
+// @Accept			json
+// @Produce		json
+// @Param			id		path		uint64	true	"Song ID"
+// @Param			t		query		int		true	"Playback position in milliseconds"
+// @Param			next	query		int		false	"Number of following lines, 3 by default, 50 at most"
+// @Success		200		{object}	domain.LinePosition
+// @Failure		400		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/songs/{id}/lrc/at [get]
+func (h *Handlers) LineAt(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	timeMs, err := parseMs(ctx, "t", true)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	next := domain.DefaultNextLines
+	if nextStr := ctx.Request.URL.Query().Get("next"); nextStr != "" {
+		next, err = strconv.Atoi(nextStr)
+		if err != nil {
+			answerError(ctx, &e.InvalidInputData{
+				Err:  "Invalid next",
+				Code: http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	position, err := TimingService.LineAt(id, timeMs, next)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, position)
+}
+
+// @Summary		Stream lines
+// @Description	Server-sent events stream pushing "line" events in real time from a start offset. The line active at the offset is sent at once, the stream ends with an "end" event
+// @Tags			timing
+// @Accept			json
+// @Produce		text/event-stream
+// @Param			id		path		uint64	true	"Song ID"
+// @Param			from	query		int		false	"Start offset in milliseconds"
+// @Success		200		{object}	domain.TimedLine
+// @Failure		400		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/songs/{id}/lrc/stream [get]
+func (h *Handlers) StreamLines(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	from, err := parseMs(ctx, "from", false)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	lyrics, err := TimingService.GetTiming(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	err = TimingService.Play(ctx.Request.Context(), lyrics.Lines, from, func(line domain.TimedLine) error {
+		ctx.SSEvent("line", line)
+		ctx.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		logger.Logger.Debug(fmt.Sprintf("Lines stream for song %d was interrupted: %v", id, err))
+		return
+	}
+
+	ctx.SSEvent("end", map[string]int64{"songId": int64(id)})
+	ctx.Writer.Flush()
+}
+
+// parseMs разбирает неотрицательную позицию в миллисекундах из параметра запроса
+func parseMs(ctx *gin.Context, param string, required bool) (int64, error) {
+	value := ctx.Request.URL.Query().Get(param)
+	if value == "" {
+		if required {
+			return 0, &e.InvalidInputData{
+				Err:  param + " is a required parameter",
+				Code: http.StatusBadRequest,
+			}
+		}
+		return 0, nil
+	}
+
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms < 0 {
+		return 0, &e.InvalidInputData{
+			Err:  "Invalid " + param,
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return ms, nil
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"net/http"
+	"song/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Get translation languages
+// @Description	Get BCP-47 codes of the languages the song text is translated to
+// @Tags			translation
+// @Accept			json
+// @Produce		json
+// @Param			id	path		uint64	true	"Song ID"
+// @Success		200	{array}		string
+// @Failure		400	{object}	map[string]string
+// @Failure		500	{object}	map[string]string
+// @Router			/songs/{id}/translations [get]
+func (h *Handlers) GetTranslationLangs(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	langs, err := TranslationService.GetLangs(id)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, langs)
+}
+
+// @Summary		Get translation
+// @Description	Get the translation of the song text to a language with its sections
+// @Tags			translation
+// @Accept			json
+// @Produce		json
+// @Param			id		path		uint64	true	"Song ID"
+// @Param			lang	path		string	true	"BCP-47 language code"
+// @Success		200		{object}	domain.Translation
+// @Failure		400		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/songs/{id}/translations/{lang} [get]
+func (h *Handlers) GetTranslation(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	translation, err := TranslationService.GetTranslation(id, ctx.Param("lang"))
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, translation)
+}
+
+// @Summary		Set translation
+// @Description	Create or replace the translation of the song text. The translation must have as many sections as the original, section types are taken from the original
+// @Tags			translation
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64						true	"Song ID"
+// @Param			lang	path		string						true	"BCP-47 language code"
+// @Param			body	body		domain.TranslationByUser	true	"Translation text"
+// @Success		200		{object}	domain.Translation
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/songs/{id}/translations/{lang} [put]
+func (h *Handlers) SetTranslation(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	var data domain.TranslationByUser
+	if err := decodeBody(ctx, &data); err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	translation, err := TranslationService.SetTranslation(id, ctx.Param("lang"), data.Text)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, translation)
+}
+
+// @Summary		Delete translation
+// @Description	Delete the translation of the song text to a language
+// @Tags			translation
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64	true	"Song ID"
+// @Param			lang	path		string	true	"BCP-47 language code"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/songs/{id}/translations/{lang} [delete]
+func (h *Handlers) DelTranslation(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = TranslationService.DelTranslation(id, ctx.Param("lang"))
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Get trash
+// @Description	Get deleted songs waiting in the trash, most recently deleted first
+// @Tags			trash
+// @Accept			json
+// @Produce		json
+// @Param			page	query		int		false	"Page number"
+// @Success		200		{array}		domain.TrashedSong
+// @Failure		400		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/trash [get]
+func (h *Handlers) GetTrash(ctx *gin.Context) {
+	page, err := parsePage(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	songs, err := TrashService.GetTrash(page)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, songs)
+}
+
+// @Summary		Restore song
+// @Description	Move a song from the trash back to the library
+// @Tags			trash
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64	true	"Song ID"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/trash/{id}/restore [post]
+func (h *Handlers) RestoreSong(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = TrashService.RestoreSong(id, actor(ctx))
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary		Purge song
+// @Description	Permanently delete a song from the trash. The revision history is kept
+// @Tags			trash
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		uint64	true	"Song ID"
+// @Success		200		{object}	nil
+// @Failure		400		{object}	map[string]string
+// @Failure		401		{object}	map[string]string
+// @Failure		403		{object}	map[string]string
+// @Failure		404		{object}	map[string]string
+// @Failure		500		{object}	map[string]string
+// @Router			/trash/{id} [delete]
+func (h *Handlers) PurgeSong(ctx *gin.Context) {
+	id, err := parsePathId(ctx)
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	err = TrashService.PurgeSong(id, actor(ctx))
+	if err != nil {
+		answerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"strings"
+)
+
+// AlbumService - сервис для работы с альбомами
+type AlbumService struct {
+	cacheDb *SongCache
+	album   interfaces.AlbumRepo
+}
+
+// NewAlbumService создает новый объект AlbumService
+func NewAlbumService(cache *SongCache, album interfaces.AlbumRepo) *AlbumService {
+	return &AlbumService{
+		cacheDb: cache,
+		album:   album,
+	}
+}
+
+// GetAlbums получает список альбомов
+// artistID - идентификатор исполнителя, 0 - все альбомы
+// page - номер страницы для пагинации
+func (s *AlbumService) GetAlbums(artistID domain.Id, page domain.Page) (*[]domain.Album, error) {
+	return s.album.GetAlbums(artistID, page)
+}
+
+// GetAlbum получает альбом с композициями
+// id - идентификатор альбома
+func (s *AlbumService) GetAlbum(id domain.Id) (*domain.Album, error) {
+	return s.album.GetAlbum(id)
+}
+
+// CreateAlbum создает новый альбом
+// album - объект нового альбома, композиции нумеруются в порядке списка
+func (s *AlbumService) CreateAlbum(album domain.Album) (*domain.Id, error) {
+	album.Title = strings.TrimSpace(album.Title)
+	if album.Title == "" || album.ArtistID == 0 {
+		return nil, &domain.InputDataError{
+			Err:  "Album title and artistId are required",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	songs := make([]domain.Id, 0, len(album.Tracks))
+	for _, track := range album.Tracks {
+		songs = append(songs, track.SongID)
+	}
+	if err := checkTracks(songs); err != nil {
+		return nil, err
+	}
+
+	id, err := s.album.CreateAlbum(album)
+	if err != nil {
+		return nil, err
+	}
+
+	return id, s.cacheDb.InvalidateLibrary()
+}
+
+// ChangeAlbum изменяет данные альбома, композиции меняются через SetTracks
+// album - объект альбома с новыми данными
+func (s *AlbumService) ChangeAlbum(album domain.Album) error {
+	album.Title = strings.TrimSpace(album.Title)
+	if album.Title == "" && album.ArtistID == 0 && album.Date.IsZero() {
+		return &domain.InputDataError{
+			Err:  "Nothing to change",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	err := s.album.ChangeAlbum(album)
+	if err != nil {
+		return err
+	}
+
+	return s.cacheDb.InvalidateLibrary()
+}
+
+// DelAlbum удаляет альбом
+// id - идентификатор альбома
+func (s *AlbumService) DelAlbum(id domain.Id) error {
+	err := s.album.DelAlbum(id)
+	if err != nil {
+		return err
+	}
+
+	return s.cacheDb.InvalidateLibrary()
+}
+
+// SetTracks задает новый порядок композиций альбома
+// id - идентификатор альбома
+// songs - идентификаторы песен в порядке следования
+func (s *AlbumService) SetTracks(id domain.Id, songs []domain.Id) error {
+	if err := checkTracks(songs); err != nil {
+		return err
+	}
+
+	err := s.album.SetTracks(id, songs)
+	if err != nil {
+		return err
+	}
+
+	return s.cacheDb.InvalidateLibrary()
+}
+
+// checkTracks проверяет, что песни в списке композиций не повторяются
+func checkTracks(songs []domain.Id) error {
+	seen := make(map[domain.Id]bool, len(songs))
+	for _, id := range songs {
+		if id == 0 || seen[id] {
+			return &domain.InputDataError{
+				Err:  fmt.Sprintf("Invalid or duplicate song %d in track list", id),
+				Code: http.StatusBadRequest,
+			}
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для метода SetTracks с повторяющимися песнями
+func TestAlbumService_SetTracks_Duplicate(t *testing.T) {
+	mockAlbumRepo := new(mock.MockAlbumRepo)
+	albumService := NewAlbumService(newSongCache(new(mock.MockCacheRepo)), mockAlbumRepo)
+
+	err := albumService.SetTracks(1, []domain.Id{3, 1, 3})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.InputDataError).Code)
+	mockAlbumRepo.AssertNotCalled(t, "SetTracks")
+}
+
+// Тест для метода ChangeAlbum - после изменения альбома начинается новое поколение библиотеки
+func TestAlbumService_ChangeAlbum_InvalidatesLibrary(t *testing.T) {
+	mockAlbumRepo := new(mock.MockAlbumRepo)
+	cache := new(mock.MockCacheRepo)
+	albumService := NewAlbumService(newSongCache(cache), mockAlbumRepo)
+	album := domain.Album{ID: 2, Title: "Origin of Symmetry"}
+
+	mockAlbumRepo.On("ChangeAlbum", album).Return(nil)
+	cache.On("Incr", libraryGenerationKey).Return(int64(3), nil)
+
+	err := albumService.ChangeAlbum(album)
+
+	assert.Nil(t, err)
+	mockAlbumRepo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+// Тест для метода CreateSong с альбомом из API
+func TestSongService_CreateSong_Album(t *testing.T) {
+	songRepo := new(mock.MockSongRepo)
+	albumRepo := new(mock.MockAlbumRepo)
+	enrichment := new(mock.MockEnrichmentClient)
+	cache := new(mock.MockCacheRepo)
+	songService := NewSongService(newSongCache(cache), songRepo, albumRepo, enrichment)
+	date := time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)
+	id := domain.Id(5)
+	data := domain.SongDataByUser{Group: "Muse", Name: "Hole"}
+	album := domain.AlbumByApi{
+		Title: "Black Holes and Revelations",
+		Date:  time.Date(2006, 7, 3, 0, 0, 0, 0, time.UTC),
+		Track: 3,
+	}
+
+	enrichment.On("SongInfo", data).Return(&domain.SongDataByApi{Date: date, Text: "text", Link: "link", Album: &album}, nil)
+	songRepo.On("CreateSong", domain.Song{Name: "Hole", Group: "Muse", Date: date, Text: "text", Link: "link"}, "anonymous").Return(&id, nil)
+	albumRepo.On("AttachSong", id, album).Return(nil)
+	cache.On("Incr", libraryGenerationKey).Return(int64(1), nil)
+
+	result, err := songService.CreateSong(data, "anonymous")
+
+	assert.Nil(t, err)
+	assert.Equal(t, id, *result)
+	songRepo.AssertExpectations(t)
+	albumRepo.AssertExpectations(t)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ограничения API-ключей
+const (
+	maxAPIKeyNameLength = 64
+	apiKeySecretBytes   = 32
+	apiKeyPrefixLength  = len(domain.APIKeyPrefix) + 8
+)
+
+// CreateAPIKey создает API-ключ пользователя. Ключ не может получить разрешений,
+// которых нет у создателя, а ключи без admin не могут выпускать новые ключи
+// principal - пользователь, выполняющий запрос
+// data - название и разрешения ключа
+func (s *AuthService) CreateAPIKey(principal domain.Principal, data domain.APIKeyByUser) (*domain.CreatedAPIKey, error) {
+	name := strings.TrimSpace(data.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLength {
+		return nil, &domain.InputDataError{
+			Err:  fmt.Sprintf("key name must be 1 to %d characters long", maxAPIKeyNameLength),
+			Code: http.StatusBadRequest,
+		}
+	}
+	if err := domain.ValidateScopes(data.Scopes); err != nil {
+		return nil, err
+	}
+	if principal.UserID == 0 {
+		return nil, forbidden("API keys can only be created by local users")
+	}
+	if principal.APIKeyID != 0 && !principal.HasScope(domain.ScopeAdmin) {
+		return nil, forbidden("API keys can only be created after a password login")
+	}
+	for _, scope := range data.Scopes {
+		if !principal.HasScope(scope) {
+			return nil, forbidden(fmt.Sprintf("scope %q is not granted to the user", scope))
+		}
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, &domain.BaseError{
+			Err:  fmt.Sprintf("key generating error - %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	key := domain.APIKeyPrefix + hex.EncodeToString(secret)
+
+	created, err := s.apiKeys.CreateAPIKey(domain.APIKey{
+		UserID: principal.UserID,
+		Name:   name,
+		Prefix: key[:apiKeyPrefixLength],
+		Scopes: data.Scopes,
+	}, hashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedAPIKey{APIKey: *created, Key: key}, nil
+}
+
+// GetAPIKeys получает действующие ключи пользователя
+// principal - пользователь, выполняющий запрос
+func (s *AuthService) GetAPIKeys(principal domain.Principal) (*[]domain.APIKey, error) {
+	return s.apiKeys.GetAPIKeys(principal.UserID)
+}
+
+// RevokeAPIKey отзывает ключ пользователя, администратор может отозвать любой ключ
+// principal - пользователь, выполняющий запрос
+// id - идентификатор ключа
+func (s *AuthService) RevokeAPIKey(principal domain.Principal, id domain.Id) error {
+	admin := principal.HasScope(domain.ScopeAdmin)
+	if principal.UserID == 0 && !admin {
+		return forbidden("API keys can only be revoked by local users")
+	}
+
+	return s.apiKeys.RevokeAPIKey(id, principal.UserID, admin)
+}
+
+// authenticateAPIKey проверяет API-ключ, разрешения ключа ограничиваются ролью владельца
+func (s *AuthService) authenticateAPIKey(key string) (*domain.Principal, error) {
+	principal, err := s.apiKeys.UseAPIKey(hashAPIKey(key))
+	if err != nil {
+		var baseErr *domain.BaseError
+		if errors.As(err, &baseErr) && baseErr.Code == http.StatusNotFound {
+			return nil, unauthorized("Invalid or revoked API key")
+		}
+		return nil, err
+	}
+	principal.Scopes = domain.EffectiveScopes(principal.Role, principal.Scopes)
+
+	return principal, nil
+}
+
+// hashAPIKey возвращает SHA-256 ключа в hex. Ключи случайны и длинны,
+// поэтому медленное хэширование, как для паролей, не требуется
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
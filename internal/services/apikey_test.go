@@ -0,0 +1,114 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/test/mock"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+)
+
+// Тест для метода CreateAPIKey: ключ показывается один раз, хранится только хэш
+func TestAuthService_CreateAPIKey(t *testing.T) {
+	apiKeyRepo := new(mock.MockAPIKeyRepo)
+	authService := NewAuthService(new(mock.MockAuthRepo), apiKeyRepo, nil, testAuthConfig)
+	editor := domain.Principal{UserID: 7, Role: domain.RoleEditor, Scopes: domain.RoleScopes[domain.RoleEditor], SessionID: "s"}
+
+	var hash string
+	apiKeyRepo.On("CreateAPIKey", testifyMock.MatchedBy(func(key domain.APIKey) bool {
+		return key.UserID == 7 && key.Name == "ingest" && strings.HasPrefix(key.Prefix, domain.APIKeyPrefix)
+	}), testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		hash = args.String(1)
+	}).Return(&domain.APIKey{ID: 3, UserID: 7, Name: "ingest"}, nil)
+
+	key, err := authService.CreateAPIKey(editor, domain.APIKeyByUser{Name: "ingest", Scopes: []string{domain.ScopeSongsWrite}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Id(3), key.ID)
+	assert.True(t, strings.HasPrefix(key.Key, domain.APIKeyPrefix))
+	assert.Equal(t, hashAPIKey(key.Key), hash)
+	assert.NotContains(t, hash, key.Key[len(domain.APIKeyPrefix):])
+}
+
+// Тест для метода CreateAPIKey с разрешением, которого нет у пользователя
+func TestAuthService_CreateAPIKey_Escalation(t *testing.T) {
+	apiKeyRepo := new(mock.MockAPIKeyRepo)
+	authService := NewAuthService(new(mock.MockAuthRepo), apiKeyRepo, nil, testAuthConfig)
+	viewer := domain.Principal{UserID: 7, Role: domain.RoleViewer, Scopes: domain.RoleScopes[domain.RoleViewer], SessionID: "s"}
+
+	_, err := authService.CreateAPIKey(viewer, domain.APIKeyByUser{Name: "ingest", Scopes: []string{domain.ScopeSongsWrite}})
+
+	assert.Equal(t, http.StatusForbidden, err.(*domain.BaseError).Code)
+	apiKeyRepo.AssertNotCalled(t, "CreateAPIKey", testifyMock.Anything, testifyMock.Anything)
+}
+
+// Тест для метода CreateAPIKey с неизвестным разрешением
+func TestAuthService_CreateAPIKey_UnknownScope(t *testing.T) {
+	authService := NewAuthService(new(mock.MockAuthRepo), new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+	admin := domain.Principal{UserID: 1, Role: domain.RoleAdmin, Scopes: domain.RoleScopes[domain.RoleAdmin], SessionID: "s"}
+
+	_, err := authService.CreateAPIKey(admin, domain.APIKeyByUser{Name: "ingest", Scopes: []string{"songs:everything"}})
+
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.BaseError).Code)
+}
+
+// Тест для метода Authenticate с API-ключом, разрешения ограничены ролью владельца
+func TestAuthService_Authenticate_APIKey(t *testing.T) {
+	apiKeyRepo := new(mock.MockAPIKeyRepo)
+	authService := NewAuthService(new(mock.MockAuthRepo), apiKeyRepo, nil, testAuthConfig)
+	key := domain.APIKeyPrefix + "0123456789abcdef"
+
+	apiKeyRepo.On("UseAPIKey", hashAPIKey(key)).Return(&domain.Principal{
+		UserID:   7,
+		Username: "ingest-bot",
+		Role:     domain.RoleViewer,
+		Scopes:   []string{domain.ScopeSongsRead, domain.ScopeSongsWrite},
+		APIKeyID: 3,
+	}, nil)
+
+	principal, err := authService.Authenticate(key)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{domain.ScopeSongsRead}, principal.Scopes)
+	assert.False(t, principal.HasScope(domain.ScopeSongsWrite))
+}
+
+// Тест для метода Authenticate с отозванным API-ключом
+func TestAuthService_Authenticate_RevokedAPIKey(t *testing.T) {
+	apiKeyRepo := new(mock.MockAPIKeyRepo)
+	authService := NewAuthService(new(mock.MockAuthRepo), apiKeyRepo, nil, testAuthConfig)
+	key := domain.APIKeyPrefix + "revoked"
+
+	apiKeyRepo.On("UseAPIKey", hashAPIKey(key)).Return((*domain.Principal)(nil), &domain.BaseError{Code: http.StatusNotFound})
+
+	_, err := authService.Authenticate(key)
+
+	assert.Equal(t, http.StatusUnauthorized, err.(*domain.BaseError).Code)
+}
+
+// Тест для метода RevokeAPIKey: администратор отзывает любой ключ
+func TestAuthService_RevokeAPIKey(t *testing.T) {
+	apiKeyRepo := new(mock.MockAPIKeyRepo)
+	authService := NewAuthService(new(mock.MockAuthRepo), apiKeyRepo, nil, testAuthConfig)
+
+	apiKeyRepo.On("RevokeAPIKey", domain.Id(3), domain.Id(7), false).Return(nil)
+	apiKeyRepo.On("RevokeAPIKey", domain.Id(4), domain.Id(1), true).Return(nil)
+
+	assert.Nil(t, authService.RevokeAPIKey(domain.Principal{UserID: 7, Scopes: domain.RoleScopes[domain.RoleEditor]}, 3))
+	assert.Nil(t, authService.RevokeAPIKey(domain.Principal{UserID: 1, Scopes: domain.RoleScopes[domain.RoleAdmin]}, 4))
+	apiKeyRepo.AssertExpectations(t)
+}
+
+// Тест для метода RevokeAPIKey: пользователь OIDC без admin не может отзывать ключи
+func TestAuthService_RevokeAPIKey_OIDCViewer(t *testing.T) {
+	apiKeyRepo := new(mock.MockAPIKeyRepo)
+	authService := NewAuthService(new(mock.MockAuthRepo), apiKeyRepo, nil, testAuthConfig)
+
+	err := authService.RevokeAPIKey(domain.Principal{Username: "alice@corp", Role: domain.RoleViewer, Scopes: domain.RoleScopes[domain.RoleViewer]}, 3)
+
+	assert.Equal(t, http.StatusForbidden, err.(*domain.BaseError).Code)
+	apiKeyRepo.AssertNotCalled(t, "RevokeAPIKey", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+}
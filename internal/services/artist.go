@@ -0,0 +1,74 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"strings"
+)
+
+// ArtistService - сервис для работы с исполнителями
+type ArtistService struct {
+	cacheDb *SongCache
+	artist  interfaces.ArtistRepo
+}
+
+// NewArtistService создает новый объект ArtistService
+func NewArtistService(cache *SongCache, artist interfaces.ArtistRepo) *ArtistService {
+	return &ArtistService{
+		cacheDb: cache,
+		artist:  artist,
+	}
+}
+
+// GetArtists получает список исполнителей
+// page - номер страницы для пагинации
+func (s *ArtistService) GetArtists(page domain.Page) (*[]domain.Artist, error) {
+	return s.artist.GetArtists(page)
+}
+
+// GetArtist получает исполнителя по идентификатору
+// id - идентификатор исполнителя
+func (s *ArtistService) GetArtist(id domain.Id) (*domain.Artist, error) {
+	return s.artist.GetArtist(id)
+}
+
+// CreateArtist создает нового исполнителя
+// artist - объект нового исполнителя
+func (s *ArtistService) CreateArtist(artist domain.Artist) (*domain.Id, error) {
+	artist.Name = strings.TrimSpace(artist.Name)
+	if artist.Name == "" {
+		return nil, &domain.InputDataError{
+			Err:  "Artist name is required",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return s.artist.CreateArtist(artist)
+}
+
+// ChangeArtist переименовывает исполнителя, название обновляется во всех его песнях
+// artist - объект исполнителя с новыми данными
+// actor - автор изменения
+func (s *ArtistService) ChangeArtist(artist domain.Artist, actor string) error {
+	artist.Name = strings.TrimSpace(artist.Name)
+	if artist.Name == "" {
+		return &domain.InputDataError{
+			Err:  "Artist name is required",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	ids, err := s.artist.ChangeArtist(artist, actor)
+	if err != nil {
+		return err
+	}
+
+	return s.cacheDb.InvalidateSong(ids...)
+}
+
+// DelArtist удаляет исполнителя без песен
+// id - идентификатор исполнителя
+func (s *ArtistService) DelArtist(id domain.Id) error {
+	return s.artist.DelArtist(id)
+}
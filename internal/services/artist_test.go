@@ -0,0 +1,56 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для метода CreateArtist
+func TestArtistService_CreateArtist(t *testing.T) {
+	mockArtistRepo := new(mock.MockArtistRepo)
+	artistService := NewArtistService(newSongCache(new(mock.MockCacheRepo)), mockArtistRepo)
+	id := domain.Id(1)
+
+	mockArtistRepo.On("CreateArtist", domain.Artist{Name: "Muse"}).Return(&id, nil)
+
+	result, err := artistService.CreateArtist(domain.Artist{Name: "  Muse "})
+
+	assert.Nil(t, err)
+	assert.Equal(t, id, *result)
+	mockArtistRepo.AssertExpectations(t)
+}
+
+// Тест для метода ChangeArtist с пустым названием
+func TestArtistService_ChangeArtist_EmptyName(t *testing.T) {
+	mockArtistRepo := new(mock.MockArtistRepo)
+	artistService := NewArtistService(newSongCache(new(mock.MockCacheRepo)), mockArtistRepo)
+
+	err := artistService.ChangeArtist(domain.Artist{ID: 1, Name: " "}, "editor")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.InputDataError).Code)
+	mockArtistRepo.AssertNotCalled(t, "ChangeArtist")
+}
+
+// Тест для метода ChangeArtist - начинаются новые версии песен исполнителя
+func TestArtistService_ChangeArtist(t *testing.T) {
+	mockArtistRepo := new(mock.MockArtistRepo)
+	mockCache := new(mock.MockCacheRepo)
+	artistService := NewArtistService(newSongCache(mockCache), mockArtistRepo)
+	artist := domain.Artist{ID: 3, Name: "Muse"}
+
+	mockArtistRepo.On("ChangeArtist", artist, "editor").Return([]domain.Id{4, 9}, nil)
+	mockCache.On("Incr", songVersionKey(4)).Return(int64(2), nil)
+	mockCache.On("Incr", songVersionKey(9)).Return(int64(5), nil)
+	mockCache.On("Incr", libraryGenerationKey).Return(int64(1), nil)
+
+	err := artistService.ChangeArtist(artist, "editor")
+
+	assert.Nil(t, err)
+	mockArtistRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"song/internal/presentation/logger"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// tokenIssuer - издатель токенов
+const tokenIssuer = "song"
+
+// dummyHash - хэш для сравнения пароля несуществующего пользователя,
+// чтобы время ответа не выдавало, зарегистрировано ли имя
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// tokenClaims - содержимое токенов доступа и обновления
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Username  string `json:"name"`
+	SessionID string `json:"sid"`
+	Type      string `json:"typ"`
+}
+
+// AuthService - сервис для работы с пользователями и токенами
+type AuthService struct {
+	auth    interfaces.AuthRepo
+	apiKeys interfaces.APIKeyRepo
+	oidc    interfaces.TokenVerifier
+	config  domain.AuthConfig
+	now     func() time.Time
+}
+
+// NewAuthService создает новый объект AuthService
+// oidc - проверка токенов внешнего издателя, nil - принимаются только собственные токены
+func NewAuthService(auth interfaces.AuthRepo, apiKeys interfaces.APIKeyRepo, oidc interfaces.TokenVerifier, config domain.AuthConfig) *AuthService {
+	if config.DefaultRole == "" {
+		config.DefaultRole = domain.RoleViewer
+	}
+	if config.Registration == "" {
+		config.Registration = domain.RegistrationOpen
+	}
+
+	return &AuthService{
+		auth:    auth,
+		apiKeys: apiKeys,
+		oidc:    oidc,
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+// ProtectReads возвращает, требуется ли аутентификация для чтения
+func (s *AuthService) ProtectReads() bool {
+	return s.config.ProtectReads
+}
+
+// Register регистрирует нового пользователя с ролью по умолчанию
+// credentials - имя пользователя и пароль
+// registrar - пользователь, выполняющий запрос, nil - анонимный клиент
+func (s *AuthService) Register(credentials domain.Credentials, registrar *domain.Principal) (*domain.User, error) {
+	switch s.config.Registration {
+	case domain.RegistrationClosed:
+		return nil, &domain.BaseError{
+			Err:  "registration is disabled",
+			Code: http.StatusForbidden,
+		}
+	case domain.RegistrationAdmin:
+		if registrar == nil {
+			return nil, unauthorized("Bearer token is missing")
+		}
+		if !registrar.HasScope(domain.ScopeAdmin) {
+			return nil, &domain.BaseError{
+				Err:  fmt.Sprintf("scope %s is required", domain.ScopeAdmin),
+				Code: http.StatusForbidden,
+			}
+		}
+	}
+
+	return s.createUser(credentials, s.config.DefaultRole)
+}
+
+// BootstrapAdmin создает администратора при запуске сервиса. Если пользователь уже есть,
+// он получает роль admin, а пароль не меняется. Регистрация не назначает администраторов,
+// поэтому это единственный способ получить первого из них
+// credentials - имя пользователя и пароль
+func (s *AuthService) BootstrapAdmin(credentials domain.Credentials) (*domain.User, error) {
+	user, err := s.createUser(credentials, domain.RoleAdmin)
+	if err == nil || !conflictError(err) {
+		return user, err
+	}
+
+	// Пользователь уже создан, в том числе другим экземпляром сервиса
+	user, err = s.auth.GetUserByName(strings.TrimSpace(credentials.Username))
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != domain.RoleAdmin {
+		if err = s.auth.SetUserRole(user.ID, domain.RoleAdmin); err != nil {
+			return nil, err
+		}
+		user.Role = domain.RoleAdmin
+	}
+
+	return user, nil
+}
+
+// createUser проверяет имя и пароль и создает пользователя с ролью role
+func (s *AuthService) createUser(credentials domain.Credentials, role string) (*domain.User, error) {
+	username := strings.TrimSpace(credentials.Username)
+	if n := utf8.RuneCountInString(username); n < domain.MinUsernameLength || n > domain.MaxUsernameLength {
+		return nil, &domain.InputDataError{
+			Err:  fmt.Sprintf("username must be %d to %d characters long", domain.MinUsernameLength, domain.MaxUsernameLength),
+			Code: http.StatusBadRequest,
+		}
+	}
+	if utf8.RuneCountInString(credentials.Password) < domain.MinPasswordLength || len(credentials.Password) > domain.MaxPasswordLength {
+		return nil, &domain.InputDataError{
+			Err:  fmt.Sprintf("password must be at least %d characters and at most %d bytes long", domain.MinPasswordLength, domain.MaxPasswordLength),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(credentials.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, &domain.BaseError{
+			Err:  fmt.Sprintf("password hashing error - %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return s.auth.CreateUser(domain.User{Username: username, Role: role, PasswordHash: string(hash)})
+}
+
+// Login проверяет пароль и открывает новую сессию
+// credentials - имя пользователя и пароль
+func (s *AuthService) Login(credentials domain.Credentials) (*domain.TokenPair, error) {
+	user, err := s.auth.GetUserByName(strings.TrimSpace(credentials.Username))
+	if err != nil {
+		var baseErr *domain.BaseError
+		if !errors.As(err, &baseErr) || baseErr.Code != http.StatusNotFound {
+			return nil, err
+		}
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(credentials.Password))
+		return nil, unauthorized("Invalid username or password")
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password)) != nil {
+		return nil, unauthorized("Invalid username or password")
+	}
+
+	session := domain.Session{
+		ID:         randomID(),
+		UserID:     user.ID,
+		RefreshJTI: randomID(),
+		ExpiresAt:  s.now().Add(s.config.RefreshTTL),
+	}
+	err = s.auth.CreateSession(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user.ID, user.Username, session)
+}
+
+// Refresh выдает новые токены по токену обновления, старый токен обновления
+// перестает действовать. Повторное предъявление старого токена отзывает сессию
+// refreshToken - токен обновления
+func (s *AuthService) Refresh(refreshToken string) (*domain.TokenPair, error) {
+	claims, err := s.parseToken(refreshToken, domain.TokenRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := strconv.ParseUint(claims.Subject, 10, 64)
+	session := domain.Session{
+		ID:         claims.SessionID,
+		UserID:     userID,
+		RefreshJTI: randomID(),
+		ExpiresAt:  s.now().Add(s.config.RefreshTTL),
+	}
+
+	rotated, err := s.auth.RotateSession(session.ID, claims.ID, session.RefreshJTI, session.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Токен уже использован: его мог перехватить злоумышленник
+		logger.Logger.Warn(fmt.Sprintf("Refresh token reuse in session %s of user %d", session.ID, userID))
+		if err := s.auth.RevokeSession(session.ID); err != nil {
+			return nil, err
+		}
+		return nil, unauthorized("Refresh token is revoked")
+	}
+
+	return s.issueTokens(userID, claims.Username, session)
+}
+
+// Authenticate проверяет токен доступа и действительность его сессии, API-ключ
+// или токен внешнего OIDC-провайдера
+// accessToken - токен доступа, API-ключ или токен провайдера
+func (s *AuthService) Authenticate(accessToken string) (*domain.Principal, error) {
+	if strings.HasPrefix(accessToken, domain.APIKeyPrefix) {
+		return s.authenticateAPIKey(accessToken)
+	}
+	if s.oidc != nil && unverifiedIssuer(accessToken) == s.oidc.Issuer() {
+		return s.oidc.Verify(accessToken)
+	}
+
+	claims, err := s.parseToken(accessToken, domain.TokenAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.auth.GetSession(claims.SessionID)
+	if err != nil {
+		var baseErr *domain.BaseError
+		if errors.As(err, &baseErr) && baseErr.Code == http.StatusNotFound {
+			return nil, unauthorized("Token is revoked")
+		}
+		return nil, err
+	}
+	if session.Revoked || !session.ExpiresAt.After(s.now()) {
+		return nil, unauthorized("Token is revoked")
+	}
+
+	return &domain.Principal{
+		UserID:    session.UserID,
+		Username:  claims.Username,
+		Role:      session.Role,
+		Scopes:    domain.RoleScopes[session.Role],
+		SessionID: session.ID,
+	}, nil
+}
+
+// Me получает пользователя, выполняющего запрос. Пользователи внешнего провайдера
+// не хранятся в базе, для них данные берутся из токена
+// principal - пользователь, выполняющий запрос
+func (s *AuthService) Me(principal domain.Principal) (*domain.User, error) {
+	if principal.UserID == 0 {
+		return &domain.User{Username: principal.Username, Role: principal.Role}, nil
+	}
+
+	return s.auth.GetUser(principal.UserID)
+}
+
+// SetUserRole меняет роль пользователя, новая роль действует со следующего запроса
+// id - идентификатор пользователя
+// role - новая роль
+func (s *AuthService) SetUserRole(id domain.Id, role string) error {
+	if err := domain.ValidateRole(role); err != nil {
+		return err
+	}
+
+	return s.auth.SetUserRole(id, role)
+}
+
+// Logout отзывает сессию пользователя вместе с ее токенами
+// principal - пользователь, выполняющий запрос
+func (s *AuthService) Logout(principal domain.Principal) error {
+	if principal.SessionID == "" {
+		return &domain.InputDataError{
+			Err:  "request is not authenticated by a session",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return s.auth.RevokeSession(principal.SessionID)
+}
+
+// LogoutAll отзывает все сессии пользователя
+// principal - пользователь, выполняющий запрос
+func (s *AuthService) LogoutAll(principal domain.Principal) error {
+	if principal.UserID == 0 {
+		return &domain.InputDataError{
+			Err:  "request is not authenticated by a local user",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return s.auth.RevokeUserSessions(principal.UserID)
+}
+
+// issueTokens подписывает токены доступа и обновления сессии
+func (s *AuthService) issueTokens(userID domain.Id, username string, session domain.Session) (*domain.TokenPair, error) {
+	now := s.now()
+	access, err := s.signToken(tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.FormatUint(userID, 10),
+			ID:        randomID(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.AccessTTL)),
+		},
+		Username:  username,
+		SessionID: session.ID,
+		Type:      domain.TokenAccess,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := s.signToken(tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.FormatUint(userID, 10),
+			ID:        session.RefreshJTI,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(session.ExpiresAt),
+		},
+		Username:  username,
+		SessionID: session.ID,
+		Type:      domain.TokenRefresh,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    domain.TokenBearer,
+		ExpiresIn:    int64(s.config.AccessTTL.Seconds()),
+	}, nil
+}
+
+// signToken подписывает токен ключом HS256
+func (s *AuthService) signToken(claims tokenClaims) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.config.Secret)
+	if err != nil {
+		return "", &domain.BaseError{
+			Err:  fmt.Sprintf("token signing error - %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return token, nil
+}
+
+// parseToken проверяет подпись, срок действия и тип токена
+func (s *AuthService) parseToken(token, tokenType string) (*tokenClaims, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.config.Secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now),
+	)
+	if err != nil || claims.Type != tokenType || claims.SessionID == "" {
+		return nil, unauthorized("Invalid or expired token")
+	}
+
+	return &claims, nil
+}
+
+// unverifiedIssuer возвращает издателя токена без проверки подписи, только для выбора способа проверки
+func unverifiedIssuer(token string) string {
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(claims.Issuer, "/")
+}
+
+// unauthorized возвращает ошибку аутентификации
+func unauthorized(msg string) error {
+	return &domain.BaseError{
+		Err:  msg,
+		Code: http.StatusUnauthorized,
+	}
+}
+
+// conflictError проверяет, что пользователь с таким именем уже существует
+func conflictError(err error) bool {
+	var baseErr *domain.BaseError
+	return errors.As(err, &baseErr) && baseErr.Code == http.StatusConflict
+}
+
+// forbidden возвращает ошибку нехватки разрешений
+func forbidden(msg string) error {
+	return &domain.BaseError{
+		Err:  msg,
+		Code: http.StatusForbidden,
+	}
+}
+
+// randomID возвращает случайный идентификатор из 16 байт в hex
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
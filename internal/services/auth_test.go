@@ -0,0 +1,250 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"song/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testAuthConfig = domain.AuthConfig{
+	Secret:     []byte("test secret"),
+	AccessTTL:  15 * time.Minute,
+	RefreshTTL: 24 * time.Hour,
+}
+
+// testUser возвращает пользователя с хэшем пароля password
+func testUser(t *testing.T, password string) *domain.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.Nil(t, err)
+	return &domain.User{ID: 7, Username: "alice", PasswordHash: string(hash)}
+}
+
+// loginTestUser открывает сессию пользователя alice и возвращает токены и сессию
+func loginTestUser(t *testing.T, authRepo *mock.MockAuthRepo, authService *AuthService) (*domain.TokenPair, domain.Session) {
+	var session domain.Session
+	authRepo.On("GetUserByName", "alice").Return(testUser(t, "password1"), nil).Once()
+	authRepo.On("CreateSession", testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		session = args.Get(0).(domain.Session)
+	}).Return(nil).Once()
+
+	tokens, err := authService.Login(domain.Credentials{Username: "alice", Password: "password1"})
+	assert.Nil(t, err)
+
+	return tokens, session
+}
+
+// Тест для метода Register с хэшированием пароля
+func TestAuthService_Register(t *testing.T) {
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+	created := &domain.User{ID: 7, Username: "alice", Role: domain.RoleViewer}
+
+	authRepo.On("CreateUser", testifyMock.MatchedBy(func(user domain.User) bool {
+		return user.Username == "alice" && user.Role == domain.RoleViewer &&
+			bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("password1")) == nil
+	})).Return(created, nil)
+
+	user, err := authService.Register(domain.Credentials{Username: " alice ", Password: "password1"}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, created, user)
+	authRepo.AssertExpectations(t)
+}
+
+// Тест для метода Register с коротким паролем
+func TestAuthService_Register_ShortPassword(t *testing.T) {
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+
+	_, err := authService.Register(domain.Credentials{Username: "alice", Password: "short"}, nil)
+
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.BaseError).Code)
+	authRepo.AssertNotCalled(t, "CreateUser", testifyMock.Anything)
+}
+
+// Тест для метода Register в режимах регистрации администратором и закрытой регистрации
+func TestAuthService_Register_Mode(t *testing.T) {
+	admin := &domain.Principal{Username: "root", Role: domain.RoleAdmin, Scopes: domain.RoleScopes[domain.RoleAdmin]}
+	editor := &domain.Principal{Username: "bob", Role: domain.RoleEditor, Scopes: domain.RoleScopes[domain.RoleEditor]}
+	tests := []struct {
+		name      string
+		mode      string
+		registrar *domain.Principal
+		code      int
+	}{
+		{name: "Анонимный клиент", mode: domain.RegistrationAdmin, registrar: nil, code: http.StatusUnauthorized},
+		{name: "Не администратор", mode: domain.RegistrationAdmin, registrar: editor, code: http.StatusForbidden},
+		{name: "Регистрация отключена", mode: domain.RegistrationClosed, registrar: admin, code: http.StatusForbidden},
+		{name: "Администратор", mode: domain.RegistrationAdmin, registrar: admin, code: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authRepo := new(mock.MockAuthRepo)
+			config := testAuthConfig
+			config.Registration = tt.mode
+			authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, config)
+			authRepo.On("CreateUser", testifyMock.Anything).Return(&domain.User{ID: 7, Username: "alice"}, nil)
+
+			_, err := authService.Register(domain.Credentials{Username: "alice", Password: "password1"}, tt.registrar)
+
+			if tt.code == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			assert.Equal(t, tt.code, err.(*domain.BaseError).Code)
+			authRepo.AssertNotCalled(t, "CreateUser", testifyMock.Anything)
+		})
+	}
+}
+
+// Тест для метода BootstrapAdmin: новый пользователь создается администратором,
+// существующий получает роль admin без смены пароля
+func TestAuthService_BootstrapAdmin(t *testing.T) {
+	credentials := domain.Credentials{Username: "root", Password: "password1"}
+
+	t.Run("Новый пользователь", func(t *testing.T) {
+		authRepo := new(mock.MockAuthRepo)
+		authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+		authRepo.On("CreateUser", testifyMock.MatchedBy(func(user domain.User) bool {
+			return user.Username == "root" && user.Role == domain.RoleAdmin
+		})).Return(&domain.User{ID: 1, Username: "root", Role: domain.RoleAdmin}, nil)
+
+		user, err := authService.BootstrapAdmin(credentials)
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.RoleAdmin, user.Role)
+		authRepo.AssertNotCalled(t, "SetUserRole", testifyMock.Anything, testifyMock.Anything)
+	})
+
+	t.Run("Существующий пользователь", func(t *testing.T) {
+		authRepo := new(mock.MockAuthRepo)
+		authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+		authRepo.On("CreateUser", testifyMock.Anything).Return((*domain.User)(nil), &domain.BaseError{Code: http.StatusConflict})
+		authRepo.On("GetUserByName", "root").Return(&domain.User{ID: 3, Username: "root", Role: domain.RoleViewer}, nil)
+		authRepo.On("SetUserRole", domain.Id(3), domain.RoleAdmin).Return(nil)
+
+		user, err := authService.BootstrapAdmin(credentials)
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.RoleAdmin, user.Role)
+		authRepo.AssertExpectations(t)
+	})
+}
+
+// Тест для метода Login с неверным паролем
+func TestAuthService_Login_WrongPassword(t *testing.T) {
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+
+	authRepo.On("GetUserByName", "alice").Return(testUser(t, "password1"), nil)
+
+	_, err := authService.Login(domain.Credentials{Username: "alice", Password: "password2"})
+
+	assert.Equal(t, http.StatusUnauthorized, err.(*domain.BaseError).Code)
+	authRepo.AssertNotCalled(t, "CreateSession", testifyMock.Anything)
+}
+
+// Тест для метода Login с несуществующим пользователем
+func TestAuthService_Login_UnknownUser(t *testing.T) {
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+
+	authRepo.On("GetUserByName", "bob").Return((*domain.User)(nil), &domain.BaseError{Code: http.StatusNotFound})
+
+	_, err := authService.Login(domain.Credentials{Username: "bob", Password: "password1"})
+
+	assert.Equal(t, http.StatusUnauthorized, err.(*domain.BaseError).Code)
+}
+
+// Тест для метода Authenticate с токеном доступа после входа
+func TestAuthService_Authenticate(t *testing.T) {
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+	tokens, session := loginTestUser(t, authRepo, authService)
+	session.Role = domain.RoleViewer
+
+	authRepo.On("GetSession", session.ID).Return(&session, nil)
+
+	principal, err := authService.Authenticate(tokens.AccessToken)
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Principal{
+		UserID:    7,
+		Username:  "alice",
+		Role:      domain.RoleViewer,
+		Scopes:    []string{domain.ScopeSongsRead},
+		SessionID: session.ID,
+	}, *principal)
+	assert.Equal(t, domain.TokenBearer, tokens.TokenType)
+	assert.Equal(t, int64(900), tokens.ExpiresIn)
+}
+
+// Тест для метода Authenticate с отозванной сессией и токеном обновления
+func TestAuthService_Authenticate_Rejected(t *testing.T) {
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+	tokens, session := loginTestUser(t, authRepo, authService)
+
+	revoked := session
+	revoked.Revoked = true
+	authRepo.On("GetSession", session.ID).Return(&revoked, nil)
+
+	_, err := authService.Authenticate(tokens.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, err.(*domain.BaseError).Code)
+
+	_, err = authService.Authenticate(tokens.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, err.(*domain.BaseError).Code)
+}
+
+// Тест для метода Authenticate с истекшим токеном
+func TestAuthService_Authenticate_Expired(t *testing.T) {
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+	tokens, _ := loginTestUser(t, authRepo, authService)
+
+	authService.now = func() time.Time { return time.Now().Add(time.Hour) }
+
+	_, err := authService.Authenticate(tokens.AccessToken)
+
+	assert.Equal(t, http.StatusUnauthorized, err.(*domain.BaseError).Code)
+	authRepo.AssertNotCalled(t, "GetSession", testifyMock.Anything)
+}
+
+// Тест для метода Refresh с заменой токена обновления
+func TestAuthService_Refresh(t *testing.T) {
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+	tokens, session := loginTestUser(t, authRepo, authService)
+
+	authRepo.On("RotateSession", session.ID, session.RefreshJTI, testifyMock.Anything, testifyMock.Anything).Return(true, nil)
+
+	refreshed, err := authService.Refresh(tokens.RefreshToken)
+
+	assert.Nil(t, err)
+	assert.NotEqual(t, tokens.RefreshToken, refreshed.RefreshToken)
+	authRepo.AssertNotCalled(t, "RevokeSession", testifyMock.Anything)
+}
+
+// Тест для метода Refresh с повторно использованным токеном обновления
+func TestAuthService_Refresh_Reuse(t *testing.T) {
+	_ = logger.NewLogger()
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), nil, testAuthConfig)
+	tokens, session := loginTestUser(t, authRepo, authService)
+
+	authRepo.On("RotateSession", session.ID, session.RefreshJTI, testifyMock.Anything, testifyMock.Anything).Return(false, nil)
+	authRepo.On("RevokeSession", session.ID).Return(nil)
+
+	_, err := authService.Refresh(tokens.RefreshToken)
+
+	assert.Equal(t, http.StatusUnauthorized, err.(*domain.BaseError).Code)
+	authRepo.AssertExpectations(t)
+}
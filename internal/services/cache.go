@@ -0,0 +1,225 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"song/internal/presentation/logger"
+	"strconv"
+	"time"
+)
+
+// cacheVersion - версия формата значений в кэше. Входит во все ключи,
+// чтобы после изменения формата не читать значения, записанные прежней версией
+const cacheVersion = "v1"
+
+// libraryGenerationKey - ключ поколения библиотеки. Поколение входит в ключи страниц библиотеки
+// и увеличивается при каждом изменении, после чего прежние страницы больше не читаются
+// и удаляются Redis по истечении времени хранения
+const libraryGenerationKey = "library:" + cacheVersion + ":generation"
+
+// SongCache - кэш текстов, песен и страниц библиотеки поверх хранилища CacheRepo
+type SongCache struct {
+	cache  interfaces.CacheRepo
+	config domain.CacheConfig
+}
+
+// NewSongCache создает новый объект SongCache
+// config - время хранения значений по видам
+func NewSongCache(cache interfaces.CacheRepo, config domain.CacheConfig) *SongCache {
+	return &SongCache{
+		cache:  cache,
+		config: config,
+	}
+}
+
+// songVersionKey возвращает ключ версии песни. Версия входит в ключи текста и песни
+// и увеличивается при изменении песни, другие песни остаются в кэше
+func songVersionKey(id domain.Id) string {
+	return fmt.Sprintf("song:%s:%d:version", cacheVersion, id)
+}
+
+// lyricsKey возвращает ключ разделов текста песни в версии version
+func lyricsKey(version int64, id domain.Id) string {
+	return fmt.Sprintf("lyrics:%s:%d:%d", cacheVersion, id, version)
+}
+
+// songKey возвращает ключ песни в версии version
+func songKey(version int64, id domain.Id) string {
+	return fmt.Sprintf("song:%s:%d:%d", cacheVersion, id, version)
+}
+
+// libKey возвращает ключ страницы библиотеки в поколении generation
+func libKey(generation int64, filter domain.LibFilter, pagination domain.Pagination) (string, error) {
+	data, err := json.Marshal(struct {
+		Filter     domain.LibFilter
+		Pagination domain.Pagination
+	}{filter, pagination})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return fmt.Sprintf("lib:%s:%d:%s", cacheVersion, generation, hex.EncodeToString(hash[:16])), nil
+}
+
+// Lyrics получает разделы текста песни из кэша, при промахе - через load с записью в кэш.
+// Версия песни читается до load, поэтому текст, загруженный до изменения песни,
+// записывается в прежнюю версию и больше не читается
+// id - идентификатор песни
+func (c *SongCache) Lyrics(id domain.Id, load func() (*domain.Lyrics, error)) (*domain.Lyrics, error) {
+	if c.config.TextTTL <= 0 {
+		return load()
+	}
+
+	version, ok := c.counter(songVersionKey(id))
+	if !ok {
+		return load()
+	}
+
+	return cached(c.cache, lyricsKey(version, id), c.config.TextTTL, load)
+}
+
+// Song получает песню из кэша, при промахе - через load с записью в кэш
+// id - идентификатор песни
+func (c *SongCache) Song(id domain.Id, load func() (*domain.Song, error)) (*domain.Song, error) {
+	if c.config.SongTTL <= 0 {
+		return load()
+	}
+
+	version, ok := c.counter(songVersionKey(id))
+	if !ok {
+		return load()
+	}
+
+	return cached(c.cache, songKey(version, id), c.config.SongTTL, load)
+}
+
+// Lib получает страницу библиотеки из кэша, при промахе - через load с записью в кэш
+// filter - фильтр для песен
+// pagination - параметры пагинации
+func (c *SongCache) Lib(filter domain.LibFilter, pagination domain.Pagination, load func() (*domain.LibPage, error)) (*domain.LibPage, error) {
+	if c.config.LibTTL <= 0 {
+		return load()
+	}
+
+	generation, ok := c.generation()
+	if !ok {
+		return load()
+	}
+
+	key, err := libKey(generation, filter, pagination)
+	if err != nil {
+		return load()
+	}
+
+	return cached(c.cache, key, c.config.LibTTL, load)
+}
+
+// InvalidateSong начинает новые версии песен и поколение библиотеки: закэшированные тексты
+// и данные этих песен и страницы библиотеки больше не читаются
+// ids - идентификаторы песен
+func (c *SongCache) InvalidateSong(ids ...domain.Id) error {
+	for _, id := range ids {
+		_, err := c.cache.Incr(songVersionKey(id))
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.InvalidateLibrary()
+}
+
+// InvalidateLibrary начинает новое поколение библиотеки: закэшированные страницы библиотеки
+// больше не читаются, тексты и данные песен остаются
+func (c *SongCache) InvalidateLibrary() error {
+	generation, err := c.cache.Incr(libraryGenerationKey)
+	if err != nil {
+		return err
+	}
+
+	logger.Logger.Debug(fmt.Sprintf("Library cache generation %d has started", generation))
+	return nil
+}
+
+// Stats возвращает статистику попаданий по уровням кэша
+func (c *SongCache) Stats() []domain.CacheTierStats {
+	return c.cache.Stats()
+}
+
+// Close закрывает подключение к хранилищу кэша
+func (c *SongCache) Close() error {
+	return c.cache.Close()
+}
+
+// generation возвращает текущее поколение библиотеки, false - поколение прочитать не удалось
+func (c *SongCache) generation() (int64, bool) {
+	return c.counter(libraryGenerationKey)
+}
+
+// counter возвращает значение счетчика key, отсутствующий счетчик создается. Если значение
+// прочитать не удалось, возвращается false и кэш не используется: хранилище, ошибки которого
+// считаются промахами, иначе вернуло бы 0, и по ключам с 0 читались бы значения,
+// записанные до изменений во время прошлого сбоя
+func (c *SongCache) counter(key string) (int64, bool) {
+	value, err := c.cache.Get(key)
+	if err != nil {
+		logger.Logger.Warn(fmt.Sprintf("key: %s reading error, cache is bypassed - %v", key, err))
+		return 0, false
+	}
+
+	if value == nil {
+		counter, err := c.cache.Incr(key)
+		return counter, err == nil && counter > 0
+	}
+
+	counter, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		logger.Logger.Warn(fmt.Sprintf("key: %s has invalid value, cache is bypassed - %v", key, err))
+		return 0, false
+	}
+
+	return counter, counter > 0
+}
+
+// cached получает значение key из кэша, при промахе - через load с записью в кэш на время ttl.
+// Поврежденное значение считается промахом, ttl 0 - значение не кэшируется
+func cached[T any](cache interfaces.CacheRepo, key string, ttl time.Duration, load func() (*T, error)) (*T, error) {
+	if ttl <= 0 {
+		return load()
+	}
+
+	data, err := cache.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if data != nil {
+		var value T
+		err = json.Unmarshal(data, &value)
+		if err == nil {
+			return &value, nil
+		}
+		logger.Logger.Warn(fmt.Sprintf("key: %s has invalid value - %v", key, err))
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(value)
+	if err != nil {
+		return value, nil
+	}
+
+	err = cache.Set(key, data, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
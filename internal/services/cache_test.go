@@ -0,0 +1,151 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"song/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+)
+
+// newSongCache создает кэш песен поверх мока хранилища
+func newSongCache(cache *mock.MockCacheRepo) *SongCache {
+	_ = logger.NewLogger()
+	return NewSongCache(cache, domain.CacheConfig{
+		TextTTL: time.Hour,
+		SongTTL: time.Minute,
+		LibTTL:  time.Minute,
+	})
+}
+
+// Тест для метода Lyrics с попаданием в кэш
+func TestSongCache_Lyrics_Hit(t *testing.T) {
+	mockCache := new(mock.MockCacheRepo)
+	lyrics := domain.Lyrics{{Index: 1, Type: domain.SectionVerse, Lines: []string{"Line"}}}
+	data, _ := json.Marshal(lyrics)
+
+	mockCache.On("Get", songVersionKey(1)).Return([]byte("1"), nil)
+	mockCache.On("Get", lyricsKey(1, 1)).Return(data, nil)
+
+	result, err := newSongCache(mockCache).Lyrics(1, func() (*domain.Lyrics, error) {
+		t.Fatal("load must not be called on a cache hit")
+		return nil, nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, lyrics, *result)
+	mockCache.AssertNotCalled(t, "Set", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+}
+
+// Тест для метода Song с промахом - песня загружается и записывается в текущую версию
+func TestSongCache_Song_Miss(t *testing.T) {
+	mockCache := new(mock.MockCacheRepo)
+	song := domain.Song{ID: 3, Name: "Hysteria", Group: "Muse"}
+	data, _ := json.Marshal(song)
+
+	mockCache.On("Get", songVersionKey(3)).Return([]byte("7"), nil)
+	mockCache.On("Get", songKey(7, 3)).Return([]byte(nil), nil)
+	mockCache.On("Set", songKey(7, 3), data, time.Minute).Return(nil)
+
+	result, err := newSongCache(mockCache).Song(3, func() (*domain.Song, error) {
+		return &song, nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, song, *result)
+	mockCache.AssertExpectations(t)
+}
+
+// Тест для метода Lib с поврежденным значением в кэше
+func TestSongCache_Lib_InvalidValue(t *testing.T) {
+	mockCache := new(mock.MockCacheRepo)
+	page := domain.LibPage{Items: []domain.Song{{ID: 1}}}
+	key, _ := libKey(1, domain.LibFilter{}, domain.Pagination{Page: 1, Limit: 10})
+
+	mockCache.On("Get", libraryGenerationKey).Return([]byte("1"), nil)
+	mockCache.On("Get", key).Return([]byte("{broken"), nil)
+	mockCache.On("Set", key, testifyMock.Anything, time.Minute).Return(nil)
+
+	result, err := newSongCache(mockCache).Lib(domain.LibFilter{}, domain.Pagination{Page: 1, Limit: 10}, func() (*domain.LibPage, error) {
+		return &page, nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, page, *result)
+	mockCache.AssertExpectations(t)
+}
+
+// Тест для метода InvalidateSong - текст, загруженный до изменения песни, записывается
+// в прежнюю версию и не читается после изменения, версии других песен не меняются
+func TestSongCache_InvalidateSong(t *testing.T) {
+	mockCache := new(mock.MockCacheRepo)
+	cache := newSongCache(mockCache)
+	stale := domain.Lyrics{{Index: 1, Type: domain.SectionVerse, Lines: []string{"Old"}}}
+	fresh := domain.Lyrics{{Index: 1, Type: domain.SectionVerse, Lines: []string{"New"}}}
+	staleData, _ := json.Marshal(stale)
+	freshData, _ := json.Marshal(fresh)
+
+	mockCache.On("Get", songVersionKey(5)).Return([]byte("1"), nil).Once()
+	mockCache.On("Get", lyricsKey(1, 5)).Return([]byte(nil), nil)
+	mockCache.On("Incr", songVersionKey(5)).Return(int64(2), nil)
+	mockCache.On("Incr", libraryGenerationKey).Return(int64(4), nil)
+	mockCache.On("Set", lyricsKey(1, 5), staleData, time.Hour).Return(nil)
+	mockCache.On("Get", songVersionKey(5)).Return([]byte("2"), nil).Once()
+	mockCache.On("Get", lyricsKey(2, 5)).Return([]byte(nil), nil)
+	mockCache.On("Set", lyricsKey(2, 5), freshData, time.Hour).Return(nil)
+
+	result, err := cache.Lyrics(5, func() (*domain.Lyrics, error) {
+		// Песня изменяется, пока читатель загружает прежний текст
+		assert.Nil(t, cache.InvalidateSong(5))
+		return &stale, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, stale, *result)
+
+	result, err = cache.Lyrics(5, func() (*domain.Lyrics, error) {
+		return &fresh, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, fresh, *result)
+	mockCache.AssertExpectations(t)
+	mockCache.AssertNotCalled(t, "Incr", songVersionKey(6))
+}
+
+// Тест для метода Song, когда версию песни прочитать не удалось - кэш не используется,
+// чтобы не читать значения версии 0, записанные во время прошлого сбоя
+func TestSongCache_Song_VersionUnavailable(t *testing.T) {
+	song := domain.Song{ID: 3, Name: "Hysteria", Group: "Muse"}
+	tests := []struct {
+		name  string
+		setup func(cache *mock.MockCacheRepo)
+	}{
+		{name: "Ошибка чтения", setup: func(cache *mock.MockCacheRepo) {
+			cache.On("Get", songVersionKey(3)).Return([]byte(nil), &domain.BaseError{Code: http.StatusInternalServerError})
+		}},
+		{name: "Ошибка хранилища считается промахом", setup: func(cache *mock.MockCacheRepo) {
+			cache.On("Get", songVersionKey(3)).Return([]byte(nil), nil)
+			cache.On("Incr", songVersionKey(3)).Return(int64(0), nil)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mock.MockCacheRepo)
+			tt.setup(mockCache)
+
+			result, err := newSongCache(mockCache).Song(3, func() (*domain.Song, error) {
+				return &song, nil
+			})
+
+			assert.Nil(t, err)
+			assert.Equal(t, song, *result)
+			mockCache.AssertNotCalled(t, "Get", songKey(0, 3))
+			mockCache.AssertNotCalled(t, "Set", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"song/internal/presentation/logger"
+	"sync"
+	"time"
+)
+
+// maxBackoffShift ограничивает рост паузы между попытками
+const maxBackoffShift = 10
+
+// EnrichmentService - сервис фонового получения дополнительных данных песен
+type EnrichmentService struct {
+	cacheDb *SongCache
+	repo    interfaces.EnrichmentRepo
+	album   interfaces.AlbumRepo
+	client  interfaces.EnrichmentClient
+	config  domain.EnrichmentJobConfig
+}
+
+// NewEnrichmentService создает новый объект EnrichmentService
+func NewEnrichmentService(cache *SongCache, repo interfaces.EnrichmentRepo, album interfaces.AlbumRepo,
+	client interfaces.EnrichmentClient, config domain.EnrichmentJobConfig) *EnrichmentService {
+	return &EnrichmentService{
+		cacheDb: cache,
+		repo:    repo,
+		album:   album,
+		client:  client,
+		config:  config,
+	}
+}
+
+// Enqueue сразу создает песню с данными пользователя и ставит в очередь получение остальных данных из API
+// data - данные о песне, предоставленные пользователем
+// actor - автор изменения
+func (s *EnrichmentService) Enqueue(data domain.SongDataByUser, actor string) (*domain.EnrichmentJob, error) {
+	if data.Group == "" || data.Name == "" {
+		return nil, &domain.InputDataError{
+			Err:  "group and song are required",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	job, err := s.repo.CreateSongJob(domain.Song{Name: data.Name, Group: data.Group}, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, s.cacheDb.InvalidateLibrary()
+}
+
+// GetJob получает задачу по идентификатору
+// id - идентификатор задачи
+func (s *EnrichmentService) GetJob(id uint64) (*domain.EnrichmentJob, error) {
+	return s.repo.GetJob(id)
+}
+
+// ProcessNext выполняет одну готовую задачу, false - готовых задач нет.
+// Если API не знает песню или отклоняет данные, задача сразу завершается ошибкой,
+// остальные ошибки повторяются с растущей паузой, пока не закончатся попытки
+func (s *EnrichmentService) ProcessNext() (bool, error) {
+	job, err := s.repo.ClaimJob(s.config.Lease)
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	info, err := s.client.SongInfo(job.Data)
+	if err != nil {
+		if permanentError(err) || job.Attempts >= s.config.MaxAttempts {
+			logger.Logger.Warn(fmt.Sprintf("Enrichment of song %d failed after %d attempts: %v", job.SongID, job.Attempts, err))
+			err = s.repo.FailJob(*job, err.Error())
+			if err != nil {
+				return true, err
+			}
+			return true, s.cacheDb.InvalidateSong(job.SongID)
+		}
+
+		return true, s.repo.RetryJob(job.ID, err.Error(), time.Now().Add(s.backoff(job.Attempts)))
+	}
+
+	err = s.repo.CompleteJob(*job, *info)
+	if err != nil {
+		return true, err
+	}
+
+	if info.Album != nil && info.Album.Title != "" {
+		err = s.album.AttachSong(job.SongID, *info.Album)
+		if err != nil {
+			logger.Logger.Warn(fmt.Sprintf("Song %d was enriched without album %q: %v", job.SongID, info.Album.Title, err))
+		}
+	}
+
+	return true, s.cacheDb.InvalidateSong(job.SongID)
+}
+
+// Run запускает обработчики задач и ждет их завершения после отмены ctx
+func (s *EnrichmentService) Run(ctx context.Context) {
+	if s.config.Workers <= 0 {
+		logger.Logger.Info("Song enrichment workers are disabled")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work выполняет задачи подряд, а когда очередь пуста или недоступна - ждет PollInterval
+func (s *EnrichmentService) work(ctx context.Context) {
+	for {
+		processed, err := s.ProcessNext()
+		if err != nil {
+			logger.Logger.Error(fmt.Sprintf("Song enrichment error - %v", err))
+		}
+
+		if !processed || err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.config.PollInterval):
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// backoff возвращает паузу после попытки с номером attempt
+func (s *EnrichmentService) backoff(attempt int) time.Duration {
+	shift := attempt - 1
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+	if shift < 0 {
+		shift = 0
+	}
+
+	return s.config.RetryBackoff << shift
+}
+
+// permanentError проверяет, что повтор запроса к API не изменит результат
+func permanentError(err error) bool {
+	var baseErr *domain.BaseError
+	if !errors.As(err, &baseErr) {
+		return false
+	}
+
+	return baseErr.Code == http.StatusBadRequest || baseErr.Code == http.StatusNotFound
+}
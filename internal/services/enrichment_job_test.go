@@ -0,0 +1,146 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"song/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+)
+
+// newEnrichmentService создает сервис фонового получения данных с mock-зависимостями
+func newEnrichmentService() (*EnrichmentService, *mock.MockEnrichmentRepo, *mock.MockEnrichmentClient, *mock.MockCacheRepo, *mock.MockAlbumRepo) {
+	_ = logger.NewLogger()
+	repo := new(mock.MockEnrichmentRepo)
+	client := new(mock.MockEnrichmentClient)
+	cache := new(mock.MockCacheRepo)
+	album := new(mock.MockAlbumRepo)
+
+	return NewEnrichmentService(newSongCache(cache), repo, album, client, domain.EnrichmentJobConfig{
+		MaxAttempts:  3,
+		RetryBackoff: time.Second,
+		Lease:        time.Minute,
+	}), repo, client, cache, album
+}
+
+// Тест для метода Enqueue - песня создается только с данными пользователя
+func TestEnrichmentService_Enqueue(t *testing.T) {
+	service, repo, client, cache, _ := newEnrichmentService()
+	job := &domain.EnrichmentJob{ID: 5, SongID: 10, Status: domain.EnrichmentPending}
+
+	repo.On("CreateSongJob", domain.Song{Name: "Hysteria", Group: "Muse"}, "editor").Return(job, nil)
+	cache.On("Incr", libraryGenerationKey).Return(int64(1), nil)
+
+	result, err := service.Enqueue(domain.SongDataByUser{Group: "Muse", Name: "Hysteria"}, "editor")
+
+	assert.Nil(t, err)
+	assert.Equal(t, job, result)
+	client.AssertNotCalled(t, "SongInfo", testifyMock.Anything)
+}
+
+// Тест для метода Enqueue без названия песни
+func TestEnrichmentService_Enqueue_Invalid(t *testing.T) {
+	service, repo, _, _, _ := newEnrichmentService()
+
+	_, err := service.Enqueue(domain.SongDataByUser{Group: "Muse"}, "editor")
+
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.BaseError).Code)
+	repo.AssertNotCalled(t, "CreateSongJob", testifyMock.Anything, testifyMock.Anything)
+}
+
+// Тест для метода ProcessNext с пустой очередью
+func TestEnrichmentService_ProcessNext_Empty(t *testing.T) {
+	service, repo, _, _, _ := newEnrichmentService()
+
+	repo.On("ClaimJob", time.Minute).Return((*domain.EnrichmentJob)(nil), nil)
+
+	processed, err := service.ProcessNext()
+
+	assert.Nil(t, err)
+	assert.False(t, processed)
+}
+
+// Тест для метода ProcessNext - данные сохраняются, песня привязывается к альбому, кэш сбрасывается
+func TestEnrichmentService_ProcessNext_Success(t *testing.T) {
+	service, repo, client, cache, album := newEnrichmentService()
+	data := domain.SongDataByUser{Group: "Muse", Name: "Hysteria"}
+	job := &domain.EnrichmentJob{ID: 5, SongID: 10, Attempts: 1, Data: data}
+	info := &domain.SongDataByApi{Text: "text", Link: "link", Album: &domain.AlbumByApi{Title: "Absolution"}}
+
+	repo.On("ClaimJob", time.Minute).Return(job, nil)
+	client.On("SongInfo", data).Return(info, nil)
+	repo.On("CompleteJob", *job, *info).Return(nil)
+	album.On("AttachSong", domain.Id(10), *info.Album).Return(nil)
+	cache.On("Incr", songVersionKey(10)).Return(int64(2), nil)
+	cache.On("Incr", libraryGenerationKey).Return(int64(1), nil)
+
+	processed, err := service.ProcessNext()
+
+	assert.Nil(t, err)
+	assert.True(t, processed)
+	repo.AssertExpectations(t)
+	album.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+// Тест для метода ProcessNext - временная ошибка API откладывает задачу с растущей паузой
+func TestEnrichmentService_ProcessNext_Retry(t *testing.T) {
+	service, repo, client, _, _ := newEnrichmentService()
+	data := domain.SongDataByUser{Group: "Muse", Name: "Hysteria"}
+	job := &domain.EnrichmentJob{ID: 5, SongID: 10, Attempts: 2, Data: data}
+	apiErr := &domain.RequestError{Err: "API is unavailable", Code: http.StatusBadGateway}
+	start := time.Now()
+
+	repo.On("ClaimJob", time.Minute).Return(job, nil)
+	client.On("SongInfo", data).Return((*domain.SongDataByApi)(nil), apiErr)
+	repo.On("RetryJob", uint64(5), apiErr.Error(), testifyMock.MatchedBy(func(next time.Time) bool {
+		return !next.Before(start.Add(2*time.Second)) && next.Before(time.Now().Add(2*time.Second+time.Millisecond))
+	})).Return(nil)
+
+	processed, err := service.ProcessNext()
+
+	assert.Nil(t, err)
+	assert.True(t, processed)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "FailJob", testifyMock.Anything, testifyMock.Anything)
+}
+
+// Тест для метода ProcessNext - задача завершается ошибкой, если повтор бесполезен или попытки закончились
+func TestEnrichmentService_ProcessNext_Fail(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     int
+		attempts int
+	}{
+		{name: "Песня не найдена", code: http.StatusNotFound, attempts: 1},
+		{name: "API отклонил данные", code: http.StatusBadRequest, attempts: 1},
+		{name: "Попытки закончились", code: http.StatusGatewayTimeout, attempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, client, cache, _ := newEnrichmentService()
+			data := domain.SongDataByUser{Group: "Muse", Name: tt.name}
+			job := &domain.EnrichmentJob{ID: 5, SongID: 10, Attempts: tt.attempts, Data: data}
+			apiErr := &domain.RequestError{Err: "error", Code: tt.code}
+
+			repo.On("ClaimJob", time.Minute).Return(job, nil)
+			client.On("SongInfo", data).Return((*domain.SongDataByApi)(nil), apiErr)
+			repo.On("FailJob", *job, apiErr.Error()).Return(nil)
+			cache.On("Incr", songVersionKey(10)).Return(int64(2), nil)
+			cache.On("Incr", libraryGenerationKey).Return(int64(1), nil)
+
+			processed, err := service.ProcessNext()
+
+			assert.Nil(t, err)
+			assert.True(t, processed)
+			repo.AssertExpectations(t)
+			repo.AssertNotCalled(t, "RetryJob", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+			cache.AssertNotCalled(t, "Del", testifyMock.Anything)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"song/internal/presentation/logger"
+	"sync"
+)
+
+// ImportService - сервис массового импорта песен из файла
+type ImportService struct {
+	cacheDb    *SongCache
+	song       interfaces.SongRepo
+	album      interfaces.AlbumRepo
+	imports    interfaces.ImportRepo
+	enrichment interfaces.EnrichmentClient
+	workers    int
+}
+
+// NewImportService создает новый объект ImportService
+// workers - число песен, одновременно получающих данные и создаваемых в библиотеке
+func NewImportService(cache *SongCache, song interfaces.SongRepo, album interfaces.AlbumRepo, imports interfaces.ImportRepo,
+	enrichment interfaces.EnrichmentClient, workers int) *ImportService {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	return &ImportService{
+		cacheDb:    cache,
+		song:       song,
+		album:      album,
+		imports:    imports,
+		enrichment: enrichment,
+		workers:    workers,
+	}
+}
+
+// Import создает песни из строк файла и возвращает отчет по каждой строке.
+// Строки без группы или названия завершаются ошибкой, повторы в файле и песни,
+// которые уже есть в библиотеке, пропускаются. Недостающие данные песен запрашиваются
+// у источников данных параллельно, данные из файла важнее полученных.
+// При пробном импорте данные не запрашиваются и библиотека не меняется.
+// После отмены ctx новые песни не создаются, оставшиеся строки завершаются ошибкой
+// ctx - контекст импорта
+// rows - строки файла
+// dryRun - пробный импорт
+// actor - автор изменения
+func (s *ImportService) Import(ctx context.Context, rows []domain.ImportRow, dryRun bool, actor string) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]domain.ImportResult, len(rows)),
+	}
+
+	seen := make(map[string]int, len(rows))
+	candidates := make([]domain.SongDataByUser, 0, len(rows))
+	for i, row := range rows {
+		report.Rows[i] = domain.ImportResult{Row: row.Row, Group: row.Group, Name: row.Name}
+		if row.Group == "" || row.Name == "" {
+			report.Rows[i].Status = domain.ImportFailed
+			report.Rows[i].Reason = "group and song are required"
+			continue
+		}
+
+		key := domain.SongKey(row.SongDataByUser)
+		if first, ok := seen[key]; ok {
+			report.Rows[i].Status = domain.ImportSkipped
+			report.Rows[i].Reason = fmt.Sprintf("duplicate of row %d", rows[first].Row)
+			continue
+		}
+		seen[key] = i
+		candidates = append(candidates, row.SongDataByUser)
+	}
+
+	existing, err := s.imports.ExistingSongs(candidates)
+	if err != nil {
+		return nil, err
+	}
+	for _, song := range existing {
+		i, ok := seen[domain.SongKey(song)]
+		if !ok {
+			continue
+		}
+		report.Rows[i].Status = domain.ImportSkipped
+		report.Rows[i].Reason = "song already exists in the library"
+	}
+
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for w := 0; w < s.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				if ctx.Err() != nil {
+					canceledRow(ctx, &report.Rows[i])
+					continue
+				}
+				s.importRow(rows[i], &report.Rows[i], actor)
+			}
+		}()
+	}
+	for i := range rows {
+		if report.Rows[i].Status != "" {
+			continue
+		}
+		if dryRun {
+			report.Rows[i].Status = domain.ImportWouldCreate
+			continue
+		}
+		select {
+		case queue <- i:
+		case <-ctx.Done():
+			canceledRow(ctx, &report.Rows[i])
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	for _, result := range report.Rows {
+		switch result.Status {
+		case domain.ImportCreated, domain.ImportWouldCreate:
+			report.Created++
+		case domain.ImportSkipped:
+			report.Skipped++
+		case domain.ImportFailed:
+			report.Failed++
+		}
+	}
+
+	if ctx.Err() != nil {
+		logger.Logger.Warn(fmt.Sprintf("Import was canceled after %d songs were created", report.Created))
+	}
+
+	if !dryRun && report.Created > 0 {
+		// Песни уже созданы, поэтому ошибка кэша не должна скрыть отчет
+		err = s.cacheDb.InvalidateLibrary()
+		if err != nil {
+			logger.Logger.Error(fmt.Sprintf("Library cache invalidation after import error - %v", err))
+		}
+	}
+
+	return report, nil
+}
+
+// canceledRow отмечает строку, которая не была импортирована из-за отмены импорта
+func canceledRow(ctx context.Context, result *domain.ImportResult) {
+	result.Status = domain.ImportFailed
+	result.Reason = fmt.Sprintf("import was canceled - %v", ctx.Err())
+}
+
+// importRow получает недостающие данные песни и создает ее, результат записывается в result
+func (s *ImportService) importRow(row domain.ImportRow, result *domain.ImportResult, actor string) {
+	data, err := s.songData(row)
+	if err != nil {
+		result.Status = domain.ImportFailed
+		result.Reason = err.Error()
+		return
+	}
+
+	id, err := s.song.CreateSong(domain.Song{
+		Name:  row.Name,
+		Group: row.Group,
+		Date:  data.Date,
+		Text:  data.Text,
+		Link:  data.Link,
+
+		Sources: data.Sources,
+	}, actor)
+	if err != nil {
+		result.Status = domain.ImportFailed
+		result.Reason = err.Error()
+		return
+	}
+	result.Status = domain.ImportCreated
+	result.SongID = *id
+
+	if data.Album != nil && data.Album.Title != "" {
+		err = s.album.AttachSong(*id, *data.Album)
+		if err != nil {
+			logger.Logger.Warn(fmt.Sprintf("Imported song %d was created without album %q: %v", *id, data.Album.Title, err))
+		}
+	}
+}
+
+// songData дополняет данные песни из файла данными источников. Поля из файла отмечаются
+// источником user. Если источники не знают песню, она создается с данными из файла,
+// но только когда в файле есть хотя бы одно поле
+func (s *ImportService) songData(row domain.ImportRow) (*domain.SongDataByApi, error) {
+	data := domain.SongDataByApi{Sources: domain.MetadataSources{}}
+	provided := 0
+	for _, field := range domain.MetadataFields {
+		if domain.MetadataValue(row.SongDataByApi, field) {
+			domain.CopyMetadataField(&data, row.SongDataByApi, field)
+			data.Sources[field] = domain.MetadataSourceUser
+			provided++
+		}
+	}
+	if provided == len(domain.MetadataFields) {
+		return &data, nil
+	}
+
+	info, err := s.enrichment.SongInfo(row.SongDataByUser)
+	if err != nil {
+		if provided > 0 && notFoundError(err) {
+			return &data, nil
+		}
+		return nil, err
+	}
+
+	for _, field := range domain.MetadataFields {
+		if data.Sources[field] == "" && domain.MetadataValue(*info, field) {
+			domain.CopyMetadataField(&data, *info, field)
+			if source := info.Sources[field]; source != "" {
+				data.Sources[field] = source
+			}
+		}
+	}
+
+	return &data, nil
+}
+
+// notFoundError проверяет, что источники данных не знают песню
+func notFoundError(err error) bool {
+	var baseErr *domain.BaseError
+	if !errors.As(err, &baseErr) {
+		return false
+	}
+
+	return baseErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"song/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+)
+
+// importRow создает строку файла импорта
+func importRow(row int, group, name string) domain.ImportRow {
+	return domain.ImportRow{Row: row, SongDataByUser: domain.SongDataByUser{Group: group, Name: name}}
+}
+
+// Тест для метода Import - ошибки, повторы в файле и песни из библиотеки попадают в отчет
+func TestImportService_Import(t *testing.T) {
+	_ = logger.NewLogger()
+	songRepo := new(mock.MockSongRepo)
+	albumRepo := new(mock.MockAlbumRepo)
+	importRepo := new(mock.MockImportRepo)
+	client := new(mock.MockEnrichmentClient)
+	cache := new(mock.MockCacheRepo)
+	date := time.Date(2003, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := []domain.ImportRow{
+		importRow(1, "Muse", "Hysteria"),
+		importRow(2, "muse", "HYSTERIA"),
+		importRow(3, "Muse", ""),
+		importRow(4, "Muse", "Starlight"),
+		importRow(5, "Muse", "Uprising"),
+		importRow(6, "Muse", "Unknown"),
+	}
+	rows[4].Text = "own text"
+
+	importRepo.On("ExistingSongs", []domain.SongDataByUser{rows[0].SongDataByUser, rows[3].SongDataByUser,
+		rows[4].SongDataByUser, rows[5].SongDataByUser}).Return([]domain.SongDataByUser{{Group: "MUSE", Name: "starlight"}}, nil)
+	client.On("SongInfo", rows[0].SongDataByUser).Return(&domain.SongDataByApi{
+		Date: date, Text: "api text", Link: "link", Album: &domain.AlbumByApi{Title: "Absolution"},
+		Sources: domain.MetadataSources{domain.FieldReleaseDate: "api", domain.FieldText: "api", domain.FieldLink: "api", domain.FieldAlbum: "api"},
+	}, nil)
+	client.On("SongInfo", rows[4].SongDataByUser).Return(&domain.SongDataByApi{
+		Text: "api text", Link: "link", Sources: domain.MetadataSources{domain.FieldText: "api", domain.FieldLink: "api"},
+	}, nil)
+	client.On("SongInfo", rows[5].SongDataByUser).Return((*domain.SongDataByApi)(nil), &domain.BaseError{Err: "song is not found", Code: http.StatusNotFound})
+
+	id1, id5 := domain.Id(1), domain.Id(5)
+	songRepo.On("CreateSong", domain.Song{Group: "Muse", Name: "Hysteria", Date: date, Text: "api text", Link: "link",
+		Sources: domain.MetadataSources{domain.FieldReleaseDate: "api", domain.FieldText: "api", domain.FieldLink: "api", domain.FieldAlbum: "api"},
+	}, "admin").Return(&id1, nil)
+	songRepo.On("CreateSong", domain.Song{Group: "Muse", Name: "Uprising", Text: "own text", Link: "link",
+		Sources: domain.MetadataSources{domain.FieldText: domain.MetadataSourceUser, domain.FieldLink: "api"},
+	}, "admin").Return(&id5, nil)
+	albumRepo.On("AttachSong", id1, domain.AlbumByApi{Title: "Absolution"}).Return(nil)
+	cache.On("Incr", libraryGenerationKey).Return(int64(1), nil)
+
+	report, err := NewImportService(newSongCache(cache), songRepo, albumRepo, importRepo, client, 2).Import(context.Background(), rows, false, "admin")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 6, report.Total)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, []domain.ImportResult{
+		{Row: 1, Group: "Muse", Name: "Hysteria", Status: domain.ImportCreated, SongID: 1},
+		{Row: 2, Group: "muse", Name: "HYSTERIA", Status: domain.ImportSkipped, Reason: "duplicate of row 1"},
+		{Row: 3, Group: "Muse", Status: domain.ImportFailed, Reason: "group and song are required"},
+		{Row: 4, Group: "Muse", Name: "Starlight", Status: domain.ImportSkipped, Reason: "song already exists in the library"},
+		{Row: 5, Group: "Muse", Name: "Uprising", Status: domain.ImportCreated, SongID: 5},
+		{Row: 6, Group: "Muse", Name: "Unknown", Status: domain.ImportFailed, Reason: "song is not found"},
+	}, report.Rows)
+	songRepo.AssertNumberOfCalls(t, "CreateSong", 2)
+	cache.AssertExpectations(t)
+}
+
+// Тест для метода Import - пробный импорт не запрашивает данные и не создает песни
+func TestImportService_Import_DryRun(t *testing.T) {
+	songRepo := new(mock.MockSongRepo)
+	importRepo := new(mock.MockImportRepo)
+	client := new(mock.MockEnrichmentClient)
+	rows := []domain.ImportRow{importRow(1, "Muse", "Hysteria")}
+
+	importRepo.On("ExistingSongs", testifyMock.Anything).Return([]domain.SongDataByUser(nil), nil)
+
+	report, err := NewImportService(newSongCache(new(mock.MockCacheRepo)), songRepo, new(mock.MockAlbumRepo), importRepo, client, 4).Import(context.Background(), rows, true, "admin")
+
+	assert.Nil(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, domain.ImportWouldCreate, report.Rows[0].Status)
+	client.AssertNotCalled(t, "SongInfo", testifyMock.Anything)
+	songRepo.AssertNotCalled(t, "CreateSong", testifyMock.Anything, testifyMock.Anything)
+}
+
+// Тест для метода Import - песня из библиотеки, не совпавшая ни с одной строкой, не пропускает первую строку
+func TestImportService_Import_UnmatchedExisting(t *testing.T) {
+	importRepo := new(mock.MockImportRepo)
+	rows := []domain.ImportRow{importRow(1, "Muse", "Hysteria")}
+
+	importRepo.On("ExistingSongs", testifyMock.Anything).Return([]domain.SongDataByUser{{Group: "Queen", Name: "Innuendo"}}, nil)
+
+	report, err := NewImportService(newSongCache(new(mock.MockCacheRepo)), new(mock.MockSongRepo), new(mock.MockAlbumRepo), importRepo, new(mock.MockEnrichmentClient), 1).
+		Import(context.Background(), rows, true, "admin")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, report.Skipped)
+	assert.Equal(t, domain.ImportWouldCreate, report.Rows[0].Status)
+}
+
+// Тест для метода Import - ошибка проверки библиотеки прерывает импорт
+func TestImportService_Import_RepoError(t *testing.T) {
+	importRepo := new(mock.MockImportRepo)
+	importRepo.On("ExistingSongs", testifyMock.Anything).Return([]domain.SongDataByUser(nil), &domain.BaseError{Code: http.StatusInternalServerError})
+
+	report, err := NewImportService(newSongCache(new(mock.MockCacheRepo)), new(mock.MockSongRepo), new(mock.MockAlbumRepo), importRepo, new(mock.MockEnrichmentClient), 1).
+		Import(context.Background(), []domain.ImportRow{importRow(1, "Muse", "Hysteria")}, false, "admin")
+
+	assert.Nil(t, report)
+	assert.NotNil(t, err)
+}
+
+// Тест для метода Import - после отмены контекста песни не создаются
+func TestImportService_Import_Canceled(t *testing.T) {
+	importRepo := new(mock.MockImportRepo)
+	songRepo := new(mock.MockSongRepo)
+	client := new(mock.MockEnrichmentClient)
+	rows := []domain.ImportRow{importRow(1, "Muse", "Hysteria"), importRow(2, "Muse", "Uprising")}
+	importRepo.On("ExistingSongs", testifyMock.Anything).Return([]domain.SongDataByUser(nil), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := NewImportService(newSongCache(new(mock.MockCacheRepo)), songRepo, new(mock.MockAlbumRepo), importRepo, client, 2).
+		Import(ctx, rows, false, "admin")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, 0, report.Created)
+	assert.Contains(t, report.Rows[0].Reason, "import was canceled")
+	client.AssertNotCalled(t, "SongInfo", testifyMock.Anything)
+	songRepo.AssertNotCalled(t, "CreateSong", testifyMock.Anything, testifyMock.Anything)
+}
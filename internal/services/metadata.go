@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+)
+
+// MetadataChain - цепочка источников дополнительных данных о песне.
+// Каждое поле берется из первого источника в порядке правил для этого поля, который его заполнил.
+// Источник запрашивается только когда нужен и не больше одного раза, поэтому медленный API
+// не вызывается, если более приоритетные источники уже заполнили все поля
+type MetadataChain struct {
+	providers  map[string]interfaces.MetadataProvider
+	precedence domain.MetadataPrecedence
+}
+
+// NewMetadataChain создает цепочку источников
+// precedence - порядок источников для полей
+// providers - источники данных, их имена используются в правилах порядка
+func NewMetadataChain(precedence domain.MetadataPrecedence, providers ...interfaces.MetadataProvider) *MetadataChain {
+	chain := &MetadataChain{
+		providers:  make(map[string]interfaces.MetadataProvider, len(providers)),
+		precedence: precedence,
+	}
+	for _, provider := range providers {
+		chain.providers[provider.Name()] = provider
+	}
+
+	return chain
+}
+
+// SongInfo собирает данные песни из источников и отмечает источник каждого поля.
+// Источник, которому песня неизвестна, пропускается. Другие ошибки прерывают сбор,
+// чтобы песня не сохранилась с неполными данными из-за временного сбоя
+// data - группа и название песни
+func (c *MetadataChain) SongInfo(data domain.SongDataByUser) (*domain.SongDataByApi, error) {
+	results := make(map[string]*domain.SongDataByApi, len(c.providers))
+	merged := domain.SongDataByApi{Sources: domain.MetadataSources{}}
+
+	for _, field := range domain.MetadataFields {
+		for _, name := range c.precedence.Order(field) {
+			info, queried := results[name]
+			if !queried {
+				var err error
+				info, err = c.query(name, data)
+				if err != nil {
+					return nil, err
+				}
+				results[name] = info
+			}
+
+			if info != nil && domain.MetadataValue(*info, field) {
+				domain.CopyMetadataField(&merged, *info, field)
+				merged.Sources[field] = name
+				break
+			}
+		}
+	}
+
+	if len(merged.Sources) == 0 {
+		return nil, &domain.RequestError{
+			Err:  "song is not found by any metadata provider",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return &merged, nil
+}
+
+// query запрашивает данные у источника name, если песня ему неизвестна - nil
+func (c *MetadataChain) query(name string, data domain.SongDataByUser) (*domain.SongDataByApi, error) {
+	provider, ok := c.providers[name]
+	if !ok {
+		return nil, nil
+	}
+
+	info, err := provider.SongInfo(data)
+	if err != nil {
+		var baseErr *domain.BaseError
+		if errors.As(err, &baseErr) && baseErr.Code == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// OverrideService - сервис для работы с ручными исправлениями данных песен
+type OverrideService struct {
+	repo interfaces.MetadataOverrideRepo
+}
+
+// NewOverrideService создает новый объект OverrideService
+func NewOverrideService(repo interfaces.MetadataOverrideRepo) *OverrideService {
+	return &OverrideService{repo: repo}
+}
+
+// GetOverrides получает исправления по номеру страницы
+// page - номер страницы для пагинации
+func (s *OverrideService) GetOverrides(page domain.Page) (*[]domain.MetadataOverride, error) {
+	return s.repo.GetOverrides(page)
+}
+
+// SetOverride создает или заменяет исправление песни, новые песни получат исправленные данные
+// override - исправление, хотя бы одно поле должно быть заполнено
+func (s *OverrideService) SetOverride(override domain.MetadataOverride) (*domain.MetadataOverride, error) {
+	if override.Group == "" || override.Name == "" {
+		return nil, &domain.InputDataError{
+			Err:  "group and song are required",
+			Code: http.StatusBadRequest,
+		}
+	}
+	if override.Date == nil && override.Text == "" && override.Link == "" {
+		return nil, &domain.InputDataError{
+			Err:  "at least one of releaseDate, text and link is required",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return s.repo.SetOverride(override)
+}
+
+// DelOverride удаляет исправление по идентификатору
+// id - идентификатор исправления
+func (s *OverrideService) DelOverride(id uint64) error {
+	return s.repo.DelOverride(id)
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+)
+
+// newMetadataProviders создает mock-источники исправлений, каталога и API
+func newMetadataProviders() (*mock.MockMetadataProvider, *mock.MockMetadataProvider, *mock.MockMetadataProvider) {
+	return &mock.MockMetadataProvider{ProviderName: domain.MetadataProviderOverride},
+		&mock.MockMetadataProvider{ProviderName: domain.MetadataProviderCatalog},
+		&mock.MockMetadataProvider{ProviderName: domain.MetadataProviderAPI}
+}
+
+// Тест для метода SongInfo - поля собираются из разных источников по правилам порядка
+func TestMetadataChain_SongInfo_Merge(t *testing.T) {
+	override, catalog, api := newMetadataProviders()
+	data := domain.SongDataByUser{Group: "Muse", Name: "Hysteria"}
+	date := time.Date(2003, 12, 1, 0, 0, 0, 0, time.UTC)
+	precedence, err := domain.ParseMetadataPrecedence("text=catalog,api", []string{"override", "catalog", "api"})
+	assert.Nil(t, err)
+
+	override.On("SongInfo", data).Return(&domain.SongDataByApi{Link: "fixed link"}, nil)
+	catalog.On("SongInfo", data).Return(&domain.SongDataByApi{Text: "catalog text"}, nil)
+	api.On("SongInfo", data).Return(&domain.SongDataByApi{
+		Date: date, Text: "api text", Link: "api link", Album: &domain.AlbumByApi{Title: "Absolution"},
+	}, nil)
+
+	info, err := NewMetadataChain(precedence, override, catalog, api).SongInfo(data)
+
+	assert.Nil(t, err)
+	assert.Equal(t, date, info.Date)
+	assert.Equal(t, "catalog text", info.Text)
+	assert.Equal(t, "fixed link", info.Link)
+	assert.Equal(t, "Absolution", info.Album.Title)
+	assert.Equal(t, domain.MetadataSources{
+		domain.FieldReleaseDate: domain.MetadataProviderAPI,
+		domain.FieldText:        domain.MetadataProviderCatalog,
+		domain.FieldLink:        domain.MetadataProviderOverride,
+		domain.FieldAlbum:       domain.MetadataProviderAPI,
+	}, info.Sources)
+	override.AssertNumberOfCalls(t, "SongInfo", 1)
+	api.AssertNumberOfCalls(t, "SongInfo", 1)
+}
+
+// Тест для метода SongInfo - API не запрашивается, если остальные источники заполнили все поля
+func TestMetadataChain_SongInfo_SkipsAPI(t *testing.T) {
+	override, catalog, api := newMetadataProviders()
+	data := domain.SongDataByUser{Group: "Muse", Name: "Hysteria"}
+	precedence, _ := domain.ParseMetadataPrecedence("album=override,catalog", []string{"override", "catalog", "api"})
+
+	override.On("SongInfo", data).Return((*domain.SongDataByApi)(nil), &domain.BaseError{Code: http.StatusNotFound})
+	catalog.On("SongInfo", data).Return(&domain.SongDataByApi{
+		Date: time.Date(2003, 12, 1, 0, 0, 0, 0, time.UTC), Text: "text", Link: "link",
+	}, nil)
+
+	info, err := NewMetadataChain(precedence, override, catalog, api).SongInfo(data)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "text", info.Text)
+	assert.Nil(t, info.Album)
+	api.AssertNotCalled(t, "SongInfo", testifyMock.Anything)
+}
+
+// Тест для метода SongInfo - временная ошибка источника прерывает сбор, даже если часть полей заполнена
+func TestMetadataChain_SongInfo_Unavailable(t *testing.T) {
+	_, catalog, api := newMetadataProviders()
+	data := domain.SongDataByUser{Group: "Muse", Name: "Hysteria"}
+	precedence, _ := domain.ParseMetadataPrecedence("", []string{"catalog", "api"})
+
+	catalog.On("SongInfo", data).Return(&domain.SongDataByApi{Link: "link"}, nil)
+	api.On("SongInfo", data).Return((*domain.SongDataByApi)(nil), &domain.BaseError{Code: http.StatusServiceUnavailable})
+
+	_, err := NewMetadataChain(precedence, catalog, api).SongInfo(data)
+
+	assert.Equal(t, http.StatusServiceUnavailable, err.(*domain.BaseError).Code)
+}
+
+// Тест для метода SongInfo без данных ни в одном источнике
+func TestMetadataChain_SongInfo_NotFound(t *testing.T) {
+	override, _, api := newMetadataProviders()
+	data := domain.SongDataByUser{Group: "Muse", Name: "Unknown"}
+	precedence, _ := domain.ParseMetadataPrecedence("", []string{"override", "api"})
+
+	override.On("SongInfo", data).Return((*domain.SongDataByApi)(nil), &domain.BaseError{Code: http.StatusNotFound})
+	api.On("SongInfo", data).Return((*domain.SongDataByApi)(nil), &domain.BaseError{Code: http.StatusNotFound})
+
+	_, err := NewMetadataChain(precedence, override, api).SongInfo(data)
+
+	assert.Equal(t, http.StatusNotFound, err.(*domain.BaseError).Code)
+}
+
+// Тест для метода SetOverride без исправленных полей
+func TestOverrideService_SetOverride_Empty(t *testing.T) {
+	repo := new(mock.MockOverrideRepo)
+
+	_, err := NewOverrideService(repo).SetOverride(domain.MetadataOverride{Group: "Muse", Name: "Hysteria"})
+
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.BaseError).Code)
+	repo.AssertNotCalled(t, "SetOverride", testifyMock.Anything)
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Параметры работы с OIDC-провайдером
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+	oidcLeeway        = 30 * time.Second
+	// jwksMinRefresh - минимальный интервал между загрузками ключей при неизвестном kid
+	// или после неудачной загрузки, чтобы токены не заваливали провайдера запросами
+	jwksMinRefresh = 30 * time.Second
+	// DefaultJWKSTTL - время жизни кэша ключей подписи по умолчанию
+	DefaultJWKSTTL = time.Hour
+)
+
+// oidcRolePriority - роли от большей к меньшей, при нескольких ролях выбирается первая
+var oidcRolePriority = []string{domain.RoleAdmin, domain.RoleEditor, domain.RoleViewer}
+
+// oidcDiscovery - нужная часть документа discovery
+type oidcDiscovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// jsonWebKey - ключ подписи из JWKS
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// OIDCVerifier - проверка токенов OIDC-провайдера по его документу discovery и JWKS.
+// Ключи кэшируются и загружаются заново по истечении времени жизни или при появлении нового kid
+type OIDCVerifier struct {
+	config domain.OIDCConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	jwksURI   string
+	keys      map[string]interface{}
+	fetchedAt time.Time
+	failedAt  time.Time // Время неудачной загрузки ключей, пока они ни разу не загружены
+	failure   error     // Ошибка неудачной загрузки ключей
+}
+
+// NewOIDCVerifier создает новый объект OIDCVerifier, документ discovery загружается при первой проверке
+func NewOIDCVerifier(config domain.OIDCConfig, client *http.Client) *OIDCVerifier {
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	if config.RoleClaim == "" {
+		config.RoleClaim = "roles"
+	}
+	if config.JWKSTTL <= 0 {
+		config.JWKSTTL = DefaultJWKSTTL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &OIDCVerifier{
+		config: config,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Issuer возвращает издателя, токены которого проверяются
+func (v *OIDCVerifier) Issuer() string {
+	return v.config.Issuer
+}
+
+// Verify проверяет подпись, издателя, получателя и срок действия токена
+// и возвращает пользователя с ролью из утверждений токена
+// token - токен OIDC-провайдера
+func (v *OIDCVerifier) Verify(token string) (*domain.Principal, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(v.config.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(oidcLeeway),
+		jwt.WithTimeFunc(v.now),
+	}
+	if v.config.Audience != "" {
+		options = append(options, jwt.WithAudience(v.config.Audience))
+	}
+
+	var keyErr error
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.key(kid)
+		keyErr = err
+		return key, err
+	}, options...)
+	if keyErr != nil {
+		return nil, keyErr
+	}
+	if err != nil {
+		return nil, unauthorized("Invalid or expired token")
+	}
+
+	subject, _ := claims.GetSubject()
+	username := subject
+	for _, name := range []string{"preferred_username", "email"} {
+		if value, ok := claims[name].(string); ok && value != "" {
+			username = value
+			break
+		}
+	}
+
+	role := v.role(claims)
+	return &domain.Principal{
+		Username: username,
+		Role:     role,
+		Scopes:   domain.RoleScopes[role],
+	}, nil
+}
+
+// role находит роль пользователя по утверждению RoleClaim
+func (v *OIDCVerifier) role(claims jwt.MapClaims) string {
+	var value interface{} = map[string]interface{}(claims)
+	for _, part := range strings.Split(v.config.RoleClaim, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return v.config.DefaultRole
+		}
+		value = object[part]
+	}
+
+	var values []string
+	switch value := value.(type) {
+	case string:
+		values = strings.Fields(value)
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	granted := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if len(v.config.RoleMap) > 0 {
+			value = v.config.RoleMap[value]
+		}
+		granted[value] = struct{}{}
+	}
+	for _, role := range oidcRolePriority {
+		if _, ok := granted[role]; ok {
+			return role
+		}
+	}
+
+	return v.config.DefaultRole
+}
+
+// key возвращает ключ подписи kid, при необходимости загружая JWKS заново
+func (v *OIDCVerifier) key(kid string) (interface{}, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	if v.keys == nil && now.Sub(v.failedAt) < jwksMinRefresh {
+		// Провайдер недавно не ответил - не ждем его снова до истечения интервала
+		return nil, v.failure
+	}
+
+	key, found := v.lookup(kid)
+	expired := now.Sub(v.fetchedAt) >= v.config.JWKSTTL
+	if !expired && (found || now.Sub(v.fetchedAt) < jwksMinRefresh) {
+		if !found {
+			return nil, unauthorized("Unknown token signing key")
+		}
+		return key, nil
+	}
+
+	err := v.refresh()
+	if err != nil {
+		if v.keys == nil {
+			v.failedAt, v.failure = now, err
+			return nil, err
+		}
+		// Провайдер недоступен - продолжаем работать с загруженными ранее ключами
+		logger.Logger.Warn(err.Error())
+	}
+	v.fetchedAt = now
+
+	key, found = v.lookup(kid)
+	if !found {
+		return nil, unauthorized("Unknown token signing key")
+	}
+
+	return key, nil
+}
+
+// lookup ищет ключ в кэше, токен без kid подходит, только если ключ один
+func (v *OIDCVerifier) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// refresh загружает документ discovery, если он еще не загружен, и JWKS
+func (v *OIDCVerifier) refresh() error {
+	if v.jwksURI == "" {
+		var discovery oidcDiscovery
+		if err := v.getJSON(v.config.Issuer+oidcDiscoveryPath, &discovery); err != nil {
+			return err
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != v.config.Issuer || discovery.JWKSURI == "" {
+			return &domain.BaseError{
+				Err:  fmt.Sprintf("OIDC discovery document of %s is invalid", v.config.Issuer),
+				Code: http.StatusInternalServerError,
+			}
+		}
+		v.jwksURI = discovery.JWKSURI
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := v.getJSON(v.jwksURI, &jwks); err != nil {
+		return err
+	}
+
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			logger.Logger.Warn(fmt.Sprintf("Skipping OIDC key %q - %v", jwk.Kid, err))
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	v.keys = keys
+
+	return nil
+}
+
+// getJSON выполняет GET-запрос к провайдеру и декодирует ответ в v
+func (v *OIDCVerifier) getJSON(url string, dst interface{}) error {
+	resp, err := v.client.Get(url)
+	if err != nil {
+		return &domain.RequestError{
+			Err:  fmt.Sprintf("OIDC request error - %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return &domain.RequestError{
+			Err:  fmt.Sprintf("OIDC request to %s failed with status %d", url, resp.StatusCode),
+			Code: http.StatusInternalServerError,
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return &domain.RequestError{
+			Err:  fmt.Sprintf("OIDC response decoding error - %v", err),
+			Code: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
+
+// publicKey преобразует JWK в открытый ключ RSA или ECDSA
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus - %v", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid coordinates")
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("point is not on the curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"song/internal/domain"
+	"song/test/mock"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// testIssuer - локальный OIDC-провайдер с документом discovery и JWKS
+type testIssuer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	keys      map[string]*rsa.PrivateKey
+	jwksCalls int32
+}
+
+// newTestIssuer запускает провайдера с ключом kid
+func newTestIssuer(t *testing.T, kid string) *testIssuer {
+	issuer := &testIssuer{keys: make(map[string]*rsa.PrivateKey)}
+	issuer.addKey(t, kid)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(oidcDiscovery{Issuer: issuer.URL, JWKSURI: issuer.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&issuer.jwksCalls, 1)
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		keys := make([]jsonWebKey, 0, len(issuer.keys))
+		for kid, key := range issuer.keys {
+			keys = append(keys, jsonWebKey{
+				Kid: kid,
+				Kty: "RSA",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+
+	return issuer
+}
+
+// addKey добавляет провайдеру ключ подписи kid
+func (i *testIssuer) addKey(t *testing.T, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	i.mu.Lock()
+	i.keys[kid] = key
+	i.mu.Unlock()
+}
+
+// token подписывает токен ключом kid
+func (i *testIssuer) token(t *testing.T, kid string, claims jwt.MapClaims) string {
+	i.mu.Lock()
+	key := i.keys[kid]
+	i.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	assert.Nil(t, err)
+
+	return signed
+}
+
+// claims возвращает утверждения действующего токена провайдера
+func (i *testIssuer) claims(roles ...interface{}) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                i.URL,
+		"sub":                "00u1",
+		"aud":                "song-api",
+		"exp":                time.Now().Add(time.Minute).Unix(),
+		"preferred_username": "alice@corp",
+		"realm_access":       map[string]interface{}{"roles": roles},
+	}
+}
+
+// newTestVerifier создает проверку токенов провайдера issuer
+func newTestVerifier(issuer *testIssuer) *OIDCVerifier {
+	return NewOIDCVerifier(domain.OIDCConfig{
+		Issuer:      issuer.URL,
+		Audience:    "song-api",
+		RoleClaim:   "realm_access.roles",
+		RoleMap:     map[string]string{"song-editors": domain.RoleEditor, "song-admins": domain.RoleAdmin},
+		DefaultRole: domain.RoleViewer,
+	}, issuer.Client())
+}
+
+// Тест для метода Verify с ролью из утверждений токена
+func TestOIDCVerifier_Verify(t *testing.T) {
+	issuer := newTestIssuer(t, "k1")
+	verifier := newTestVerifier(issuer)
+
+	principal, err := verifier.Verify(issuer.token(t, "k1", issuer.claims("offline_access", "song-editors")))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "alice@corp", principal.Username)
+	assert.Equal(t, domain.RoleEditor, principal.Role)
+	assert.True(t, principal.HasScope(domain.ScopeSongsWrite))
+	assert.False(t, principal.HasScope(domain.ScopeAdmin))
+
+	principal, err = verifier.Verify(issuer.token(t, "k1", issuer.claims("song-editors", "song-admins")))
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.RoleAdmin, principal.Role)
+
+	principal, err = verifier.Verify(issuer.token(t, "k1", issuer.claims()))
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.RoleViewer, principal.Role)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&issuer.jwksCalls))
+}
+
+// Тест для метода Verify с недействительными токенами
+func TestOIDCVerifier_Verify_Invalid(t *testing.T) {
+	issuer := newTestIssuer(t, "k1")
+	other := newTestIssuer(t, "k1")
+	verifier := newTestVerifier(issuer)
+
+	expired := issuer.claims("song-editors")
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	audience := issuer.claims("song-editors")
+	audience["aud"] = "other-api"
+
+	for name, token := range map[string]string{
+		"Истекший токен":        issuer.token(t, "k1", expired),
+		"Другой получатель":     issuer.token(t, "k1", audience),
+		"Подпись другим ключом": other.token(t, "k1", issuer.claims("song-editors")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifier.Verify(token)
+
+			assert.Equal(t, http.StatusUnauthorized, err.(*domain.BaseError).Code)
+		})
+	}
+}
+
+// Тест для метода Verify со сменой ключей провайдера
+func TestOIDCVerifier_Verify_KeyRotation(t *testing.T) {
+	issuer := newTestIssuer(t, "k1")
+	verifier := newTestVerifier(issuer)
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+
+	_, err := verifier.Verify(issuer.token(t, "k1", issuer.claims()))
+	assert.Nil(t, err)
+
+	issuer.addKey(t, "k2")
+	token := issuer.token(t, "k2", issuer.claims())
+
+	// Неизвестный kid сразу после загрузки ключей не приводит к новой загрузке
+	_, err = verifier.Verify(token)
+	assert.Equal(t, http.StatusUnauthorized, err.(*domain.BaseError).Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&issuer.jwksCalls))
+
+	now = now.Add(jwksMinRefresh)
+	_, err = verifier.Verify(token)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&issuer.jwksCalls))
+}
+
+// Тест для метода Verify с недоступным провайдером - повторная загрузка ключей
+// выполняется не чаще jwksMinRefresh
+func TestOIDCVerifier_Verify_IssuerFailure(t *testing.T) {
+	issuer := newTestIssuer(t, "k1")
+	var calls int32
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(failing.Close)
+	verifier := NewOIDCVerifier(domain.OIDCConfig{Issuer: failing.URL}, failing.Client())
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+	claims := issuer.claims()
+	claims["iss"] = failing.URL
+	token := issuer.token(t, "k1", claims)
+
+	for i := 0; i < 3; i++ {
+		_, err := verifier.Verify(token)
+		assert.Equal(t, http.StatusInternalServerError, err.(*domain.RequestError).Code)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	now = now.Add(jwksMinRefresh)
+	_, err := verifier.Verify(token)
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+// Тест для метода Authenticate: токены провайдера и собственные токены принимаются вместе
+func TestAuthService_Authenticate_OIDC(t *testing.T) {
+	issuer := newTestIssuer(t, "k1")
+	authRepo := new(mock.MockAuthRepo)
+	authService := NewAuthService(authRepo, new(mock.MockAPIKeyRepo), newTestVerifier(issuer), testAuthConfig)
+
+	principal, err := authService.Authenticate(issuer.token(t, "k1", issuer.claims("song-editors")))
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.RoleEditor, principal.Role)
+	assert.Equal(t, uint64(0), principal.UserID)
+
+	tokens, session := loginTestUser(t, authRepo, authService)
+	session.Role = domain.RoleEditor
+	authRepo.On("GetSession", session.ID).Return(&session, nil)
+
+	principal, err = authService.Authenticate(tokens.AccessToken)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", principal.Username)
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"fmt"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"song/internal/presentation/logger"
+	"sync"
+	"time"
+)
+
+// RateLimitRetry - сколько запросы проверяются только в памяти после ошибки основного хранилища,
+// чтобы недоступный Redis не замедлял каждый запрос
+const RateLimitRetry = 10 * time.Second
+
+// RateLimitService - сервис для ограничения частоты запросов клиентов
+type RateLimitService struct {
+	primary  interfaces.RateLimitRepo
+	fallback interfaces.RateLimitRepo
+	rules    domain.RateLimitRules
+	now      func() time.Time
+
+	mu          sync.Mutex
+	bypassUntil time.Time
+	blocked     map[string]time.Time // Клиенты, превысившие ограничение неудачными аутентификациями
+}
+
+// NewRateLimitService создает новый объект RateLimitService
+// primary - общие для всех экземпляров счетчики
+// fallback - счетчики процесса на случай недоступности primary
+// rules - правила ограничения
+func NewRateLimitService(primary, fallback interfaces.RateLimitRepo, rules domain.RateLimitRules) *RateLimitService {
+	return &RateLimitService{
+		primary:  primary,
+		fallback: fallback,
+		rules:    rules,
+		now:      time.Now,
+		blocked:  make(map[string]time.Time),
+	}
+}
+
+// Allow учитывает запрос клиента к маршруту, если для него есть правило
+// kind - вид клиента
+// identity - идентификатор клиента
+// method, path - маршрут
+func (s *RateLimitService) Allow(kind, identity, method, path string) (*domain.RateLimitResult, error) {
+	rule, limit, ok := s.rules.Match(kind, method, path)
+	if !ok {
+		return nil, nil
+	}
+
+	key := rule + ":" + identity
+	now := s.now()
+	if !s.bypassed(now) {
+		result, err := s.primary.Allow(key, limit, now)
+		if err == nil {
+			return result, nil
+		}
+
+		logger.Logger.Warn(fmt.Sprintf("Rate limit storage is unavailable, limiting in process - %v", err))
+		s.mu.Lock()
+		s.bypassUntil = now.Add(RateLimitRetry)
+		s.mu.Unlock()
+	}
+
+	return s.fallback.Allow(key, limit, now)
+}
+
+// AuthFailure учитывает неудачную аутентификацию клиента по IP-адресу в ограничении его адреса.
+// Если ограничение превышено, адрес блокируется до RetryAfter и его токены не проверяются
+// identity - идентификатор клиента по IP-адресу
+// method, path - маршрут
+func (s *RateLimitService) AuthFailure(identity, method, path string) (*domain.RateLimitResult, error) {
+	result, err := s.Allow(domain.RateLimitIP, identity, method, path)
+	if err != nil || result == nil || result.Allowed {
+		return result, err
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for client, until := range s.blocked {
+		if !now.Before(until) {
+			delete(s.blocked, client)
+		}
+	}
+	s.blocked[identity] = now.Add(result.RetryAfter)
+
+	return result, nil
+}
+
+// AuthBlocked возвращает, через сколько клиент сможет снова пройти аутентификацию,
+// 0 - клиент не заблокирован
+// identity - идентификатор клиента по IP-адресу
+func (s *RateLimitService) AuthBlocked(identity string) time.Duration {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.blocked[identity]
+	if !ok {
+		return 0
+	}
+	if !now.Before(until) {
+		delete(s.blocked, identity)
+		return 0
+	}
+
+	return until.Sub(now)
+}
+
+// bypassed проверяет, что основное хранилище недавно было недоступно
+func (s *RateLimitService) bypassed(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return now.Before(s.bypassUntil)
+}
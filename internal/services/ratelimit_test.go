@@ -0,0 +1,80 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/internal/presentation/logger"
+	"song/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRateLimitRules = domain.RateLimitRules{
+	"POST /song": {Limit: 10, Window: time.Minute},
+}
+
+// Тест для метода Allow по правилу маршрута
+func TestRateLimitService_Allow(t *testing.T) {
+	primary := new(mock.MockRateLimitRepo)
+	fallback := new(mock.MockRateLimitRepo)
+	rateLimitService := NewRateLimitService(primary, fallback, testRateLimitRules)
+	now := time.Now()
+	rateLimitService.now = func() time.Time { return now }
+	expected := &domain.RateLimitResult{Allowed: true, Limit: 10, Remaining: 9}
+
+	primary.On("Allow", "POST /song:user:7", testRateLimitRules["POST /song"], now).Return(expected, nil)
+
+	result, err := rateLimitService.Allow(domain.RateLimitUser, "user:7", http.MethodPost, "/song")
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	fallback.AssertNotCalled(t, "Allow")
+}
+
+// Тест для метода Allow без подходящего правила
+func TestRateLimitService_Allow_NoRule(t *testing.T) {
+	primary := new(mock.MockRateLimitRepo)
+	rateLimitService := NewRateLimitService(primary, new(mock.MockRateLimitRepo), testRateLimitRules)
+
+	result, err := rateLimitService.Allow(domain.RateLimitIP, "ip:10.0.0.1", http.MethodGet, "/lib")
+
+	assert.Nil(t, err)
+	assert.Nil(t, result)
+	primary.AssertNotCalled(t, "Allow")
+}
+
+// Тест для метода Allow при недоступном Redis: запросы считаются в памяти,
+// пока не пройдет RateLimitRetry
+func TestRateLimitService_Allow_Fallback(t *testing.T) {
+	_ = logger.NewLogger()
+	primary := new(mock.MockRateLimitRepo)
+	fallback := new(mock.MockRateLimitRepo)
+	rateLimitService := NewRateLimitService(primary, fallback, testRateLimitRules)
+	now := time.Now()
+	rateLimitService.now = func() time.Time { return now }
+	limit := testRateLimitRules["POST /song"]
+	expected := &domain.RateLimitResult{Allowed: true, Limit: 10, Remaining: 9}
+
+	primary.On("Allow", "POST /song:ip:10.0.0.1", limit, now).
+		Return((*domain.RateLimitResult)(nil), &domain.BaseError{Code: http.StatusInternalServerError}).Once()
+	fallback.On("Allow", "POST /song:ip:10.0.0.1", limit, now).Return(expected, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		result, err := rateLimitService.Allow(domain.RateLimitIP, "ip:10.0.0.1", http.MethodPost, "/song")
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result)
+	}
+	primary.AssertNumberOfCalls(t, "Allow", 1)
+
+	now = now.Add(RateLimitRetry)
+	primary.On("Allow", "POST /song:ip:10.0.0.1", limit, now).Return(expected, nil).Once()
+
+	_, err := rateLimitService.Allow(domain.RateLimitIP, "ip:10.0.0.1", http.MethodPost, "/song")
+
+	assert.Nil(t, err)
+	primary.AssertExpectations(t)
+	fallback.AssertExpectations(t)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"song/internal/domain"
+	"song/internal/interfaces"
+)
+
+// RevisionService - сервис для работы с историей изменений песен
+type RevisionService struct {
+	cacheDb  *SongCache
+	revision interfaces.RevisionRepo
+}
+
+// NewRevisionService создает новый объект RevisionService
+func NewRevisionService(cache *SongCache, revision interfaces.RevisionRepo) *RevisionService {
+	return &RevisionService{
+		cacheDb:  cache,
+		revision: revision,
+	}
+}
+
+// GetRevisions получает ревизии песни
+// songID - идентификатор песни
+func (s *RevisionService) GetRevisions(songID domain.Id) (*[]domain.Revision, error) {
+	return s.revision.GetRevisions(songID)
+}
+
+// GetRevision получает ревизию песни со снимком
+// songID - идентификатор песни
+// id - идентификатор ревизии
+func (s *RevisionService) GetRevision(songID, id domain.Id) (*domain.Revision, error) {
+	return s.revision.GetRevision(songID, id)
+}
+
+// DiffRevisions сравнивает две ревизии песни по полям
+// songID - идентификатор песни
+// from - исходная ревизия
+// to - конечная ревизия
+func (s *RevisionService) DiffRevisions(songID, from, to domain.Id) (*domain.RevisionDiff, error) {
+	fromRevision, err := s.revision.GetRevision(songID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := s.revision.GetRevision(songID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.RevisionDiff{
+		SongID: songID,
+		From:   from,
+		To:     to,
+		Fields: domain.DiffSongs(*fromRevision.Snapshot, *toRevision.Snapshot),
+	}, nil
+}
+
+// RevertSong возвращает песню к состоянию ревизии и сбрасывает ее кэш
+// songID - идентификатор песни
+// id - идентификатор ревизии
+// actor - автор изменения
+func (s *RevisionService) RevertSong(songID, id domain.Id, actor string) error {
+	err := s.revision.RevertSong(songID, id, actor)
+	if err != nil {
+		return err
+	}
+
+	return s.cacheDb.InvalidateSong(songID)
+}
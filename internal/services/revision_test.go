@@ -0,0 +1,61 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+)
+
+// Тест для метода DiffRevisions
+func TestRevisionService_DiffRevisions(t *testing.T) {
+	mockRevisionRepo := new(mock.MockRevisionRepo)
+	revisionService := NewRevisionService(newSongCache(new(mock.MockCacheRepo)), mockRevisionRepo)
+
+	mockRevisionRepo.On("GetRevision", domain.Id(1), domain.Id(2)).
+		Return(&domain.Revision{ID: 2, Snapshot: &domain.Song{ID: 1, Name: "Hole", Link: "link"}}, nil)
+	mockRevisionRepo.On("GetRevision", domain.Id(1), domain.Id(5)).
+		Return(&domain.Revision{ID: 5, Snapshot: &domain.Song{ID: 1, Name: "Hole", Link: "new link"}}, nil)
+
+	diff, err := revisionService.DiffRevisions(1, 2, 5)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.FieldDiff{{Field: domain.FieldLink, Old: "link", New: "new link"}}, diff.Fields)
+	mockRevisionRepo.AssertExpectations(t)
+}
+
+// Тест для метода RevertSong со сбросом кэша
+func TestRevisionService_RevertSong(t *testing.T) {
+	mockRevisionRepo := new(mock.MockRevisionRepo)
+	mockCache := new(mock.MockCacheRepo)
+	revisionService := NewRevisionService(newSongCache(mockCache), mockRevisionRepo)
+
+	mockRevisionRepo.On("RevertSong", domain.Id(1), domain.Id(2), "editor").Return(nil)
+	mockCache.On("Incr", songVersionKey(1)).Return(int64(2), nil)
+	mockCache.On("Incr", libraryGenerationKey).Return(int64(1), nil)
+
+	err := revisionService.RevertSong(1, 2, "editor")
+
+	assert.Nil(t, err)
+	mockRevisionRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+// Тест для метода RevertSong с несуществующей ревизией
+func TestRevisionService_RevertSong_NotFound(t *testing.T) {
+	mockRevisionRepo := new(mock.MockRevisionRepo)
+	mockCache := new(mock.MockCacheRepo)
+	revisionService := NewRevisionService(newSongCache(mockCache), mockRevisionRepo)
+
+	mockRevisionRepo.On("RevertSong", domain.Id(1), domain.Id(9), "editor").
+		Return(&domain.BaseError{Err: "Ревизия с таким идентификатором не существует", Code: http.StatusNotFound})
+
+	err := revisionService.RevertSong(1, 9, "editor")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*domain.BaseError).Code)
+	mockCache.AssertNotCalled(t, "Del", testifyMock.Anything)
+}
@@ -1,67 +1,122 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"net/http"
-	"net/http/httptest"
-	"net/url"
 	"song/internal/domain"
 	"song/test/mock"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
 )
 
 var (
-	mockSongRepo  *mock.MockSongRepo
-	mockCacheRepo *mock.MockCacheRepo
-	service       *SongService
+	mockSongRepo   *mock.MockSongRepo
+	mockCacheRepo  *mock.MockCacheRepo
+	mockAlbumRepo  *mock.MockAlbumRepo
+	mockEnrichment *mock.MockEnrichmentClient
+	service        *SongService
 )
 
 func init() {
 	mockSongRepo = new(mock.MockSongRepo)
 	mockCacheRepo = new(mock.MockCacheRepo)
-	service = NewSongService(mockCacheRepo, mockSongRepo)
+	mockAlbumRepo = new(mock.MockAlbumRepo)
+	mockEnrichment = new(mock.MockEnrichmentClient)
+	service = NewSongService(newSongCache(mockCacheRepo), mockSongRepo, mockAlbumRepo, mockEnrichment)
 }
 
 // Тест для метода GetLib
 func TestSongService_GetLib(t *testing.T) {
-	filter := domain.Song{}
-	page := domain.Page(1)
-	expectedSongs := &[]domain.Song{{ID: 1, Name: "Test Song"}}
+	filter := domain.LibFilter{}
+	pagination := domain.Pagination{Limit: domain.DefaultLibLimit}
+	expectedSongs := &domain.LibPage{Items: []domain.Song{{ID: 1, Name: "Test Song"}}}
 
-	mockSongRepo.On("GetLib", filter, page).Return(expectedSongs, nil)
+	key, _ := libKey(1, filter, pagination)
 
-	result, err := service.GetLib(filter, page)
+	mockCacheRepo.On("Get", libraryGenerationKey).Return([]byte("1"), nil)
+	mockCacheRepo.On("Get", key).Return([]byte(nil), nil)
+	mockCacheRepo.On("Set", key, testifyMock.Anything, time.Minute).Return(nil)
+	mockSongRepo.On("GetLib", filter, pagination).Return(expectedSongs, nil)
+
+	result, err := service.GetLib(filter, pagination)
 
 	assert.Nil(t, err)
 	assert.Equal(t, expectedSongs, result)
 	mockSongRepo.AssertExpectations(t)
 }
 
+// Тест для метода ExportLib - песни из репозитория записываются в формате NDJSON
+func TestSongService_ExportLib(t *testing.T) {
+	filter := domain.LibFilter{Song: domain.Song{Group: "Export"}, Fields: []string{domain.FieldID, domain.FieldName}}
+
+	mockSongRepo.On("ExportLib", testifyMock.Anything, filter, testifyMock.Anything).
+		Run(func(args testifyMock.Arguments) {
+			fn := args.Get(2).(func(domain.Song) error)
+			_ = fn(domain.Song{ID: 1, Name: "Hysteria"})
+			_ = fn(domain.Song{ID: 2, Name: "Uprising"})
+		}).
+		Return(nil)
+
+	var out bytes.Buffer
+	count, err := service.ExportLib(context.Background(), filter, domain.FormatNDJSON, &out)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "{\"id\":1,\"name\":\"Hysteria\"}\n{\"id\":2,\"name\":\"Uprising\"}\n", out.String())
+}
+
 // Тест для метода GetText
 func TestSongService_GetText(t *testing.T) {
 	id := domain.Id(1)
-	page := domain.Page(1)
-	text := domain.SongText("Verse 1\\n\\nVerse 2")
-	expectedText := "Verse 1"
+	page := domain.Page(2)
+	lyrics, _ := json.Marshal(domain.ParseLyrics("Verse 1\n\n[Chorus]\nLine 1\nLine 2"))
 
-	mockCacheRepo.On("GetText", id).Return(&text, nil)
+	mockCacheRepo.On("Get", songVersionKey(id)).Return([]byte("1"), nil)
+	mockCacheRepo.On("Get", lyricsKey(1, id)).Return(lyrics, nil).Once()
 
 	result, err := service.GetText(uint64(id), page)
 
 	assert.Nil(t, err)
-	assert.Equal(t, expectedText, *result)
+	assert.Equal(t, &domain.TextSection{
+		SongID: 1,
+		Index:  2,
+		Total:  2,
+		Type:   domain.SectionChorus,
+		Label:  "Chorus",
+		Text:   "Line 1\nLine 2",
+		Lines:  []string{"Line 1", "Line 2"},
+	}, result)
 	mockCacheRepo.AssertExpectations(t)
 }
 
+// Тест для метода GetText с неположительным номером страницы
+func TestSongService_GetText_InvalidPage(t *testing.T) {
+	id := domain.Id(1)
+	lyrics, _ := json.Marshal(domain.ParseLyrics("Verse 1"))
+
+	mockCacheRepo.On("Get", songVersionKey(id)).Return([]byte("1"), nil)
+	mockCacheRepo.On("Get", lyricsKey(1, id)).Return(lyrics, nil).Once()
+
+	_, err := service.GetText(uint64(id), 0)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.InputDataError).Code)
+}
+
 // Тест для метода DelSong
 func TestSongService_DelSong(t *testing.T) {
 	id := domain.Id(1)
 
-	mockSongRepo.On("DelSong", id).Return(nil)
-	mockCacheRepo.On("DelKey", id).Return(nil)
+	mockSongRepo.On("DelSong", id, "editor").Return(nil)
+	mockCacheRepo.On("Incr", songVersionKey(id)).Return(int64(2), nil)
+	mockCacheRepo.On("Incr", libraryGenerationKey).Return(int64(1), nil)
 
-	err := service.DelSong(uint64(id))
+	err := service.DelSong(uint64(id), "editor")
 
 	assert.Nil(t, err)
 	mockSongRepo.AssertExpectations(t)
@@ -72,75 +127,37 @@ func TestSongService_DelSong(t *testing.T) {
 func TestSongService_ChangeSong(t *testing.T) {
 	song := domain.Song{ID: 1, Name: "Updated Song"}
 
-	mockSongRepo.On("ChangeSong", song).Return(nil)
+	mockSongRepo.On("ChangeSong", song, "editor").Return(nil)
+	mockCacheRepo.On("Incr", songVersionKey(song.ID)).Return(int64(2), nil)
+	mockCacheRepo.On("Incr", libraryGenerationKey).Return(int64(1), nil)
 
-	err := service.ChangeSong(song)
+	err := service.ChangeSong(song, "editor")
 
 	assert.Nil(t, err)
 	mockSongRepo.AssertExpectations(t)
 }
 
-// Тест для метода CreateSong - различные ошибки
+// Тест для метода CreateSong - ошибка API передается без изменений, песня не создается
 func TestSongService_CreateSong_Errors(t *testing.T) {
-	data := domain.SongDataByUser{
-		Group: "Test Group",
-		Name:  "Test Song",
-	}
-	apiUrl, _ := url.Parse("http://example.com")
-
 	tests := []struct {
-		name           string
-		apiUrl         *url.URL
-		mockHTTPServer func() *httptest.Server
-		expectedError  *domain.RequestError
+		name string
+		code int
 	}{
-		{
-			name:   "Ошибка создания запроса",
-			apiUrl: nil,
-			expectedError: &domain.RequestError{
-				Err:  "error creating request - test error",
-				Code: http.StatusInternalServerError,
-			},
-		},
-		{
-			name: "Ошибка выполнения запроса",
-			mockHTTPServer: func() *httptest.Server {
-				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					w.WriteHeader(http.StatusInternalServerError)
-				}))
-			},
-			expectedError: &domain.RequestError{
-				Err:  "error making request - test error",
-				Code: http.StatusInternalServerError,
-			},
-		},
-		{
-			name: "Ошибка декодирования ответа",
-			mockHTTPServer: func() *httptest.Server {
-				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					w.WriteHeader(http.StatusOK)
-					_, _ = w.Write([]byte("invalid json"))
-				}))
-			},
-			expectedError: &domain.RequestError{
-				Err:  "error decoding response - test error",
-				Code: http.StatusInternalServerError,
-			},
-		},
+		{name: "Песня не найдена", code: http.StatusNotFound},
+		{name: "API отклонил данные", code: http.StatusBadRequest},
+		{name: "API недоступен", code: http.StatusServiceUnavailable},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.mockHTTPServer != nil {
-				ts := tt.mockHTTPServer()
-				defer ts.Close()
-				apiUrl, _ = url.Parse(ts.URL)
-			}
+			data := domain.SongDataByUser{Group: "Test Group", Name: tt.name}
+			mockEnrichment.On("SongInfo", data).Return((*domain.SongDataByApi)(nil), &domain.RequestError{Code: tt.code})
 
-			_, err := service.CreateSong(data, apiUrl)
+			_, err := service.CreateSong(data, "editor")
 
 			assert.NotNil(t, err)
-			assert.Equal(t, tt.expectedError.Code, err.(*domain.RequestError).Code)
+			assert.Equal(t, tt.code, err.(*domain.RequestError).Code)
+			mockSongRepo.AssertNotCalled(t, "CreateSong", testifyMock.Anything, testifyMock.Anything)
 		})
 	}
 }
@@ -1,10 +1,10 @@
 package services
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
-	"net/url"
 	"song/internal/domain"
 	"song/internal/interfaces"
 	"song/internal/presentation/logger"
@@ -13,124 +13,148 @@ import (
 
 // SongService - сервис для работы с песнями
 type SongService struct {
-	cacheDb interfaces.CacheRepo
-	song    interfaces.SongRepo
+	cacheDb    *SongCache
+	song       interfaces.SongRepo
+	album      interfaces.AlbumRepo
+	enrichment interfaces.EnrichmentClient
 }
 
 // NewSongService создает новый объект SongService
-func NewSongService(cache interfaces.CacheRepo, song interfaces.SongRepo) *SongService {
+func NewSongService(cache *SongCache, song interfaces.SongRepo, album interfaces.AlbumRepo, enrichment interfaces.EnrichmentClient) *SongService {
 	return &SongService{
-		cacheDb: cache,
-		song:    song,
+		cacheDb:    cache,
+		song:       song,
+		album:      album,
+		enrichment: enrichment,
 	}
 }
 
-// GetLib получает библиотеку песен
+// GetLib получает страницу библиотеки песен
 // filter - фильтр для песен
-// page - номер страницы для пагинации
-func (s *SongService) GetLib(filter domain.Song, page domain.Page) (*[]domain.Song, error) {
-	songs, err := s.song.GetLib(filter, page)
+// pagination - параметры пагинации
+func (s *SongService) GetLib(filter domain.LibFilter, pagination domain.Pagination) (*domain.LibPage, error) {
+	return s.cacheDb.Lib(filter, pagination, func() (*domain.LibPage, error) {
+		return s.song.GetLib(filter, pagination)
+	})
+}
+
+// GetSong получает песню со всеми полями по идентификатору
+// id - идентификатор песни
+func (s *SongService) GetSong(id domain.Id) (*domain.Song, error) {
+	return s.cacheDb.Song(id, func() (*domain.Song, error) {
+		return s.song.GetSong(id)
+	})
+}
+
+// ExportLib записывает песни библиотеки по фильтру в w по мере чтения из базы данных
+// и возвращает их число. Если ошибка случилась после первой песни, файл в w остается незавершенным
+// filter - фильтр для песен, Fields - записываемые поля
+// format - формат файла: csv, json или ndjson
+func (s *SongService) ExportLib(ctx context.Context, filter domain.LibFilter, format string, w io.Writer) (int, error) {
+	encoder, err := domain.NewSongEncoder(w, format, filter.Fields)
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+
+	count := 0
+	err = s.song.ExportLib(ctx, filter, func(song domain.Song) error {
+		if err := encoder.Encode(song); err != nil {
+			return exportError(err)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if err = encoder.Close(); err != nil {
+		return count, exportError(err)
 	}
-	return songs, nil
+
+	return count, nil
 }
 
-// GetText получает текст песни по идентификатору и номеру страницы
+// exportError возвращает ошибку записи файла экспорта
+func exportError(err error) error {
+	return &domain.BaseError{
+		Err:  fmt.Sprintf("Export writing error - %v", err),
+		Code: http.StatusInternalServerError,
+	}
+}
+
+// GetText получает раздел текста песни по идентификатору и номеру страницы
 // id - идентификатор песни
-// page - номер страницы для текста песни
-func (s *SongService) GetText(id uint64, page domain.Page) (*domain.SongText, error) {
-	text, err := s.cacheDb.GetText(id)
+// page - номер раздела текста песни, начиная с 1
+func (s *SongService) GetText(id uint64, page domain.Page) (*domain.TextSection, error) {
+	lyrics, err := cachedLyrics(s.cacheDb, s.song, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if text == nil {
-		text, err = s.song.GetText(id)
-		if err != nil {
-			return nil, err
-		}
+	return textSection(id, *lyrics, page)
+}
 
-		err = s.cacheDb.CreateKey(id, *text)
-		if err != nil {
-			return nil, err
-		}
-	}
+// cachedLyrics получает разделы текста песни из кэша, при промахе - из базы данных с записью в кэш
+func cachedLyrics(cache *SongCache, song interfaces.SongRepo, id domain.Id) (*domain.Lyrics, error) {
+	return cache.Lyrics(id, func() (*domain.Lyrics, error) {
+		return song.GetLyrics(id)
+	})
+}
 
-	verses := strings.Split(*text, "\\n\\n")
-	fmt.Println(verses)
-	n := len(verses)
-	if page > n {
+// textSection возвращает раздел текста с номером page и его положением в тексте
+func textSection(id domain.Id, lyrics domain.Lyrics, page domain.Page) (*domain.TextSection, error) {
+	n := len(lyrics)
+	if page < 1 || page > n {
 		return nil, &domain.InputDataError{
-			Err:  fmt.Sprintf("This song have only %d verses", n),
+			Err:  fmt.Sprintf("This song have only %d sections", n),
 			Code: http.StatusBadRequest,
 		}
 	}
 
-	return &verses[page-1], nil
+	section := lyrics[page-1]
+	return &domain.TextSection{
+		SongID: id,
+		Index:  section.Index,
+		Total:  n,
+		Type:   section.Type,
+		Label:  section.Label,
+		Text:   strings.Join(section.Lines, "\n"),
+		Lines:  section.Lines,
+	}, nil
 }
 
 // DelSong удаляет песню по идентификатору
 // id - идентификатор песни
-func (s *SongService) DelSong(id uint64) error {
-	err := s.song.DelSong(id)
-	if err != nil {
-		return err
-	}
-
-	err = s.cacheDb.DelKey(id)
+// actor - автор изменения
+func (s *SongService) DelSong(id uint64, actor string) error {
+	err := s.song.DelSong(id, actor)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return s.cacheDb.InvalidateSong(id)
 }
 
 // ChangeSong изменяет данные песни
 // song - объект песни с новыми данными
-func (s *SongService) ChangeSong(song domain.Song) error {
-	return s.song.ChangeSong(song)
-}
-
-// CreateSong создает новую песню
-// data - данные о песне, предоставленные пользователем
-// apiUrl - URL API для получения дополнительных данных о песне
-func (s *SongService) CreateSong(data domain.SongDataByUser, apiUrl *url.URL) (*domain.Id, error) {
-	apiUrl.Path += "/info"
-	params := url.Values{}
-	params.Add("group", data.Group)
-	params.Add("song", data.Name)
-	apiUrl.RawQuery = params.Encode()
-
-	req, err := http.NewRequest("GET", apiUrl.String(), nil)
+// actor - автор изменения
+func (s *SongService) ChangeSong(song domain.Song, actor string) error {
+	err := s.song.ChangeSong(song, actor)
 	if err != nil {
-		return nil, &domain.RequestError{
-			Err:  fmt.Sprintf("error creating request - %v", err),
-			Code: http.StatusInternalServerError,
-		}
+		return err
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, &domain.RequestError{
-			Err:  fmt.Sprintf("error making request - %v", err),
-			Code: http.StatusInternalServerError,
-		}
-	}
+	return s.cacheDb.InvalidateSong(song.ID)
+}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			logger.Logger.Error(fmt.Sprintf("Error closing response body: %v", err))
-		}
-	}()
-	var apiData domain.SongDataByApi
-	err = json.NewDecoder(resp.Body).Decode(&apiData)
+// CreateSong создает новую песню с дополнительными данными из API
+// data - данные о песне, предоставленные пользователем
+// actor - автор изменения
+func (s *SongService) CreateSong(data domain.SongDataByUser, actor string) (*domain.Id, error) {
+	apiData, err := s.enrichment.SongInfo(data)
 	if err != nil {
-		return nil, &domain.RequestError{
-			Err:  fmt.Sprintf("error decoding response - %v", err),
-			Code: http.StatusInternalServerError,
-		}
+		return nil, err
 	}
 
 	id, err := s.song.CreateSong(domain.Song{
@@ -139,7 +163,21 @@ func (s *SongService) CreateSong(data domain.SongDataByUser, apiUrl *url.URL) (*
 		Date:  apiData.Date,
 		Text:  apiData.Text,
 		Link:  apiData.Link,
-	})
+
+		Sources: apiData.Sources,
+	}, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiData.Album != nil && apiData.Album.Title != "" {
+		err = s.album.AttachSong(*id, *apiData.Album)
+		if err != nil {
+			logger.Logger.Warn(fmt.Sprintf("Song %d was created without album %q: %v", *id, apiData.Album.Title, err))
+		}
+	}
+
+	err = s.cacheDb.InvalidateLibrary()
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +185,11 @@ func (s *SongService) CreateSong(data domain.SongDataByUser, apiUrl *url.URL) (*
 	return id, nil
 }
 
+// CacheStats возвращает статистику попаданий по уровням кэша
+func (s *SongService) CacheStats() []domain.CacheTierStats {
+	return s.cacheDb.Stats()
+}
+
 func (s *SongService) Close() error {
 	return s.cacheDb.Close()
 }
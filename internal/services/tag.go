@@ -0,0 +1,93 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"strings"
+)
+
+// TagService - сервис для работы с жанрами и метками
+type TagService struct {
+	cacheDb *SongCache
+	tag     interfaces.TagRepo
+}
+
+// NewTagService создает новый объект TagService
+func NewTagService(cache *SongCache, tag interfaces.TagRepo) *TagService {
+	return &TagService{
+		cacheDb: cache,
+		tag:     tag,
+	}
+}
+
+// GetTags получает жанры или метки с количеством песен
+// kind - вид классификатора
+func (s *TagService) GetTags(kind string) (*[]domain.Tag, error) {
+	return s.tag.GetTags(kind)
+}
+
+// AttachTags привязывает жанры и метки к набору песен
+// binding - песни и названия жанров и меток
+func (s *TagService) AttachTags(binding domain.TagBinding) error {
+	binding, err := normalizeBinding(binding)
+	if err != nil {
+		return err
+	}
+
+	err = s.tag.AttachTags(binding)
+	if err != nil {
+		return err
+	}
+
+	return s.cacheDb.InvalidateLibrary()
+}
+
+// DetachTags отвязывает жанры и метки от набора песен
+// binding - песни и названия жанров и меток
+func (s *TagService) DetachTags(binding domain.TagBinding) error {
+	binding, err := normalizeBinding(binding)
+	if err != nil {
+		return err
+	}
+
+	err = s.tag.DetachTags(binding)
+	if err != nil {
+		return err
+	}
+
+	return s.cacheDb.InvalidateLibrary()
+}
+
+// normalizeBinding убирает пустые и повторяющиеся без учета регистра названия
+// и проверяет, что указаны песни и хотя бы один жанр или метка
+func normalizeBinding(binding domain.TagBinding) (domain.TagBinding, error) {
+	binding.Tags = uniqueNames(binding.Tags)
+	binding.Genres = uniqueNames(binding.Genres)
+
+	if len(binding.Songs) == 0 || len(binding.Tags)+len(binding.Genres) == 0 {
+		return binding, &domain.InputDataError{
+			Err:  "songs and at least one tag or genre are required",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	return binding, nil
+}
+
+// uniqueNames возвращает непустые названия без повторов без учета регистра
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var result []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+
+	return result
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для метода AttachTags с повторяющимися названиями
+func TestTagService_AttachTags(t *testing.T) {
+	mockTagRepo := new(mock.MockTagRepo)
+	mockCache := new(mock.MockCacheRepo)
+	tagService := NewTagService(newSongCache(mockCache), mockTagRepo)
+
+	mockCache.On("Incr", libraryGenerationKey).Return(int64(1), nil)
+	mockTagRepo.On("AttachTags", domain.TagBinding{
+		Songs: []domain.Id{1, 2},
+		Tags:  []string{"wedding", "cleared-for-broadcast"},
+	}).Return(nil)
+
+	err := tagService.AttachTags(domain.TagBinding{
+		Songs: []domain.Id{1, 2},
+		Tags:  []string{"wedding", " Wedding ", "", "cleared-for-broadcast"},
+	})
+
+	assert.Nil(t, err)
+	mockTagRepo.AssertExpectations(t)
+}
+
+// Тест для метода DetachTags без меток
+func TestTagService_DetachTags_Empty(t *testing.T) {
+	mockTagRepo := new(mock.MockTagRepo)
+	tagService := NewTagService(newSongCache(new(mock.MockCacheRepo)), mockTagRepo)
+
+	err := tagService.DetachTags(domain.TagBinding{Songs: []domain.Id{1}, Genres: []string{" "}})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.InputDataError).Code)
+	mockTagRepo.AssertNotCalled(t, "DetachTags")
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"time"
+)
+
+// MaxNextLines - наибольшее количество следующих строк в ответе
+const MaxNextLines = 50
+
+// TimingService - сервис для работы с синхронизированным текстом песен
+type TimingService struct {
+	timing interfaces.TimingRepo
+}
+
+// NewTimingService создает новый объект TimingService
+func NewTimingService(timing interfaces.TimingRepo) *TimingService {
+	return &TimingService{
+		timing: timing,
+	}
+}
+
+// ImportLRC заменяет синхронизированный текст песни строками из LRC
+// id - идентификатор песни
+// data - текст в формате LRC
+func (s *TimingService) ImportLRC(id domain.Id, data string) (int, error) {
+	lines, err := domain.ParseLRC(data)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.timing.SetTiming(id, lines)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(lines), nil
+}
+
+// ExportLRC возвращает синхронизированный текст песни в формате LRC
+// id - идентификатор песни
+func (s *TimingService) ExportLRC(id domain.Id) (string, error) {
+	lyrics, err := s.GetTiming(id)
+	if err != nil {
+		return "", err
+	}
+
+	return domain.FormatLRC(*lyrics), nil
+}
+
+// GetTiming получает синхронизированный текст песни, у песни должна быть хотя бы одна строка
+// id - идентификатор песни
+func (s *TimingService) GetTiming(id domain.Id) (*domain.TimedLyrics, error) {
+	lyrics, err := s.timing.GetTiming(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lyrics.Lines) == 0 {
+		return nil, &domain.BaseError{
+			Err:  "This song has no timed lyrics",
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return lyrics, nil
+}
+
+// LineAt возвращает строку, звучащую в момент воспроизведения, и следующие за ней
+// id - идентификатор песни
+// timeMs - позиция воспроизведения в миллисекундах
+// next - количество следующих строк
+func (s *TimingService) LineAt(id domain.Id, timeMs int64, next int) (*domain.LinePosition, error) {
+	if timeMs < 0 || next < 0 || next > MaxNextLines {
+		return nil, &domain.InputDataError{
+			Err:  fmt.Sprintf("t must not be negative and next must be between 0 and %d", MaxNextLines),
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	lyrics, err := s.GetTiming(id)
+	if err != nil {
+		return nil, err
+	}
+
+	position := domain.LineAt(lyrics.Lines, timeMs, next)
+	return &position, nil
+}
+
+// Play передает строки в send в моменты их начала, отсчитывая время от позиции fromMs.
+// Строка, звучащая в момент fromMs, передается сразу. Воспроизведение прерывается
+// отменой ctx или ошибкой send
+func (s *TimingService) Play(ctx context.Context, lines []domain.TimedLine, fromMs int64, send func(domain.TimedLine) error) error {
+	start := time.Now()
+	position := domain.LineAt(lines, fromMs, 0)
+	first := max(position.Index, 0)
+
+	for _, line := range lines[first:] {
+		wait := time.Duration(line.TimeMs-fromMs)*time.Millisecond - time.Since(start)
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		if err := send(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
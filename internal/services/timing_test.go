@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"song/internal/domain"
+	"song/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для метода ImportLRC
+func TestTimingService_ImportLRC(t *testing.T) {
+	mockTimingRepo := new(mock.MockTimingRepo)
+	timingService := NewTimingService(mockTimingRepo)
+
+	mockTimingRepo.On("SetTiming", domain.Id(1), []domain.TimedLine{{TimeMs: 1000, Text: "one"}, {TimeMs: 2000, Text: "two"}}).Return(nil)
+
+	lines, err := timingService.ImportLRC(1, "[00:02.00]two\n[00:01.00]one")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, lines)
+	mockTimingRepo.AssertExpectations(t)
+}
+
+// Тест для метода LineAt без синхронизированного текста
+func TestTimingService_LineAt_Empty(t *testing.T) {
+	mockTimingRepo := new(mock.MockTimingRepo)
+	timingService := NewTimingService(mockTimingRepo)
+
+	mockTimingRepo.On("GetTiming", domain.Id(1)).Return(&domain.TimedLyrics{SongID: 1, Lines: []domain.TimedLine{}}, nil)
+
+	_, err := timingService.LineAt(1, 0, domain.DefaultNextLines)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*domain.BaseError).Code)
+}
+
+// Тест для метода Play с начальной позицией внутри строки
+func TestTimingService_Play(t *testing.T) {
+	timingService := NewTimingService(new(mock.MockTimingRepo))
+	lines := []domain.TimedLine{{TimeMs: 0, Text: "one"}, {TimeMs: 1000, Text: "two"}, {TimeMs: 1010, Text: "three"}}
+
+	var sent []string
+	err := timingService.Play(context.Background(), lines, 1005, func(line domain.TimedLine) error {
+		sent = append(sent, line.Text)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"two", "three"}, sent)
+}
+
+// Тест для метода Play с отменой контекста
+func TestTimingService_Play_Canceled(t *testing.T) {
+	timingService := NewTimingService(new(mock.MockTimingRepo))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := timingService.Play(ctx, []domain.TimedLine{{TimeMs: 60000, Text: "late"}}, 0, func(line domain.TimedLine) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
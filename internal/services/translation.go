@@ -0,0 +1,185 @@
+package services
+
+import (
+	"net/http"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"strings"
+)
+
+// TranslationService - сервис для работы с переводами текстов песен
+type TranslationService struct {
+	cacheDb     *SongCache
+	song        interfaces.SongRepo
+	translation interfaces.TranslationRepo
+}
+
+// NewTranslationService создает новый объект TranslationService
+func NewTranslationService(cache *SongCache, song interfaces.SongRepo, translation interfaces.TranslationRepo) *TranslationService {
+	return &TranslationService{
+		cacheDb:     cache,
+		song:        song,
+		translation: translation,
+	}
+}
+
+// GetLangs получает языки, на которые переведен текст песни
+// id - идентификатор песни
+func (s *TranslationService) GetLangs(id domain.Id) (*[]string, error) {
+	return s.translation.GetLangs(id)
+}
+
+// GetTranslation получает перевод текста песни на язык
+// id - идентификатор песни
+// lang - код языка BCP-47
+func (s *TranslationService) GetTranslation(id domain.Id, lang string) (*domain.Translation, error) {
+	lang, err := domain.CanonicalLang(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	translation, err := s.translation.GetTranslation(id, []string{lang})
+	if err != nil {
+		return nil, err
+	}
+
+	if translation == nil {
+		return nil, &domain.BaseError{
+			Err:  "This song has no translation to " + lang,
+			Code: http.StatusNotFound,
+		}
+	}
+
+	return translation, nil
+}
+
+// SetTranslation создает или заменяет перевод, разделы перевода выравниваются по оригиналу
+// id - идентификатор песни
+// lang - код языка BCP-47
+// text - текст перевода
+func (s *TranslationService) SetTranslation(id domain.Id, lang, text string) (*domain.Translation, error) {
+	lang, err := domain.CanonicalLang(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	text = domain.NormalizeText(text)
+	if strings.TrimSpace(text) == "" {
+		return nil, &domain.InputDataError{
+			Err:  "text is required",
+			Code: http.StatusBadRequest,
+		}
+	}
+
+	original, err := cachedLyrics(s.cacheDb, s.song, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := domain.AlignLyrics(*original, domain.ParseLyrics(text))
+	if err != nil {
+		return nil, err
+	}
+
+	translation := domain.Translation{
+		SongID:   id,
+		Lang:     lang,
+		Text:     text,
+		Sections: sections,
+	}
+	err = s.translation.SetTranslation(translation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &translation, nil
+}
+
+// DelTranslation удаляет перевод текста песни
+// id - идентификатор песни
+// lang - код языка BCP-47
+func (s *TranslationService) DelTranslation(id domain.Id, lang string) error {
+	lang, err := domain.CanonicalLang(lang)
+	if err != nil {
+		return err
+	}
+
+	return s.translation.DelTranslation(id, lang)
+}
+
+// GetText получает раздел перевода текста песни, а если перевода на язык
+// и его более общие варианты нет - раздел оригинала
+// id - идентификатор песни
+// page - номер раздела, начиная с 1
+// lang - код языка BCP-47
+func (s *TranslationService) GetText(id domain.Id, page domain.Page, lang string) (*domain.TextSection, error) {
+	original, translation, err := s.lyrics(id, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	if translation == nil {
+		return textSection(id, *original, page)
+	}
+
+	return translatedSection(id, *translation, page)
+}
+
+// GetSideBySide получает раздел оригинала вместе с соответствующим разделом перевода
+// id - идентификатор песни
+// page - номер раздела, начиная с 1
+// lang - код языка BCP-47
+func (s *TranslationService) GetSideBySide(id domain.Id, page domain.Page, lang string) (*domain.SideBySide, error) {
+	original, translation, err := s.lyrics(id, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	section, err := textSection(id, *original, page)
+	if err != nil {
+		return nil, err
+	}
+
+	result := domain.SideBySide{Original: *section}
+	// Оригинал мог измениться после перевода, тогда раздела может не быть
+	if translation != nil && page <= len(translation.Sections) {
+		result.Translation, err = translatedSection(id, *translation, page)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
+}
+
+// lyrics получает разделы оригинала и перевод на язык или его более общий вариант,
+// если перевода нет, возвращается nil
+func (s *TranslationService) lyrics(id domain.Id, lang string) (*domain.Lyrics, *domain.Translation, error) {
+	lang, err := domain.CanonicalLang(lang)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	original, err := cachedLyrics(s.cacheDb, s.song, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	translation, err := s.translation.GetTranslation(id, domain.LangFallbacks(lang))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return original, translation, nil
+}
+
+// translatedSection возвращает раздел перевода с языком перевода
+func translatedSection(id domain.Id, translation domain.Translation, page domain.Page) (*domain.TextSection, error) {
+	section, err := textSection(id, translation.Sections, page)
+	if err != nil {
+		return nil, err
+	}
+
+	section.Lang = translation.Lang
+	return section, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"song/internal/domain"
+	"song/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTranslationService создает сервис переводов с оригиналом текста в кэше
+func newTranslationService(text string) (*TranslationService, *mock.MockTranslationRepo) {
+	mockCache := new(mock.MockCacheRepo)
+	mockTranslationRepo := new(mock.MockTranslationRepo)
+	data, _ := json.Marshal(domain.ParseLyrics(text))
+	mockCache.On("Get", songVersionKey(1)).Return([]byte("1"), nil)
+	mockCache.On("Get", lyricsKey(1, 1)).Return(data, nil)
+
+	return NewTranslationService(newSongCache(mockCache), new(mock.MockSongRepo), mockTranslationRepo), mockTranslationRepo
+}
+
+// Тест для метода GetText с переводом на более общий язык
+func TestTranslationService_GetText(t *testing.T) {
+	translationService, mockTranslationRepo := newTranslationService("Line\n\n[Chorus]\nLa la")
+
+	mockTranslationRepo.On("GetTranslation", domain.Id(1), []string{"pt-BR", "pt"}).Return(&domain.Translation{
+		SongID:   1,
+		Lang:     "pt",
+		Sections: domain.Lyrics{{Index: 1, Type: domain.SectionVerse, Lines: []string{"Linha"}}},
+	}, nil)
+
+	result, err := translationService.GetText(1, 1, "pt-br")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "pt", result.Lang)
+	assert.Equal(t, "Linha", result.Text)
+	mockTranslationRepo.AssertExpectations(t)
+}
+
+// Тест для метода GetSideBySide без перевода
+func TestTranslationService_GetSideBySide_Fallback(t *testing.T) {
+	translationService, mockTranslationRepo := newTranslationService("Line\n\n[Chorus]\nLa la")
+
+	mockTranslationRepo.On("GetTranslation", domain.Id(1), []string{"de"}).Return((*domain.Translation)(nil), nil)
+
+	result, err := translationService.GetSideBySide(1, 2, "de")
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.SectionChorus, result.Original.Type)
+	assert.Nil(t, result.Translation)
+}
+
+// Тест для метода SetTranslation с другим количеством разделов
+func TestTranslationService_SetTranslation_Misaligned(t *testing.T) {
+	translationService, mockTranslationRepo := newTranslationService("Line\n\n[Chorus]\nLa la")
+
+	_, err := translationService.SetTranslation(1, "ru", "Строка")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*domain.InputDataError).Code)
+	mockTranslationRepo.AssertNotCalled(t, "SetTranslation")
+}
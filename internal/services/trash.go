@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"song/internal/domain"
+	"song/internal/interfaces"
+	"song/internal/presentation/logger"
+	"time"
+)
+
+// RetentionActor - автор изменений при окончательном удалении песен по сроку хранения
+const RetentionActor = "retention"
+
+// TrashService - сервис для работы с корзиной песен
+type TrashService struct {
+	cacheDb   *SongCache
+	trash     interfaces.TrashRepo
+	retention time.Duration
+}
+
+// NewTrashService создает новый объект TrashService
+// retention - срок хранения песен в корзине, 0 - песни хранятся до ручного удаления
+func NewTrashService(cache *SongCache, trash interfaces.TrashRepo, retention time.Duration) *TrashService {
+	return &TrashService{
+		cacheDb:   cache,
+		trash:     trash,
+		retention: retention,
+	}
+}
+
+// GetTrash получает песни из корзины со временем их окончательного удаления
+// page - номер страницы для пагинации
+func (s *TrashService) GetTrash(page domain.Page) (*[]domain.TrashedSong, error) {
+	songs, err := s.trash.GetTrash(page)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.retention > 0 {
+		for i := range *songs {
+			purgeAt := (*songs)[i].DeletedAt.Add(s.retention)
+			(*songs)[i].PurgeAt = &purgeAt
+		}
+	}
+
+	return songs, nil
+}
+
+// RestoreSong возвращает песню из корзины в библиотеку
+// id - идентификатор песни
+// actor - автор изменения
+func (s *TrashService) RestoreSong(id domain.Id, actor string) error {
+	err := s.trash.RestoreSong(id, actor)
+	if err != nil {
+		return err
+	}
+
+	return s.cacheDb.InvalidateSong(id)
+}
+
+// PurgeSong окончательно удаляет песню из корзины
+// id - идентификатор песни
+// actor - автор изменения
+func (s *TrashService) PurgeSong(id domain.Id, actor string) error {
+	return s.trash.PurgeSong(id, actor)
+}
+
+// PurgeExpired окончательно удаляет песни, срок хранения которых в корзине истек
+// now - текущее время
+func (s *TrashService) PurgeExpired(now time.Time) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	return s.trash.PurgeExpired(now.Add(-s.retention), RetentionActor)
+}
+
+// RunPurge периодически удаляет песни с истекшим сроком хранения до отмены ctx
+// interval - период между запусками очистки
+func (s *TrashService) RunPurge(ctx context.Context, interval time.Duration) {
+	if s.retention <= 0 || interval <= 0 {
+		logger.Logger.Info("Trash purge is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeExpired(time.Now())
+		if err != nil {
+			logger.Logger.Error(fmt.Sprintf("Trash purge error - %v", err))
+		} else if purged > 0 {
+			logger.Logger.Info(fmt.Sprintf("%d songs were purged from trash", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
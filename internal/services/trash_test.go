@@ -0,0 +1,69 @@
+package services
+
+import (
+	"song/internal/domain"
+	"song/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Тест для метода GetTrash со временем окончательного удаления
+func TestTrashService_GetTrash(t *testing.T) {
+	mockTrashRepo := new(mock.MockTrashRepo)
+	trashService := NewTrashService(newSongCache(new(mock.MockCacheRepo)), mockTrashRepo, 24*time.Hour)
+	deletedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	mockTrashRepo.On("GetTrash", 1).Return(&[]domain.TrashedSong{{Song: domain.Song{ID: 1}, DeletedAt: deletedAt}}, nil)
+
+	songs, err := trashService.GetTrash(1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, deletedAt.Add(24*time.Hour), *(*songs)[0].PurgeAt)
+	mockTrashRepo.AssertExpectations(t)
+}
+
+// Тест для метода PurgeExpired
+func TestTrashService_PurgeExpired(t *testing.T) {
+	mockTrashRepo := new(mock.MockTrashRepo)
+	trashService := NewTrashService(newSongCache(new(mock.MockCacheRepo)), mockTrashRepo, 24*time.Hour)
+	now := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+
+	mockTrashRepo.On("PurgeExpired", now.Add(-24*time.Hour), RetentionActor).Return(int64(3), nil)
+
+	purged, err := trashService.PurgeExpired(now)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), purged)
+	mockTrashRepo.AssertExpectations(t)
+}
+
+// Тест для метода PurgeExpired без срока хранения
+func TestTrashService_PurgeExpired_Disabled(t *testing.T) {
+	mockTrashRepo := new(mock.MockTrashRepo)
+	trashService := NewTrashService(newSongCache(new(mock.MockCacheRepo)), mockTrashRepo, 0)
+
+	purged, err := trashService.PurgeExpired(time.Now())
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), purged)
+	mockTrashRepo.AssertNotCalled(t, "PurgeExpired")
+}
+
+// Тест для метода RestoreSong со сбросом кэша
+func TestTrashService_RestoreSong(t *testing.T) {
+	mockTrashRepo := new(mock.MockTrashRepo)
+	mockCache := new(mock.MockCacheRepo)
+	trashService := NewTrashService(newSongCache(mockCache), mockTrashRepo, 0)
+
+	mockTrashRepo.On("RestoreSong", domain.Id(1), "editor").Return(nil)
+	mockCache.On("Incr", songVersionKey(1)).Return(int64(2), nil)
+	mockCache.On("Incr", libraryGenerationKey).Return(int64(1), nil)
+
+	err := trashService.RestoreSong(1, "editor")
+
+	assert.Nil(t, err)
+	mockTrashRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyRepo - mock для интерфейса APIKeyRepo
+type MockAPIKeyRepo struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepo) CreateAPIKey(key domain.APIKey, hash string) (*domain.APIKey, error) {
+	args := m.Called(key, hash)
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) GetAPIKeys(userID domain.Id) (*[]domain.APIKey, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*[]domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) UseAPIKey(hash string) (*domain.Principal, error) {
+	args := m.Called(hash)
+	return args.Get(0).(*domain.Principal), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) RevokeAPIKey(id, userID domain.Id, anyUser bool) error {
+	args := m.Called(id, userID, anyUser)
+	return args.Error(0)
+}
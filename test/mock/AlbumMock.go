@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAlbumRepo - mock для интерфейса AlbumRepo
+type MockAlbumRepo struct {
+	mock.Mock
+}
+
+func (m *MockAlbumRepo) GetAlbums(artistID domain.Id, page domain.Page) (*[]domain.Album, error) {
+	args := m.Called(artistID, page)
+	return args.Get(0).(*[]domain.Album), args.Error(1)
+}
+
+func (m *MockAlbumRepo) GetAlbum(id domain.Id) (*domain.Album, error) {
+	args := m.Called(id)
+	return args.Get(0).(*domain.Album), args.Error(1)
+}
+
+func (m *MockAlbumRepo) CreateAlbum(album domain.Album) (*domain.Id, error) {
+	args := m.Called(album)
+	return args.Get(0).(*domain.Id), args.Error(1)
+}
+
+func (m *MockAlbumRepo) ChangeAlbum(album domain.Album) error {
+	args := m.Called(album)
+	return args.Error(0)
+}
+
+func (m *MockAlbumRepo) DelAlbum(id domain.Id) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAlbumRepo) SetTracks(id domain.Id, songs []domain.Id) error {
+	args := m.Called(id, songs)
+	return args.Error(0)
+}
+
+func (m *MockAlbumRepo) AttachSong(songID domain.Id, album domain.AlbumByApi) error {
+	args := m.Called(songID, album)
+	return args.Error(0)
+}
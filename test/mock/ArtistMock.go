@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockArtistRepo - mock для интерфейса ArtistRepo
+type MockArtistRepo struct {
+	mock.Mock
+}
+
+func (m *MockArtistRepo) GetArtists(page domain.Page) (*[]domain.Artist, error) {
+	args := m.Called(page)
+	return args.Get(0).(*[]domain.Artist), args.Error(1)
+}
+
+func (m *MockArtistRepo) GetArtist(id domain.Id) (*domain.Artist, error) {
+	args := m.Called(id)
+	return args.Get(0).(*domain.Artist), args.Error(1)
+}
+
+func (m *MockArtistRepo) CreateArtist(artist domain.Artist) (*domain.Id, error) {
+	args := m.Called(artist)
+	return args.Get(0).(*domain.Id), args.Error(1)
+}
+
+func (m *MockArtistRepo) ChangeArtist(artist domain.Artist, actor string) ([]domain.Id, error) {
+	args := m.Called(artist, actor)
+	return args.Get(0).([]domain.Id), args.Error(1)
+}
+
+func (m *MockArtistRepo) DelArtist(id domain.Id) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
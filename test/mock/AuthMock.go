@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"song/internal/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuthRepo - mock для интерфейса AuthRepo
+type MockAuthRepo struct {
+	mock.Mock
+}
+
+func (m *MockAuthRepo) CreateUser(user domain.User) (*domain.User, error) {
+	args := m.Called(user)
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockAuthRepo) GetUser(id domain.Id) (*domain.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockAuthRepo) GetUserByName(username string) (*domain.User, error) {
+	args := m.Called(username)
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockAuthRepo) SetUserRole(id domain.Id, role string) error {
+	args := m.Called(id, role)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepo) CreateSession(session domain.Session) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepo) GetSession(id string) (*domain.Session, error) {
+	args := m.Called(id)
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+func (m *MockAuthRepo) RotateSession(id, oldJTI, newJTI string, expiresAt time.Time) (bool, error) {
+	args := m.Called(id, oldJTI, newJTI, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthRepo) RevokeSession(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepo) RevokeUserSessions(userID domain.Id) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"song/internal/domain"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -11,21 +12,30 @@ type MockCacheRepo struct {
 	mock.Mock
 }
 
-func (m *MockCacheRepo) CreateKey(id domain.Id, text domain.SongText) error {
-	args := m.Called(id, text)
-	return args.Error(0)
+func (m *MockCacheRepo) Get(key string) ([]byte, error) {
+	args := m.Called(key)
+	return args.Get(0).([]byte), args.Error(1)
 }
 
-func (m *MockCacheRepo) GetText(id domain.Id) (*domain.SongText, error) {
-	args := m.Called(id)
-	return args.Get(0).(*domain.SongText), args.Error(1)
+func (m *MockCacheRepo) Set(key string, value []byte, ttl time.Duration) error {
+	args := m.Called(key, value, ttl)
+	return args.Error(0)
 }
 
-func (m *MockCacheRepo) DelKey(id domain.Id) error {
-	args := m.Called(id)
+func (m *MockCacheRepo) Del(keys ...string) error {
+	args := m.Called(keys)
 	return args.Error(0)
 }
 
+func (m *MockCacheRepo) Incr(key string) (int64, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCacheRepo) Stats() []domain.CacheTierStats {
+	return nil
+}
+
 func (m *MockCacheRepo) Close() error {
 	return nil
 }
@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEnrichmentClient - mock для интерфейса EnrichmentClient
+type MockEnrichmentClient struct {
+	mock.Mock
+}
+
+func (m *MockEnrichmentClient) SongInfo(data domain.SongDataByUser) (*domain.SongDataByApi, error) {
+	args := m.Called(data)
+	return args.Get(0).(*domain.SongDataByApi), args.Error(1)
+}
@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"song/internal/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEnrichmentRepo - mock для интерфейса EnrichmentRepo
+type MockEnrichmentRepo struct {
+	mock.Mock
+}
+
+func (m *MockEnrichmentRepo) CreateSongJob(song domain.Song, actor string) (*domain.EnrichmentJob, error) {
+	args := m.Called(song, actor)
+	return args.Get(0).(*domain.EnrichmentJob), args.Error(1)
+}
+
+func (m *MockEnrichmentRepo) GetJob(id uint64) (*domain.EnrichmentJob, error) {
+	args := m.Called(id)
+	return args.Get(0).(*domain.EnrichmentJob), args.Error(1)
+}
+
+func (m *MockEnrichmentRepo) ClaimJob(lease time.Duration) (*domain.EnrichmentJob, error) {
+	args := m.Called(lease)
+	return args.Get(0).(*domain.EnrichmentJob), args.Error(1)
+}
+
+func (m *MockEnrichmentRepo) CompleteJob(job domain.EnrichmentJob, info domain.SongDataByApi) error {
+	args := m.Called(job, info)
+	return args.Error(0)
+}
+
+func (m *MockEnrichmentRepo) RetryJob(id uint64, errMsg string, next time.Time) error {
+	args := m.Called(id, errMsg, next)
+	return args.Error(0)
+}
+
+func (m *MockEnrichmentRepo) FailJob(job domain.EnrichmentJob, errMsg string) error {
+	args := m.Called(job, errMsg)
+	return args.Error(0)
+}
@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockImportRepo - mock для интерфейса ImportRepo
+type MockImportRepo struct {
+	mock.Mock
+}
+
+func (m *MockImportRepo) ExistingSongs(songs []domain.SongDataByUser) ([]domain.SongDataByUser, error) {
+	args := m.Called(songs)
+	return args.Get(0).([]domain.SongDataByUser), args.Error(1)
+}
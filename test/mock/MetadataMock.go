@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMetadataProvider - mock для интерфейса MetadataProvider
+type MockMetadataProvider struct {
+	mock.Mock
+	ProviderName string
+}
+
+func (m *MockMetadataProvider) Name() string {
+	return m.ProviderName
+}
+
+func (m *MockMetadataProvider) SongInfo(data domain.SongDataByUser) (*domain.SongDataByApi, error) {
+	args := m.Called(data)
+	return args.Get(0).(*domain.SongDataByApi), args.Error(1)
+}
+
+// MockOverrideRepo - mock для интерфейса MetadataOverrideRepo
+type MockOverrideRepo struct {
+	MockMetadataProvider
+}
+
+func (m *MockOverrideRepo) GetOverrides(page domain.Page) (*[]domain.MetadataOverride, error) {
+	args := m.Called(page)
+	return args.Get(0).(*[]domain.MetadataOverride), args.Error(1)
+}
+
+func (m *MockOverrideRepo) SetOverride(override domain.MetadataOverride) (*domain.MetadataOverride, error) {
+	args := m.Called(override)
+	return args.Get(0).(*domain.MetadataOverride), args.Error(1)
+}
+
+func (m *MockOverrideRepo) DelOverride(id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,18 @@
+package mock
+
+import (
+	"song/internal/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRateLimitRepo - mock для интерфейса RateLimitRepo
+type MockRateLimitRepo struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitRepo) Allow(key string, limit domain.RateLimit, now time.Time) (*domain.RateLimitResult, error) {
+	args := m.Called(key, limit, now)
+	return args.Get(0).(*domain.RateLimitResult), args.Error(1)
+}
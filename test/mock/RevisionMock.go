@@ -0,0 +1,27 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRevisionRepo - mock для интерфейса RevisionRepo
+type MockRevisionRepo struct {
+	mock.Mock
+}
+
+func (m *MockRevisionRepo) GetRevisions(songID domain.Id) (*[]domain.Revision, error) {
+	args := m.Called(songID)
+	return args.Get(0).(*[]domain.Revision), args.Error(1)
+}
+
+func (m *MockRevisionRepo) GetRevision(songID, id domain.Id) (*domain.Revision, error) {
+	args := m.Called(songID, id)
+	return args.Get(0).(*domain.Revision), args.Error(1)
+}
+
+func (m *MockRevisionRepo) RevertSong(songID, id domain.Id, actor string) error {
+	args := m.Called(songID, id, actor)
+	return args.Error(0)
+}
@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"song/internal/domain"
 
 	"github.com/stretchr/testify/mock"
@@ -11,27 +12,37 @@ type MockSongRepo struct {
 	mock.Mock
 }
 
-func (m *MockSongRepo) GetLib(filter domain.Song, page domain.Page) (*[]domain.Song, error) {
-	args := m.Called(filter, page)
-	return args.Get(0).(*[]domain.Song), args.Error(1)
+func (m *MockSongRepo) GetLib(filter domain.LibFilter, pagination domain.Pagination) (*domain.LibPage, error) {
+	args := m.Called(filter, pagination)
+	return args.Get(0).(*domain.LibPage), args.Error(1)
 }
 
-func (m *MockSongRepo) GetText(id domain.Id) (*domain.SongText, error) {
+func (m *MockSongRepo) ExportLib(ctx context.Context, filter domain.LibFilter, fn func(domain.Song) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockSongRepo) GetSong(id domain.Id) (*domain.Song, error) {
 	args := m.Called(id)
-	return args.Get(0).(*domain.SongText), args.Error(1)
+	return args.Get(0).(*domain.Song), args.Error(1)
 }
 
-func (m *MockSongRepo) DelSong(id domain.Id) error {
+func (m *MockSongRepo) GetLyrics(id domain.Id) (*domain.Lyrics, error) {
 	args := m.Called(id)
+	return args.Get(0).(*domain.Lyrics), args.Error(1)
+}
+
+func (m *MockSongRepo) DelSong(id domain.Id, actor string) error {
+	args := m.Called(id, actor)
 	return args.Error(0)
 }
 
-func (m *MockSongRepo) ChangeSong(song domain.Song) error {
-	args := m.Called(song)
+func (m *MockSongRepo) ChangeSong(song domain.Song, actor string) error {
+	args := m.Called(song, actor)
 	return args.Error(0)
 }
 
-func (m *MockSongRepo) CreateSong(song domain.Song) (*domain.Id, error) {
-	args := m.Called(song)
+func (m *MockSongRepo) CreateSong(song domain.Song, actor string) (*domain.Id, error) {
+	args := m.Called(song, actor)
 	return args.Get(0).(*domain.Id), args.Error(1)
 }
@@ -1,7 +1,6 @@
 package mock
 
 import (
-	"net/url"
 	"song/internal/domain"
 
 	"github.com/stretchr/testify/mock"
@@ -12,27 +11,27 @@ type MockSongService struct {
 	mock.Mock
 }
 
-func (m *MockSongService) GetLib(filter domain.Song, page domain.Page) (*[]domain.Song, error) {
-	args := m.Called(filter, page)
-	return args.Get(0).(*[]domain.Song), args.Error(1)
+func (m *MockSongService) GetLib(filter domain.LibFilter, pagination domain.Pagination) (*domain.LibPage, error) {
+	args := m.Called(filter, pagination)
+	return args.Get(0).(*domain.LibPage), args.Error(1)
 }
 
-func (m *MockSongService) GetText(id uint64, page domain.Page) (*domain.SongText, error) {
+func (m *MockSongService) GetText(id uint64, page domain.Page) (*domain.TextSection, error) {
 	args := m.Called(id, page)
-	return args.Get(0).(*domain.SongText), args.Error(1)
+	return args.Get(0).(*domain.TextSection), args.Error(1)
 }
 
-func (m *MockSongService) DelSong(id uint64) error {
-	args := m.Called(id)
+func (m *MockSongService) DelSong(id uint64, actor string) error {
+	args := m.Called(id, actor)
 	return args.Error(0)
 }
 
-func (m *MockSongService) ChangeSong(song domain.Song) error {
-	args := m.Called(song)
+func (m *MockSongService) ChangeSong(song domain.Song, actor string) error {
+	args := m.Called(song, actor)
 	return args.Error(0)
 }
 
-func (m *MockSongService) CreateSong(data domain.SongDataByUser, apiUrl *url.URL) (*domain.Id, error) {
-	args := m.Called(data, apiUrl)
+func (m *MockSongService) CreateSong(data domain.SongDataByUser, actor string) (*domain.Id, error) {
+	args := m.Called(data, actor)
 	return args.Get(0).(*domain.Id), args.Error(1)
 }
@@ -0,0 +1,27 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagRepo - mock для интерфейса TagRepo
+type MockTagRepo struct {
+	mock.Mock
+}
+
+func (m *MockTagRepo) GetTags(kind string) (*[]domain.Tag, error) {
+	args := m.Called(kind)
+	return args.Get(0).(*[]domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepo) AttachTags(binding domain.TagBinding) error {
+	args := m.Called(binding)
+	return args.Error(0)
+}
+
+func (m *MockTagRepo) DetachTags(binding domain.TagBinding) error {
+	args := m.Called(binding)
+	return args.Error(0)
+}
@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTimingRepo - mock для интерфейса TimingRepo
+type MockTimingRepo struct {
+	mock.Mock
+}
+
+func (m *MockTimingRepo) GetTiming(songID domain.Id) (*domain.TimedLyrics, error) {
+	args := m.Called(songID)
+	return args.Get(0).(*domain.TimedLyrics), args.Error(1)
+}
+
+func (m *MockTimingRepo) SetTiming(songID domain.Id, lines []domain.TimedLine) error {
+	args := m.Called(songID, lines)
+	return args.Error(0)
+}
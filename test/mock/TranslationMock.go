@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"song/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTranslationRepo - mock для интерфейса TranslationRepo
+type MockTranslationRepo struct {
+	mock.Mock
+}
+
+func (m *MockTranslationRepo) GetLangs(songID domain.Id) (*[]string, error) {
+	args := m.Called(songID)
+	return args.Get(0).(*[]string), args.Error(1)
+}
+
+func (m *MockTranslationRepo) GetTranslation(songID domain.Id, langs []string) (*domain.Translation, error) {
+	args := m.Called(songID, langs)
+	return args.Get(0).(*domain.Translation), args.Error(1)
+}
+
+func (m *MockTranslationRepo) SetTranslation(translation domain.Translation) error {
+	args := m.Called(translation)
+	return args.Error(0)
+}
+
+func (m *MockTranslationRepo) DelTranslation(songID domain.Id, lang string) error {
+	args := m.Called(songID, lang)
+	return args.Error(0)
+}
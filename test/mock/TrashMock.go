@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"song/internal/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTrashRepo - mock для интерфейса TrashRepo
+type MockTrashRepo struct {
+	mock.Mock
+}
+
+func (m *MockTrashRepo) GetTrash(page domain.Page) (*[]domain.TrashedSong, error) {
+	args := m.Called(page)
+	return args.Get(0).(*[]domain.TrashedSong), args.Error(1)
+}
+
+func (m *MockTrashRepo) RestoreSong(id domain.Id, actor string) error {
+	args := m.Called(id, actor)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepo) PurgeSong(id domain.Id, actor string) error {
+	args := m.Called(id, actor)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepo) PurgeExpired(before time.Time, actor string) (int64, error) {
+	args := m.Called(before, actor)
+	return args.Get(0).(int64), args.Error(1)
+}
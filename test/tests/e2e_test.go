@@ -1,13 +1,15 @@
 package tests
 
 import (
+	"bytes"
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"song/internal/domain"
+	"song/internal/musicinfo"
 	"song/internal/presentation/logger"
 	"song/internal/presentation/postgres"
 	"song/internal/presentation/realization"
@@ -32,8 +34,8 @@ var (
 	redisPort string
 	redisPass string
 
-	apiUrl  string
-	apiPort string
+	// MusicInfo - локальная замена API с данными о песнях из test/fixtures/musicinfo.json
+	MusicInfo *musicinfo.Server
 )
 
 func init() {
@@ -53,9 +55,6 @@ func init() {
 	redisPort = os.Getenv("REDIS_PORT")
 	redisPass = os.Getenv("REDIS_PASSWORD")
 
-	apiUrl = os.Getenv("API_URL")
-	apiPort = os.Getenv("API_PORT")
-
 	Srv = StartTestServer()
 }
 
@@ -66,16 +65,46 @@ func StartTestServer() *server.Server {
 		log.Fatalf("Could not create database connection: %v", err)
 	}
 
+	// Настройка API с данными о песнях
+	fixtures, err := realization.NewCatalogProvider("../fixtures/musicinfo.json")
+	if err != nil {
+		log.Fatalf("Could not load music info fixtures: %v", err)
+	}
+	MusicInfo = musicinfo.NewServer(musicinfo.Config{}, fixtures, nil)
+	api, _ := url.Parse(httptest.NewServer(MusicInfo.Handler()).URL)
+
 	// Настройка сервисов
 	cacheRepo := realization.NewConnectRedis(redisHost, redisPort, redisPass)
+	songCache := services.NewSongCache(cacheRepo, domain.CacheConfig{TextTTL: time.Hour, SongTTL: time.Minute, LibTTL: time.Minute})
 	songRepo := &realization.SongRepo{}
-	songService := services.NewSongService(cacheRepo, songRepo)
+	albumRepo := realization.NewAlbumRepo()
+	enrichment := realization.NewEnrichmentClient(domain.EnrichmentConfig{BaseURL: api, Timeout: 5 * time.Second})
+	songService := services.NewSongService(songCache, songRepo, albumRepo, enrichment)
 
 	// Запуск сервера
-	srv := server.NewServer()
+	srv, _ := server.NewServer(nil)
 	go func() {
-		api, _ := url.Parse(fmt.Sprintf("%s:%s", apiUrl, apiPort))
-		if err := srv.Start(songService, api, "8080"); err != nil {
+		if err := srv.Start(server.Services{
+			Song:     songService,
+			Artist:   services.NewArtistService(songCache, realization.NewArtistRepo()),
+			Album:    services.NewAlbumService(songCache, albumRepo),
+			Tag:      services.NewTagService(songCache, realization.NewTagRepo()),
+			Revision: services.NewRevisionService(songCache, realization.NewRevisionRepo()),
+			Trash:    services.NewTrashService(songCache, realization.NewTrashRepo(), 0),
+			Enrichment: services.NewEnrichmentService(songCache, realization.NewEnrichmentRepo(), albumRepo, enrichment, domain.EnrichmentJobConfig{
+				MaxAttempts:  1,
+				PollInterval: time.Second,
+				Lease:        time.Minute,
+			}),
+			Override:    services.NewOverrideService(realization.NewOverrideRepo()),
+			Timing:      services.NewTimingService(realization.NewTimingRepo()),
+			Translation: services.NewTranslationService(songCache, songRepo, realization.NewTranslationRepo()),
+			Auth: services.NewAuthService(realization.NewAuthRepo(), realization.NewAPIKeyRepo(), nil, domain.AuthConfig{
+				Secret:     []byte("e2e secret"),
+				AccessTTL:  time.Minute,
+				RefreshTTL: time.Hour,
+			}),
+		}, "8080"); err != nil {
 			log.Fatalf("Could not start server: %v", err)
 		}
 	}()
@@ -106,9 +135,51 @@ func TestGetLib(t *testing.T) {
 	assert.NotEmpty(t, result)
 }
 
+// login регистрирует тестового пользователя, если его нет, и возвращает токен доступа
+func login(t *testing.T) string {
+	credentials, err := json.Marshal(domain.Credentials{Username: "e2e-user", Password: "e2e-password"})
+	assert.NoError(t, err)
+
+	resp, err := http.Post("http://localhost:8080/auth/register", "application/json", bytes.NewReader(credentials))
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	resp, err = http.Post("http://localhost:8080/auth/login", "application/json", bytes.NewReader(credentials))
+	assert.NoError(t, err)
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			return
+		}
+	}()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var tokens domain.TokenPair
+	err = json.NewDecoder(resp.Body).Decode(&tokens)
+	assert.NoError(t, err)
+
+	return tokens.AccessToken
+}
+
+func TestDelSong_Unauthorized(t *testing.T) {
+	req, err := http.NewRequest("DELETE", "http://localhost:8080/song?id=1", nil)
+	assert.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			return
+		}
+	}()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
 func TestDelSong(t *testing.T) {
 	req, err := http.NewRequest("DELETE", "http://localhost:8080/song?id=1", nil)
 	assert.NoError(t, err)
+	req.Header.Set("Authorization", domain.TokenBearer+" "+login(t))
 
 	resp, err := http.DefaultClient.Do(req)
 	assert.NoError(t, err)
@@ -120,3 +191,29 @@ func TestDelSong(t *testing.T) {
 	}()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
+
+func TestCreateSong(t *testing.T) {
+	MusicInfo.Reset()
+	body, err := json.Marshal(domain.SongDataByUser{Group: "Queen", Name: "Bohemian Rhapsody"})
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("POST", "http://localhost:8080/song", bytes.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", domain.TokenBearer+" "+login(t))
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			return
+		}
+	}()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	requests := MusicInfo.Requests()
+	if assert.Len(t, requests, 1) {
+		assert.Equal(t, "Queen", requests[0].Group)
+		assert.Equal(t, http.StatusOK, requests[0].Status)
+	}
+}